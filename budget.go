@@ -0,0 +1,227 @@
+package emaillistchecker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BudgetLimit caps the credits spent within a sliding time window
+type BudgetLimit struct {
+	// Tag restricts the limit to calls tagged with WithTag; empty applies to every call
+	Tag string
+	// Window is the sliding period the limit covers; zero means the lifetime of the Budget
+	Window time.Duration
+	// Credits is the most that may be spent within the window
+	Credits int
+}
+
+// Budget tracks estimated credit consumption and refuses calls that would
+// exceed a limit or the account balance. Verifications and finder lookups are
+// counted as one credit each and batches as one per address.
+type Budget struct {
+	limits  []BudgetLimit
+	refresh time.Duration
+	now     func() time.Time
+
+	mu        sync.Mutex
+	spends    []*budgetSpend
+	balance   int
+	balanceAt time.Time
+	hasBal    bool
+}
+
+// budgetSpend is an estimated spend, reserved before the call and settled after it
+type budgetSpend struct {
+	at      time.Time
+	tag     string
+	credits int
+}
+
+// NewBudget creates a budget with the given limits. If refresh is positive the
+// balance is fetched with GetCredits when it is older than refresh, and calls
+// that cost more than the remaining balance are refused too.
+func NewBudget(refresh time.Duration, limits ...BudgetLimit) *Budget {
+	return &Budget{
+		limits:  limits,
+		refresh: refresh,
+		now:     time.Now,
+	}
+}
+
+// SetBudget makes the client check b before every verify, batch and finder call.
+// Pass nil to remove the budget.
+func (c *Client) SetBudget(b *Budget) {
+	c.budget = b
+}
+
+// Spent returns the estimated credits spent by calls tagged tag (all calls if
+// tag is empty) within the last window (the lifetime of the budget if zero)
+func (b *Budget) Spent(tag string, window time.Duration) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spentLocked(tag, window, b.now())
+}
+
+// Balance returns the estimated balance: the last balance fetched from the API
+// minus credits spent since. ok is false until a balance has been fetched.
+func (b *Budget) Balance() (balance int, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.balance, b.hasBal
+}
+
+// BudgetExceededError is returned, before any request is sent, when a call
+// would exceed a budget limit or the remaining balance
+type BudgetExceededError struct {
+	Operation string
+	Tag       string
+	Requested int
+	Available int
+	// Limit is the limit that would be exceeded, or nil if the balance is too low
+	Limit *BudgetLimit
+}
+
+func (e *BudgetExceededError) Error() string {
+	if e.Limit == nil {
+		return fmt.Sprintf("budget exceeded: %s needs %d credits but the balance is %d", e.Operation, e.Requested, e.Available)
+	}
+
+	scope := "all calls"
+	if e.Limit.Tag != "" {
+		scope = fmt.Sprintf("tag %q", e.Limit.Tag)
+	}
+	window := "in total"
+	if e.Limit.Window > 0 {
+		window = "per " + e.Limit.Window.String()
+	}
+	return fmt.Sprintf("budget exceeded: %s needs %d credits but only %d of %d are left for %s %s",
+		e.Operation, e.Requested, e.Available, e.Limit.Credits, scope, window)
+}
+
+// reserveCredits checks the client's budget for an operation costing credits
+// and records the spend. The returned spend must be settled with the actual
+// cost, or zero if the call failed. It is nil when no budget is set.
+func (c *Client) reserveCredits(op string, credits int) (*budgetSpend, error) {
+	b := c.budget
+	if b == nil {
+		return nil, nil
+	}
+
+	if b.needsRefresh() {
+		if data, err := c.GetCredits(); err == nil {
+			if balance, ok := creditBalance(data); ok {
+				b.setBalance(balance)
+			}
+		}
+	}
+
+	return b.reserve(op, TagFromContext(c.context()), credits)
+}
+
+// settle replaces a reservation with the actual cost of the call
+func (c *Client) settle(spend *budgetSpend, credits int) {
+	if spend == nil {
+		return
+	}
+	b := c.budget
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.hasBal {
+		b.balance += spend.credits - credits
+	}
+	spend.credits = credits
+}
+
+func (b *Budget) needsRefresh() bool {
+	if b.refresh <= 0 {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.hasBal || b.now().Sub(b.balanceAt) >= b.refresh
+}
+
+func (b *Budget) setBalance(balance int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.balance = balance
+	b.balanceAt = b.now()
+	b.hasBal = true
+}
+
+func (b *Budget) reserve(op, tag string, credits int) (*budgetSpend, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.prune(now)
+
+	for i := range b.limits {
+		limit := &b.limits[i]
+		if limit.Tag != "" && limit.Tag != tag {
+			continue
+		}
+		available := limit.Credits - b.spentLocked(limit.Tag, limit.Window, now)
+		if credits > available {
+			if available < 0 {
+				available = 0
+			}
+			return nil, &BudgetExceededError{Operation: op, Tag: tag, Requested: credits, Available: available, Limit: limit}
+		}
+	}
+
+	if b.hasBal && credits > b.balance {
+		return nil, &BudgetExceededError{Operation: op, Tag: tag, Requested: credits, Available: b.balance}
+	}
+
+	spend := &budgetSpend{at: now, tag: tag, credits: credits}
+	b.spends = append(b.spends, spend)
+	if b.hasBal {
+		b.balance -= credits
+	}
+	return spend, nil
+}
+
+func (b *Budget) spentLocked(tag string, window time.Duration, now time.Time) int {
+	total := 0
+	for _, spend := range b.spends {
+		if tag != "" && spend.tag != tag {
+			continue
+		}
+		if window > 0 && !spend.at.After(now.Add(-window)) {
+			continue
+		}
+		total += spend.credits
+	}
+	return total
+}
+
+// prune drops spends that no windowed limit can see any more. Spends are kept
+// forever if any limit has no window.
+func (b *Budget) prune(now time.Time) {
+	var longest time.Duration
+	for _, limit := range b.limits {
+		if limit.Window <= 0 {
+			return
+		}
+		if limit.Window > longest {
+			longest = limit.Window
+		}
+	}
+
+	cutoff := now.Add(-longest)
+	kept := b.spends[:0]
+	for _, spend := range b.spends {
+		if spend.at.After(cutoff) {
+			kept = append(kept, spend)
+		}
+	}
+	b.spends = kept
+}
+
+// creditBalance reads the balance from a GetCredits response
+func creditBalance(data map[string]interface{}) (int, bool) {
+	return numberField(data, "balance")
+}
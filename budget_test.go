@@ -0,0 +1,102 @@
+package emaillistchecker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+func TestBudgetRejectsOverLimit(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	client.SetBudget(emaillistchecker.NewBudget(0, emaillistchecker.BudgetLimit{Credits: 2}))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Verify("jane@example.com", nil, true); err != nil {
+			t.Fatalf("Verify %d: %v", i, err)
+		}
+	}
+	sent := len(srv.Requests())
+
+	_, err := client.Verify("jane@example.com", nil, true)
+	var budgetErr *emaillistchecker.BudgetExceededError
+	if !errors.As(err, &budgetErr) {
+		t.Fatalf("Verify over the limit: err = %v, want *BudgetExceededError", err)
+	}
+	if budgetErr.Limit == nil || budgetErr.Requested != 1 || budgetErr.Available != 0 {
+		t.Errorf("BudgetExceededError = %+v", budgetErr)
+	}
+	if n := len(srv.Requests()); n != sent {
+		t.Errorf("sent %d requests after the budget was exceeded", n-sent)
+	}
+}
+
+func TestBudgetTagLimit(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	budget := emaillistchecker.NewBudget(0, emaillistchecker.BudgetLimit{Tag: "import", Credits: 1})
+	client.SetBudget(budget)
+
+	tagged := client.WithContext(emaillistchecker.WithTag(context.Background(), "import"))
+	if _, err := tagged.Verify("a@example.com", nil, true); err != nil {
+		t.Fatalf("Verify tagged: %v", err)
+	}
+	if _, err := tagged.Verify("b@example.com", nil, true); err == nil {
+		t.Error("second tagged Verify: want a budget error")
+	}
+	// Untagged calls are not limited
+	if _, err := client.Verify("c@example.com", nil, true); err != nil {
+		t.Errorf("Verify untagged: %v", err)
+	}
+	if got := budget.Spent("import", 0); got != 1 {
+		t.Errorf("Spent(import) = %d, want 1", got)
+	}
+	if got := budget.Spent("", 0); got != 2 {
+		t.Errorf("Spent() = %d, want 2", got)
+	}
+}
+
+func TestBudgetRejectsOverBalance(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	srv.SetCredits(2)
+	client := srv.Client()
+	client.SetBudget(emaillistchecker.NewBudget(time.Minute))
+
+	_, err := client.VerifyBatch([]string{"a@example.com", "b@example.com", "c@example.com"}, "test", "", true)
+	var budgetErr *emaillistchecker.BudgetExceededError
+	if !errors.As(err, &budgetErr) {
+		t.Fatalf("VerifyBatch: err = %v, want *BudgetExceededError", err)
+	}
+	if budgetErr.Limit != nil || budgetErr.Requested != 3 || budgetErr.Available != 2 {
+		t.Errorf("BudgetExceededError = %+v", budgetErr)
+	}
+	if srv.Credits() != 2 {
+		t.Errorf("credits = %d, want 2", srv.Credits())
+	}
+}
+
+func TestBudgetReleasesFailedCalls(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	budget := emaillistchecker.NewBudget(0, emaillistchecker.BudgetLimit{Credits: 1})
+	client.SetBudget(budget)
+
+	srv.Fail("/verify", emaillistcheckertest.Fault{Status: 503, Times: 1})
+	if _, err := client.Verify("jane@example.com", nil, true); err == nil {
+		t.Fatal("Verify with a fault: want an error")
+	}
+	if got := budget.Spent("", 0); got != 0 {
+		t.Errorf("Spent after a failed call = %d, want 0", got)
+	}
+	if _, err := client.Verify("jane@example.com", nil, true); err != nil {
+		t.Errorf("Verify after a failed call: %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package emaillistchecker
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// VerifyCache stores verification results so repeated Verify calls for the same
+// address do not spend credits. Implementations must be safe for concurrent use.
+type VerifyCache interface {
+	Get(email string) (*VerifyResponse, bool)
+	Set(email string, result *VerifyResponse)
+}
+
+// SetCache makes Verify consult cache before calling the API and store every result in it.
+// Pass nil to disable caching.
+func (c *Client) SetCache(cache VerifyCache) {
+	c.cache = cache
+}
+
+// cacheResult stores a verification result if a cache is configured
+func (c *Client) cacheResult(email string, result *VerifyResponse) {
+	if c.cache != nil {
+		c.cache.Set(email, result)
+	}
+}
+
+// MemoryCache is an in-memory VerifyCache whose entries expire after a TTL.
+// Addresses are matched case-insensitively.
+type MemoryCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+	hits    int
+	misses  int
+}
+
+type memoryCacheEntry struct {
+	result  VerifyResponse
+	expires time.Time
+}
+
+// NewMemoryCache creates an in-memory cache. A ttl of zero keeps entries forever.
+func NewMemoryCache(ttl time.Duration) *MemoryCache {
+	return &MemoryCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]memoryCacheEntry),
+	}
+}
+
+// Get returns a copy of the cached result for email
+func (m *MemoryCache) Get(email string) (*VerifyResponse, bool) {
+	key := cacheKey(email)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if ok && !entry.expires.IsZero() && !m.now().Before(entry.expires) {
+		delete(m.entries, key)
+		ok = false
+	}
+	if !ok {
+		m.misses++
+		return nil, false
+	}
+
+	m.hits++
+	result := entry.result
+	return &result, true
+}
+
+// Set stores a copy of result for email
+func (m *MemoryCache) Set(email string, result *VerifyResponse) {
+	entry := memoryCacheEntry{result: *result}
+	if m.ttl > 0 {
+		entry.expires = m.now().Add(m.ttl)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[cacheKey(email)] = entry
+}
+
+// Stats returns the number of cache hits and misses so far
+func (m *MemoryCache) Stats() (hits, misses int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.hits, m.misses
+}
+
+// cacheKey normalizes an address for cache lookups
+func cacheKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
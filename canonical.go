@@ -0,0 +1,168 @@
+package emaillistchecker
+
+import (
+	"strings"
+	"sync"
+)
+
+// CanonicalRule describes which spellings of a local part a mail provider
+// delivers to the same mailbox
+type CanonicalRule struct {
+	// IgnoreDots drops dots from the local part, as Gmail does
+	IgnoreDots bool
+	// TagSeparator starts a sub-address tag that is dropped, usually "+"; empty keeps tags
+	TagSeparator string
+	// CaseSensitive keeps the case of the local part, which is folded by default
+	CaseSensitive bool
+	// Domain replaces the domain when the provider has aliases, e.g. googlemail.com for gmail.com
+	Domain string
+}
+
+// builtinCanonicalRules are the rules of popular providers
+var builtinCanonicalRules = func() map[string]CanonicalRule {
+	rules := make(map[string]CanonicalRule)
+	gmail := CanonicalRule{IgnoreDots: true, TagSeparator: "+", Domain: "gmail.com"}
+	rules["gmail.com"] = gmail
+	rules["googlemail.com"] = gmail
+
+	plus := CanonicalRule{TagSeparator: "+"}
+	for _, domain := range []string{
+		"outlook.com", "outlook.de", "outlook.fr", "hotmail.com", "hotmail.co.uk", "hotmail.de",
+		"hotmail.fr", "hotmail.it", "live.com", "live.co.uk", "live.fr", "msn.com",
+		"icloud.com", "me.com", "mac.com", "fastmail.com", "fastmail.fm",
+		"protonmail.com", "protonmail.ch", "proton.me", "pm.me", "yandex.com", "yandex.ru",
+	} {
+		rules[domain] = plus
+	}
+	return rules
+}()
+
+// defaultCanonicalizer backs Canonicalize
+var defaultCanonicalizer = NewCanonicalizer()
+
+// Canonicalizer reduces addresses to a canonical form, so spellings that reach
+// the same mailbox compare equal. Rules are looked up by domain; other domains
+// only have their case folded.
+type Canonicalizer struct {
+	mu          sync.RWMutex
+	rules       map[string]CanonicalRule
+	defaultRule CanonicalRule
+}
+
+// NewCanonicalizer creates a canonicalizer with the built-in rules for Gmail,
+// Outlook, iCloud, Fastmail, Proton and Yandex
+func NewCanonicalizer() *Canonicalizer {
+	rules := make(map[string]CanonicalRule, len(builtinCanonicalRules))
+	for domain, rule := range builtinCanonicalRules {
+		rules[domain] = rule
+	}
+	return &Canonicalizer{rules: rules}
+}
+
+// DefaultCanonicalizer returns the canonicalizer used by Canonicalize.
+// Rules set on it affect every caller of Canonicalize.
+func DefaultCanonicalizer() *Canonicalizer {
+	return defaultCanonicalizer
+}
+
+// Canonicalize returns the canonical form of email using DefaultCanonicalizer,
+// e.g. John.Doe+promo@googlemail.com becomes johndoe@gmail.com
+func Canonicalize(email string) string {
+	return defaultCanonicalizer.Canonicalize(email)
+}
+
+// SetRule sets the rule for a domain, replacing any built-in rule
+func (cz *Canonicalizer) SetRule(domain string, rule CanonicalRule) {
+	cz.mu.Lock()
+	defer cz.mu.Unlock()
+	cz.rules[normalizeDomain(domain)] = rule
+}
+
+// SetDefaultRule sets the rule for domains without their own, for example to
+// strip "+" tags everywhere
+func (cz *Canonicalizer) SetDefaultRule(rule CanonicalRule) {
+	cz.mu.Lock()
+	defer cz.mu.Unlock()
+	cz.defaultRule = rule
+}
+
+// Canonicalize returns the canonical form of email. The domain is always
+// lowercased, and converted to ASCII when it is an IDN; the local part is
+// normalized to NFC. Addresses without a local part and domain are only
+// trimmed and lowercased.
+func (cz *Canonicalizer) Canonicalize(email string) string {
+	email = strings.TrimSpace(email)
+	at := strings.LastIndex(email, "@")
+	if at < 1 || at == len(email)-1 {
+		return strings.ToLower(email)
+	}
+	local, domain := NormalizeNFC(email[:at]), normalizeDomain(email[at+1:])
+
+	cz.mu.RLock()
+	rule, ok := cz.rules[domain]
+	if !ok {
+		rule = cz.defaultRule
+	}
+	cz.mu.RUnlock()
+
+	if rule.TagSeparator != "" {
+		if i := strings.Index(local, rule.TagSeparator); i > 0 {
+			local = local[:i]
+		}
+	}
+	if rule.IgnoreDots {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+	if !rule.CaseSensitive {
+		local = strings.ToLower(local)
+	}
+	if rule.Domain != "" {
+		domain = rule.Domain
+	}
+	return local + "@" + domain
+}
+
+// SetCanonicalizer makes VerifyBatch, VerifyMany and the Verify cache treat
+// addresses with the same canonical form as one, so each mailbox is verified
+// and paid for once. Results are still reported under every input. Pass nil
+// to compare addresses case-insensitively only.
+func (c *Client) SetCanonicalizer(cz *Canonicalizer) {
+	c.canonicalizer = cz
+}
+
+// dedupeKey returns the key addresses are deduplicated on
+func (c *Client) dedupeKey(email string) string {
+	if c.canonicalizer != nil {
+		return c.canonicalizer.Canonicalize(email)
+	}
+	return cacheKey(email)
+}
+
+// cacheAddress returns the address results are cached under
+func (c *Client) cacheAddress(email string) string {
+	if c.canonicalizer != nil {
+		return c.canonicalizer.Canonicalize(email)
+	}
+	return email
+}
+
+// dedupeAddresses keeps the first address of each canonical form and maps
+// the others to it
+func (c *Client) dedupeAddresses(emails []string) (unique []string, aliases map[string]string) {
+	first := make(map[string]string)
+	for _, email := range emails {
+		key := c.dedupeKey(email)
+		if kept, ok := first[key]; ok {
+			if email != kept {
+				if aliases == nil {
+					aliases = make(map[string]string)
+				}
+				aliases[email] = kept
+			}
+			continue
+		}
+		first[key] = email
+		unique = append(unique, email)
+	}
+	return unique, aliases
+}
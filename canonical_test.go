@@ -0,0 +1,51 @@
+package emaillistchecker
+
+import "testing"
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"John.Doe+promo@gmail.com", "johndoe@gmail.com"},
+		{"johndoe@googlemail.com", "johndoe@gmail.com"},
+		{" JohnDoe@GMail.com ", "johndoe@gmail.com"},
+		{"jane+news@outlook.com", "jane@outlook.com"},
+		{"+jane@outlook.com", "+jane@outlook.com"},
+		{"Jane.Doe+news@example.com", "jane.doe+news@example.com"},
+		{"Jörg@Bücher.de", "jörg@xn--bcher-kva.de"},
+		{"jo\u0308rg@xn--bcher-kva.de", "jörg@xn--bcher-kva.de"},
+		{"Not An Address", "not an address"},
+	}
+	for _, tt := range tests {
+		if got := Canonicalize(tt.email); got != tt.want {
+			t.Errorf("Canonicalize(%q) = %q, want %q", tt.email, got, tt.want)
+		}
+	}
+}
+
+func TestCanonicalizerRules(t *testing.T) {
+	cz := NewCanonicalizer()
+	cz.SetRule("Example.com", CanonicalRule{TagSeparator: "-", CaseSensitive: true})
+	cz.SetDefaultRule(CanonicalRule{TagSeparator: "+"})
+
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"Jane-news@example.com", "Jane@example.com"},
+		{"Jane+news@example.com", "Jane+news@example.com"},
+		{"Jane+news@example.org", "jane@example.org"},
+		{"John.Doe+promo@gmail.com", "johndoe@gmail.com"},
+	}
+	for _, tt := range tests {
+		if got := cz.Canonicalize(tt.email); got != tt.want {
+			t.Errorf("Canonicalize(%q) = %q, want %q", tt.email, got, tt.want)
+		}
+	}
+
+	// The package-level rules are not affected
+	if got := Canonicalize("Jane+news@example.org"); got != "jane+news@example.org" {
+		t.Errorf("Canonicalize after SetDefaultRule on another canonicalizer = %q", got)
+	}
+}
@@ -0,0 +1,384 @@
+// Package cassette records EmailListChecker API exchanges to a file and replays
+// them later, so integration tests run deterministically without network access.
+//
+// Record once against the real API:
+//
+//	rec := cassette.NewRecorder("testdata/verify.json", nil, cassette.Options{})
+//	client.SetTransport(rec)
+//	// ... exercise the client ...
+//	err := rec.Save()
+//
+// Then replay in CI:
+//
+//	rep, err := cassette.Load("testdata/verify.json", cassette.Options{})
+//	client.SetTransport(rep)
+//
+// Recorded exchanges never contain the API key: the Authorization header is
+// replaced before saving. Email addresses are pseudonymised by default and
+// further redaction rules can be configured. The same redaction is applied to
+// requests before they are matched, so replay works with the original inputs.
+// Pseudonymisation only applies on disk: a Replayer remembers the addresses
+// sent in requests and turns their pseudonyms back into them in every later
+// response body, so results still carry the inputs (as ExpandResults needs).
+// Addresses that were never sent stay pseudonymised.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+)
+
+// redactedAuthorization replaces the Authorization header in recordings
+const redactedAuthorization = "Bearer REDACTED"
+
+// Rule replaces every match of Pattern in URLs, header values and bodies
+type Rule struct {
+	Pattern *regexp.Regexp
+	Replace string
+}
+
+// Options configures redaction and matching
+type Options struct {
+	// Rules are applied after email redaction, in order
+	Rules []Rule
+	// KeepEmails disables the default email pseudonymisation
+	KeepEmails bool
+	// EmailReplacer overrides how addresses are pseudonymised. The default keeps
+	// the domain and replaces the local part with a short hash, so the same input
+	// always maps to the same recorded address.
+	EmailReplacer func(email string) string
+}
+
+// Cassette is the on-disk recording
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one request/response exchange
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// Response is a recorded response
+type Response struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that forwards requests and records the exchanges
+type Recorder struct {
+	path string
+	next http.RoundTripper
+	opts Options
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder creates a recorder writing to path on Save. A nil next uses http.DefaultTransport.
+func NewRecorder(path string, next http.RoundTripper, opts Options) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{path: path, next: next, opts: opts}
+}
+
+// RoundTrip implements http.RoundTripper. The request is forwarded as a clone
+// with a copy of its body, so the caller's request is left untouched.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	forwarded := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		forwarded.Body = io.NopCloser(bytes.NewReader(reqBody))
+		forwarded.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(reqBody)), nil
+		}
+		forwarded.ContentLength = int64(len(reqBody))
+	}
+
+	resp, err := r.next.RoundTrip(forwarded)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     r.opts.redact(req.URL.String()),
+			Headers: r.opts.redactHeaders(req.Header),
+			Body:    r.opts.redact(normalizeBody(req.Header, reqBody)),
+		},
+		Response: Response{
+			Status:  resp.StatusCode,
+			Headers: r.opts.redactHeaders(resp.Header),
+			Body:    r.opts.redact(string(respBody)),
+		},
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Save writes the recorded interactions to the cassette file
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// Replayer is an http.RoundTripper that answers requests from a cassette
+type Replayer struct {
+	opts Options
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+	// emails maps the pseudonyms of addresses sent in requests back to them
+	emails map[string]string
+}
+
+// Load reads a cassette file for replay
+func Load(path string, opts Options) (*Replayer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: %w", err)
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("cassette: failed to parse %s: %w", path, err)
+	}
+
+	return &Replayer{
+		opts:         opts,
+		interactions: c.Interactions,
+		used:         make([]bool, len(c.Interactions)),
+		emails:       make(map[string]string),
+	}, nil
+}
+
+// MismatchError is returned when a request matches no unused recorded interaction
+type MismatchError struct {
+	Method string
+	URL    string
+	Body   string
+}
+
+func (e *MismatchError) Error() string {
+	msg := fmt.Sprintf("cassette: no recorded interaction for %s %s", e.Method, e.URL)
+	if e.Body != "" {
+		msg += " with body " + e.Body
+	}
+	return msg
+}
+
+// RoundTrip implements http.RoundTripper. Requests are matched on method, path,
+// query and body; each recorded interaction is used at most once, in order.
+// Pseudonyms of the addresses sent so far are restored in the response body.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	url := r.opts.redact(req.URL.String())
+	normalized := r.opts.redact(normalizeBody(req.Header, body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.opts.learnEmails(r.emails, req.URL.String()+"\n"+string(body))
+
+	for i, interaction := range r.interactions {
+		if r.used[i] || !matches(interaction.Request, req.Method, url, normalized) {
+			continue
+		}
+		r.used[i] = true
+
+		recorded := interaction.Response
+		recorded.Body = restoreEmails(recorded.Body, r.emails)
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+			StatusCode:    recorded.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        recorded.Headers.Clone(),
+			Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+			ContentLength: int64(len(recorded.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, &MismatchError{Method: req.Method, URL: url, Body: normalized}
+}
+
+// Unused returns the recorded interactions that were never replayed
+func (r *Replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Interaction
+	for i, interaction := range r.interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// matches compares a recorded request with an incoming one
+func matches(recorded Request, method, url, body string) bool {
+	if recorded.Method != method {
+		return false
+	}
+	if stripHost(recorded.URL) != stripHost(url) {
+		return false
+	}
+	return canonicalJSON(recorded.Body) == canonicalJSON(body)
+}
+
+// stripHost removes scheme and host so recordings replay against any base URL
+func stripHost(url string) string {
+	if i := strings.Index(url, "://"); i >= 0 {
+		rest := url[i+3:]
+		if j := strings.Index(rest, "/"); j >= 0 {
+			return rest[j:]
+		}
+		return "/"
+	}
+	return url
+}
+
+// canonicalJSON re-encodes JSON bodies so key order and whitespace do not affect matching
+func canonicalJSON(body string) string {
+	var v interface{}
+	if err := json.Unmarshal([]byte(body), &v); err != nil {
+		return body
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return string(data)
+}
+
+// normalizeBody replaces the random multipart boundary with a fixed one
+func normalizeBody(header http.Header, body []byte) string {
+	_, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err == nil && params["boundary"] != "" {
+		return strings.ReplaceAll(string(body), params["boundary"], "BOUNDARY")
+	}
+	return string(body)
+}
+
+// readBody reads and closes a body
+func readBody(body io.ReadCloser) ([]byte, error) {
+	if body == nil || body == http.NoBody {
+		return nil, nil
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// redact applies email pseudonymisation and the configured rules
+func (o Options) redact(s string) string {
+	if !o.KeepEmails {
+		replace := o.EmailReplacer
+		if replace == nil {
+			replace = emaillistchecker.MaskEmail
+		}
+		s = emaillistchecker.MaskEmailsFunc(s, replace)
+	}
+	for _, rule := range o.Rules {
+		s = rule.Pattern.ReplaceAllString(s, rule.Replace)
+	}
+	return s
+}
+
+// learnEmails records the pseudonym of every address found in s
+func (o Options) learnEmails(emails map[string]string, s string) {
+	if o.KeepEmails {
+		return
+	}
+	replace := o.EmailReplacer
+	if replace == nil {
+		replace = emaillistchecker.MaskEmail
+	}
+	emaillistchecker.MaskEmailsFunc(s, func(email string) string {
+		if pseudonym := replace(email); pseudonym != email {
+			emails[pseudonym] = email
+		}
+		return email
+	})
+}
+
+// restoreEmails replaces known pseudonyms in s with their addresses
+func restoreEmails(s string, emails map[string]string) string {
+	if len(emails) == 0 {
+		return s
+	}
+	pairs := make([]string, 0, 2*len(emails))
+	for pseudonym, email := range emails {
+		pairs = append(pairs, pseudonym, email)
+	}
+	return strings.NewReplacer(pairs...).Replace(s)
+}
+
+// redactHeaders copies headers, replacing credentials and redacting values
+func (o Options) redactHeaders(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for key, values := range h {
+		for _, v := range values {
+			if strings.EqualFold(key, "Authorization") {
+				v = redactedAuthorization
+			} else {
+				v = o.redact(v)
+			}
+			out.Add(key, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,117 @@
+package cassette_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/cassette"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	inputs := []string{"jane@example.com", "undeliverable@example.com"}
+
+	rec := cassette.NewRecorder(path, nil, cassette.Options{})
+	client := srv.Client()
+	client.SetTransport(rec)
+	if _, err := client.Verify("john@example.com", nil, true); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	batch, err := client.VerifyBatch(inputs, "test", "", true)
+	if err != nil {
+		t.Fatalf("VerifyBatch: %v", err)
+	}
+	srv.Advance(time.Hour)
+	if _, err := client.GetBatchVerifyResults(batch, "all"); err != nil {
+		t.Fatalf("GetBatchVerifyResults: %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"john@", "jane@", "undeliverable@", emaillistcheckertest.APIKey} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q", secret)
+		}
+	}
+
+	// Replay against an unreachable base URL
+	rep, err := cassette.Load(path, cassette.Options{})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	client = emaillistchecker.NewClientWithConfig("other_key", "http://127.0.0.1:1", time.Second)
+	client.SetTransport(rep)
+
+	result, err := client.Verify("john@example.com", nil, true)
+	if err != nil || result.Email != "john@example.com" {
+		t.Errorf("replayed Verify = %+v, %v, want the original address", result, err)
+	}
+	batch, err = client.VerifyBatch(inputs, "test", "", true)
+	if err != nil {
+		t.Fatalf("replayed VerifyBatch: %v", err)
+	}
+	results, err := client.GetBatchVerifyResults(batch, "all")
+	if err != nil {
+		t.Fatalf("replayed GetBatchVerifyResults: %v", err)
+	}
+	if len(results) != 2 || results[0].Email != inputs[0] || results[1].Result != "undeliverable" {
+		t.Errorf("replayed results = %+v, want one per input", results)
+	}
+	if unused := rep.Unused(); len(unused) != 0 {
+		t.Errorf("%d interactions were not replayed", len(unused))
+	}
+
+	if _, err := client.Verify("other@example.com", nil, true); err == nil {
+		t.Error("Verify of an unrecorded address succeeded, want a MismatchError")
+	}
+}
+
+// bodyTracker notices when a transport closes the caller's body
+type bodyTracker struct {
+	*strings.Reader
+	closed bool
+}
+
+func (b *bodyTracker) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestRecorderLeavesRequestAlone(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+
+	rec := cassette.NewRecorder(filepath.Join(t.TempDir(), "cassette.json"), nil, cassette.Options{})
+	body := &bodyTracker{Reader: strings.NewReader(`{"email":"jane@example.com"}`)}
+	req, err := http.NewRequest("POST", srv.URL+"/verify", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+emaillistcheckertest.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := rec.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	resp.Body.Close()
+	if req.Body != body {
+		t.Error("RoundTrip replaced the caller's request body")
+	}
+	if !body.closed {
+		t.Error("RoundTrip did not close the request body")
+	}
+}
@@ -0,0 +1,334 @@
+// Package chaos provides an http.RoundTripper that injects failures into
+// EmailListChecker API traffic, for testing how services behave when the API
+// is slow or flaky.
+//
+//	transport := chaos.New(nil, 42,
+//		chaos.Rule{Path: "/verify", Latency: 200 * time.Millisecond, ServerErrorProbability: 0.1},
+//		chaos.Rule{Path: "/verify/batch", RateLimitProbability: 0.05, RateLimitBurst: 3, RetryAfter: 2},
+//	)
+//	client.SetTransport(transport)
+//
+// For each request the most specific matching rule is applied. Faults are
+// drawn from a seeded source, so a given seed produces the same sequence.
+//
+// Rules can be limited to a phase of the run with From and Until, so the
+// probabilities follow a schedule, for example a healthy minute followed by a
+// 5xx storm:
+//
+//	transport := chaos.New(nil, 42,
+//		chaos.Rule{Path: "/verify", Until: time.Minute},
+//		chaos.Rule{Path: "/verify", From: time.Minute, ServerErrorProbability: 0.8},
+//	)
+package chaos
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Fault names reported by Stats
+const (
+	FaultReset       = "reset"
+	FaultTruncated   = "truncated"
+	FaultMalformed   = "malformed"
+	FaultRateLimit   = "rate_limit"
+	FaultServerError = "server_error"
+	FaultLatency     = "latency"
+)
+
+// Rule configures the faults injected for matching requests.
+// Probabilities are between 0 and 1 and are checked in the order they are declared.
+type Rule struct {
+	// Method and Path select requests; an empty Method matches any method and
+	// Path matches the request path after the API base path by prefix.
+	Method string
+	Path   string
+
+	// From and Until restrict the rule to a phase measured from the creation
+	// of the transport; a zero Until means the rule never expires
+	From  time.Duration
+	Until time.Duration
+
+	// Latency delays every matching request by Latency plus up to Jitter
+	Latency time.Duration
+	Jitter  time.Duration
+
+	// ResetProbability fails the request with a connection reset
+	ResetProbability float64
+
+	// RateLimitProbability answers 429 with a Retry-After of RetryAfter seconds.
+	// RateLimitBurst makes the following requests fail too (default 1).
+	RateLimitProbability float64
+	RateLimitBurst       int
+	RetryAfter           int
+
+	// ServerErrorProbability answers ServerErrorStatus (default 503).
+	// ServerErrorBurst makes the following requests fail too (default 1).
+	ServerErrorProbability float64
+	ServerErrorBurst       int
+	ServerErrorStatus      int
+
+	// TruncateProbability cuts the real response body short; the response
+	// still declares the full length, so readers fail with io.ErrUnexpectedEOF
+	TruncateProbability float64
+
+	// MalformedProbability replaces the real response body with invalid JSON
+	MalformedProbability float64
+}
+
+// Transport is an http.RoundTripper that injects faults according to its rules
+type Transport struct {
+	next  http.RoundTripper
+	rules []Rule
+	start time.Time
+	now   func() time.Time
+
+	mu     sync.Mutex
+	rand   *rand.Rand
+	bursts map[int]*burst
+	stats  map[string]int
+}
+
+// burst tracks the requests still to fail after a rate limit or server error fired
+type burst struct {
+	remaining int
+	fault     string
+}
+
+// New creates a chaos transport in front of next (http.DefaultTransport if nil)
+func New(next http.RoundTripper, seed int64, rules ...Rule) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{
+		next:   next,
+		rules:  rules,
+		start:  time.Now(),
+		now:    time.Now,
+		rand:   rand.New(rand.NewSource(seed)),
+		bursts: make(map[int]*burst),
+		stats:  make(map[string]int),
+	}
+}
+
+// Stats returns how many times each fault was injected
+func (t *Transport) Stats() map[string]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make(map[string]int, len(t.stats))
+	for k, v := range t.stats {
+		stats[k] = v
+	}
+	return stats
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	index := t.match(req)
+	if index < 0 {
+		return t.next.RoundTrip(req)
+	}
+	rule := t.rules[index]
+
+	fault, delay := t.decide(index, rule)
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			closeRequestBody(req)
+			return nil, req.Context().Err()
+		}
+	}
+
+	switch fault {
+	case FaultReset, FaultRateLimit, FaultServerError:
+		// The fault answers the request, so the body is never sent on;
+		// RoundTrippers must close it
+		closeRequestBody(req)
+	}
+
+	switch fault {
+	case FaultReset:
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+
+	case FaultRateLimit:
+		header := http.Header{"Content-Type": {"application/json"}}
+		if rule.RetryAfter > 0 {
+			header.Set("Retry-After", strconv.Itoa(rule.RetryAfter))
+		}
+		return syntheticResponse(req, http.StatusTooManyRequests, header, `{"success":false,"error":"Too many requests"}`), nil
+
+	case FaultServerError:
+		status := rule.ServerErrorStatus
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+		body := fmt.Sprintf(`{"success":false,"error":%q}`, http.StatusText(status))
+		return syntheticResponse(req, status, http.Header{"Content-Type": {"application/json"}}, body), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || (fault != FaultTruncated && fault != FaultMalformed) {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if fault == FaultTruncated {
+		resp.Body = io.NopCloser(io.MultiReader(
+			bytes.NewReader(body[:len(body)/2]),
+			errReader{io.ErrUnexpectedEOF},
+		))
+		setContentLength(resp, len(body))
+		return resp, nil
+	}
+
+	malformed := `{"success":true,"data":{"email":`
+	resp.Body = io.NopCloser(strings.NewReader(malformed))
+	setContentLength(resp, len(malformed))
+	return resp, nil
+}
+
+// setContentLength declares the length of a replaced response body
+func setContentLength(resp *http.Response, n int) {
+	resp.ContentLength = int64(n)
+	resp.Header.Set("Content-Length", strconv.Itoa(n))
+}
+
+// closeRequestBody closes the body of a request that is not forwarded
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// match returns the index of the most specific rule matching req in the
+// current phase, or -1
+func (t *Transport) match(req *http.Request) int {
+	elapsed := t.now().Sub(t.start)
+	best, bestLen := -1, -1
+	for i, rule := range t.rules {
+		if elapsed < rule.From || rule.Until > 0 && elapsed >= rule.Until {
+			continue
+		}
+		if rule.Method != "" && !strings.EqualFold(rule.Method, req.Method) {
+			continue
+		}
+		if !pathMatches(req.URL.Path, rule.Path) {
+			continue
+		}
+		if len(rule.Path) > bestLen {
+			best, bestLen = i, len(rule.Path)
+		}
+	}
+	return best
+}
+
+// pathMatches reports whether the request path, with any base path such as
+// /api/v1 in front, starts with the rule path
+func pathMatches(path, rulePath string) bool {
+	if rulePath == "" {
+		return true
+	}
+	for {
+		if strings.HasPrefix(path, rulePath) {
+			return true
+		}
+		next := strings.Index(path[1:], "/")
+		if next < 0 {
+			return false
+		}
+		path = path[next+1:]
+	}
+}
+
+// decide picks the fault and delay for one request
+func (t *Transport) decide(index int, rule Rule) (string, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delay := rule.Latency
+	if rule.Jitter > 0 {
+		delay += time.Duration(t.rand.Int63n(int64(rule.Jitter)))
+	}
+	if delay > 0 {
+		t.stats[FaultLatency]++
+	}
+
+	if b := t.bursts[index]; b != nil && b.remaining > 0 {
+		b.remaining--
+		t.stats[b.fault]++
+		return b.fault, delay
+	}
+
+	fire := func(p float64) bool {
+		return p > 0 && t.rand.Float64() < p
+	}
+	startBurst := func(fault string, size int) {
+		if size > 1 {
+			t.bursts[index] = &burst{remaining: size - 1, fault: fault}
+		}
+	}
+
+	var fault string
+	switch {
+	case fire(rule.ResetProbability):
+		fault = FaultReset
+	case fire(rule.RateLimitProbability):
+		fault = FaultRateLimit
+		startBurst(fault, rule.RateLimitBurst)
+	case fire(rule.ServerErrorProbability):
+		fault = FaultServerError
+		startBurst(fault, rule.ServerErrorBurst)
+	case fire(rule.TruncateProbability):
+		fault = FaultTruncated
+	case fire(rule.MalformedProbability):
+		fault = FaultMalformed
+	}
+
+	if fault != "" {
+		t.stats[fault]++
+	}
+	return fault, delay
+}
+
+// syntheticResponse builds a response without contacting the server
+func syntheticResponse(req *http.Request, status int, header http.Header, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// errReader returns err on every read
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
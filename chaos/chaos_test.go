@@ -0,0 +1,92 @@
+package chaos
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// okBody is the response of okTransport
+const okBody = `{"success":true,"data":{"email":"jane@example.com","result":"deliverable"}}`
+
+// okTransport answers every request with okBody
+type okTransport struct{}
+
+func (okTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	rec.WriteString(okBody)
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+func get(t *testing.T, transport http.RoundTripper, path string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest("GET", "http://api.test/api/v1"+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	return resp
+}
+
+func TestTruncatedDeclaresFullLength(t *testing.T) {
+	transport := New(okTransport{}, 1, Rule{Path: "/verify", TruncateProbability: 1})
+	resp := get(t, transport, "/verify")
+	defer resp.Body.Close()
+
+	want := int64(len(okBody))
+	if resp.ContentLength != want || resp.Header.Get("Content-Length") != strconv.FormatInt(want, 10) {
+		t.Errorf("ContentLength = %d, header %q, want %d", resp.ContentLength, resp.Header.Get("Content-Length"), want)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if !errors.Is(err, io.ErrUnexpectedEOF) || int64(len(body)) >= want {
+		t.Errorf("read %d bytes, %v, want a short read and io.ErrUnexpectedEOF", len(body), err)
+	}
+}
+
+func TestMalformedDeclaresLength(t *testing.T) {
+	transport := New(okTransport{}, 1, Rule{MalformedProbability: 1})
+	resp := get(t, transport, "/credits")
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.ContentLength != int64(len(body)) || resp.Header.Get("Content-Length") != strconv.Itoa(len(body)) {
+		t.Errorf("ContentLength = %d, header %q, want %d", resp.ContentLength, resp.Header.Get("Content-Length"), len(body))
+	}
+}
+
+func TestPhases(t *testing.T) {
+	transport := New(okTransport{}, 1,
+		Rule{Path: "/verify", Until: time.Minute},
+		Rule{Path: "/verify", From: time.Minute, Until: 2 * time.Minute, ServerErrorProbability: 1},
+		Rule{Path: "/verify", From: 2 * time.Minute, RateLimitProbability: 1},
+	)
+	now := transport.start
+	transport.now = func() time.Time { return now }
+
+	for _, phase := range []struct {
+		at     time.Duration
+		status int
+	}{
+		{0, http.StatusOK},
+		{59 * time.Second, http.StatusOK},
+		{time.Minute, http.StatusServiceUnavailable},
+		{2 * time.Minute, http.StatusTooManyRequests},
+		{time.Hour, http.StatusTooManyRequests},
+	} {
+		now = transport.start.Add(phase.at)
+		resp := get(t, transport, "/verify")
+		resp.Body.Close()
+		if resp.StatusCode != phase.status {
+			t.Errorf("at %v: status %d, want %d", phase.at, resp.StatusCode, phase.status)
+		}
+	}
+}
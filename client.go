@@ -2,6 +2,7 @@ package emaillistchecker
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -23,9 +24,23 @@ const (
 
 // Client is the EmailListChecker API client
 type Client struct {
-	apiKey     string
-	baseURL    string
-	httpClient *http.Client
+	apiKey        string
+	baseURL       string
+	httpClient    *http.Client
+	resolver      Resolver
+	cache         VerifyCache
+	logger        Logger
+	logOptions    LogOptions
+	retry         *RetryPolicy
+	metrics       Metrics
+	middlewares   []Middleware
+	tracer        Tracer
+	budget        *Budget
+	attribution   *Attribution
+	fallback      func(error) bool
+	domains       *DomainLists
+	canonicalizer *Canonicalizer
+	ctx           context.Context
 }
 
 // NewClient creates a new EmailListChecker client
@@ -50,11 +65,17 @@ func NewClientWithConfig(apiKey, baseURL string, timeout time.Duration) *Client
 	}
 }
 
+// SetTransport replaces the HTTP transport used for API requests,
+// for example to record and replay traffic in tests
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.httpClient.Transport = transport
+}
+
 // VerifyRequest represents a single email verification request
 type VerifyRequest struct {
-	Email      string `json:"email"`
-	Timeout    *int   `json:"timeout,omitempty"`
-	SMTPCheck  bool   `json:"smtp_check"`
+	Email     string `json:"email"`
+	Timeout   *int   `json:"timeout,omitempty"`
+	SMTPCheck bool   `json:"smtp_check"`
 }
 
 // VerifyResponse represents a verification result
@@ -71,6 +92,14 @@ type VerifyResponse struct {
 	Domain       string   `json:"domain"`
 	SpamTrap     bool     `json:"spam_trap"`
 	MXFound      bool     `json:"mx_found"`
+	// Source is SourceLocal for results worked out without the API, by
+	// VerifyLocal or for invalid international addresses, and empty for API results
+	Source string `json:"source,omitempty"`
+	// Suggestion is a likely typo fix, set by VerifyWithOptions when asked to suggest
+	Suggestion *Suggestion `json:"suggestion,omitempty"`
+	// SMTPUTF8 is set when the local part is not ASCII, so only mail servers
+	// supporting SMTPUTF8 can deliver to the address
+	SMTPUTF8 bool `json:"smtputf8,omitempty"`
 }
 
 // BatchRequest represents a batch verification request
@@ -87,24 +116,179 @@ type BatchResponse struct {
 	Status      string `json:"status"`
 	TotalEmails int    `json:"total_emails"`
 	CreatedAt   string `json:"created_at"`
+	// Inputs are the addresses passed to VerifyBatch, in order
+	Inputs []string `json:"inputs,omitempty"`
+	// Aliases maps each input VerifyBatch did not submit as given, because it
+	// was a duplicate or an international address sent in ASCII form, to the
+	// submitted address whose result applies to it. Inputs and Aliases are
+	// set by the client, and kept when the response is saved as JSON, so
+	// ExpandResults can be applied later.
+	Aliases map[string]string `json:"aliases,omitempty"`
+}
+
+// ExpandResults reports batch results under every input of the batch, in
+// input order. An input listed in Aliases gets a copy of the result for the
+// address submitted in its place, with an international address reported in
+// Unicode as Verify does. International inputs left out of the batch as
+// invalid get the undeliverable result Verify reports for them. Other inputs
+// without a result, such as ones left out by a results filter, are skipped.
+// Without Inputs the results are returned as is.
+func (b *BatchResponse) ExpandResults(results []VerifyResponse) []VerifyResponse {
+	if len(b.Inputs) == 0 {
+		return results
+	}
+
+	bySubmitted := make(map[string]*VerifyResponse, len(results))
+	for i := range results {
+		bySubmitted[cacheKey(results[i].Email)] = &results[i]
+	}
+
+	expanded := make([]VerifyResponse, 0, len(b.Inputs))
+	for _, input := range b.Inputs {
+		submitted := input
+		if alias, ok := b.Aliases[input]; ok {
+			submitted = alias
+		}
+		if _, _, err := transportAddress(submitted); err != nil {
+			expanded = append(expanded, *invalidAddressResult(input))
+			continue
+		}
+		result, ok := bySubmitted[cacheKey(submitted)]
+		if !ok {
+			continue
+		}
+		r := *result
+		r.Email = input
+		if _, addr, err := transportAddress(input); err == nil {
+			localizeResult(&r, input, addr)
+		}
+		expanded = append(expanded, r)
+	}
+	return expanded
 }
 
 // BatchStatusResponse represents batch status
 type BatchStatusResponse struct {
-	ID               int     `json:"id"`
-	Status           string  `json:"status"`
-	Progress         int     `json:"progress"`
-	TotalEmails      int     `json:"total_emails"`
-	ProcessedEmails  int     `json:"processed_emails"`
-	ValidEmails      int     `json:"valid_emails"`
-	InvalidEmails    int     `json:"invalid_emails"`
-	UnknownEmails    int     `json:"unknown_emails"`
+	ID              int    `json:"id"`
+	Status          string `json:"status"`
+	Progress        int    `json:"progress"`
+	TotalEmails     int    `json:"total_emails"`
+	ProcessedEmails int    `json:"processed_emails"`
+	ValidEmails     int    `json:"valid_emails"`
+	InvalidEmails   int    `json:"invalid_emails"`
+	UnknownEmails   int    `json:"unknown_emails"`
+}
+
+// Verify verifies a single email address. With SetFallback, failures the
+// fallback accepts are answered by VerifyLocal instead.
+//
+// International addresses are checked with ParseAddress first. Ones that
+// cannot be valid are not sent to the API and cost no credits: the result is
+// undeliverable with Reason ReasonInvalidSyntax and Source SourceLocal.
+// VerifyBatch leaves them out of the batch and GetBatchVerifyResults reports
+// them the same way. ASCII addresses are always left to the API.
+func (c *Client) Verify(email string, timeout *int, smtpCheck bool) (result *VerifyResponse, err error) {
+	c, end := c.traceOperation("Verify")
+	defer func() { end(err) }()
+
+	result, err = c.verify(email, timeout, smtpCheck)
+	if err != nil && c.fallback != nil && c.fallback(err) {
+		return c.VerifyLocal(c.context(), email), nil
+	}
+	if err == nil && c.domains != nil {
+		c.domains.Learn(result)
+	}
+	return result, err
 }
 
-// Verify verifies a single email address
-func (c *Client) Verify(email string, timeout *int, smtpCheck bool) (*VerifyResponse, error) {
+// SuggestMode controls typo suggestions in VerifyWithOptions
+type SuggestMode int
+
+const (
+	// SuggestOff makes no suggestions
+	SuggestOff SuggestMode = iota
+	// SuggestAlongside verifies the address as given and attaches any suggestion to the result
+	SuggestAlongside
+	// SuggestInstead verifies the suggested address instead of the one given, when there is a suggestion
+	SuggestInstead
+)
+
+// VerifyOptions configures VerifyWithOptions
+type VerifyOptions struct {
+	// SMTPCheck and Timeout are passed to Verify
+	SMTPCheck bool
+	Timeout   *int
+	// Suggest checks the domain for typos with SuggestCorrection
+	Suggest SuggestMode
+	// MinConfidence ignores suggestions less confident than this (default 0.75)
+	MinConfidence float64
+}
+
+// VerifyWithOptions verifies a single email address, optionally looking for a
+// typo in its domain with SuggestCorrection, using the client's DomainLists.
+// The suggestion is attached to the result; with SuggestInstead the result is
+// for the suggested address, so its Email differs from the address given.
+func (c *Client) VerifyWithOptions(email string, opts VerifyOptions) (*VerifyResponse, error) {
+	var suggestion *Suggestion
+	if opts.Suggest != SuggestOff {
+		minConfidence := opts.MinConfidence
+		if minConfidence <= 0 {
+			minConfidence = 0.75
+		}
+		if s, ok := suggestCorrection(email, c.domainLists()); ok && s.Confidence >= minConfidence {
+			suggestion = s
+		}
+	}
+
+	target := email
+	if suggestion != nil && opts.Suggest == SuggestInstead {
+		target = suggestion.Email
+	}
+
+	result, err := c.Verify(target, opts.Timeout, opts.SMTPCheck)
+	if err != nil || suggestion == nil {
+		return result, err
+	}
+
+	// Copy so a cached result is not modified
+	suggested := *result
+	suggested.Suggestion = suggestion
+	return &suggested, nil
+}
+
+// verify verifies an address with the API
+func (c *Client) verify(email string, timeout *int, smtpCheck bool) (*VerifyResponse, error) {
+	if c.cache != nil {
+		cached, ok := c.cache.Get(c.cacheAddress(email))
+		c.observeCache(ok)
+		if ok {
+			if cached.Email != email && c.canonicalizer != nil {
+				// Report the result under the address asked for
+				hit := *cached
+				hit.Email = email
+				if _, addr, err := transportAddress(email); err == nil {
+					localizeResult(&hit, email, addr)
+				}
+				return &hit, nil
+			}
+			return cached, nil
+		}
+	}
+
+	// International addresses are sent with an ASCII domain and reported
+	// under the address given
+	transport, addr, err := transportAddress(email)
+	if err != nil {
+		return invalidAddressResult(email), nil
+	}
+
+	spend, err := c.reserveCredits("Verify", 1)
+	if err != nil {
+		return nil, err
+	}
+
 	req := VerifyRequest{
-		Email:     email,
+		Email:     transport,
 		Timeout:   timeout,
 		SMTPCheck: smtpCheck,
 	}
@@ -113,23 +297,51 @@ func (c *Client) Verify(email string, timeout *int, smtpCheck bool) (*VerifyResp
 		Data *VerifyResponse `json:"data"`
 	}
 
-	err := c.request("POST", "/verify", req, &result)
+	err = c.request("Verify", "POST", "/verify", req, &result)
 	if err != nil {
+		c.settle(spend, 0)
 		return nil, err
 	}
 
 	if result.Data != nil {
+		c.spendCredits("Verify", 1)
+		localizeResult(result.Data, email, addr)
+		c.cacheResult(c.cacheAddress(email), result.Data)
 		return result.Data, nil
 	}
 
 	// Fallback if response doesn't have data wrapper
 	var directResult VerifyResponse
-	err = c.request("POST", "/verify", req, &directResult)
+	err = c.request("Verify", "POST", "/verify", req, &directResult)
+	if err == nil {
+		c.spendCredits("Verify", 1)
+		localizeResult(&directResult, email, addr)
+		c.cacheResult(c.cacheAddress(email), &directResult)
+	} else {
+		c.settle(spend, 0)
+	}
 	return &directResult, err
 }
 
-// VerifyBatch submits emails for batch verification
+// VerifyBatch submits emails for batch verification. With SetCanonicalizer,
+// addresses sharing a canonical form are submitted once and the others are
+// listed in the response's Aliases. International addresses are submitted
+// with an ASCII domain and also listed in Aliases, and ones that cannot be
+// valid are left out, as Verify describes, so TotalEmails does not count
+// them. GetBatchVerifyResults reports the results under every input.
 func (c *Client) VerifyBatch(emails []string, name, callbackURL string, autoStart bool) (*BatchResponse, error) {
+	inputs := append([]string(nil), emails...)
+	var aliases map[string]string
+	if c.canonicalizer != nil {
+		emails, aliases = c.dedupeAddresses(emails)
+	}
+	emails, aliases = transportAddresses(emails, aliases)
+
+	spend, err := c.reserveCredits("VerifyBatch", len(emails))
+	if err != nil {
+		return nil, err
+	}
+
 	req := BatchRequest{
 		Emails:      emails,
 		Name:        name,
@@ -141,23 +353,52 @@ func (c *Client) VerifyBatch(emails []string, name, callbackURL string, autoStar
 		Data *BatchResponse `json:"data"`
 	}
 
-	err := c.request("POST", "/verify/batch", req, &result)
+	err = c.request("VerifyBatch", "POST", "/verify/batch", req, &result)
 	if err != nil {
+		c.settle(spend, 0)
 		return nil, err
 	}
 
 	if result.Data != nil {
+		c.settle(spend, result.Data.TotalEmails)
+		c.spendCredits("VerifyBatch", result.Data.TotalEmails)
+		result.Data.Inputs, result.Data.Aliases = inputs, aliases
 		return result.Data, nil
 	}
 
 	// Fallback if response doesn't have data wrapper
 	var directResult BatchResponse
-	err = c.request("POST", "/verify/batch", req, &directResult)
+	err = c.request("VerifyBatch", "POST", "/verify/batch", req, &directResult)
+	if err == nil {
+		c.settle(spend, directResult.TotalEmails)
+		c.spendCredits("VerifyBatch", directResult.TotalEmails)
+		directResult.Inputs, directResult.Aliases = inputs, aliases
+	} else {
+		c.settle(spend, 0)
+	}
 	return &directResult, err
 }
 
-// VerifyBatchFile uploads a file for batch verification (CSV, TXT, or XLSX)
+// VerifyBatchFile uploads a file for batch verification (CSV, TXT, or XLSX).
+// With SetBudget the file is read first to estimate its cost, and files that
+// cannot be read are not uploaded.
 func (c *Client) VerifyBatchFile(filePath string, name, callbackURL *string, autoStart bool) (*BatchResponse, error) {
+	estimate := 0
+	if c.budget != nil {
+		// Without an estimate the budget cannot be enforced, so do not upload
+		emails, err := readFileAddresses(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate credits for budget: %w", err)
+		}
+		estimate = estimateAddresses(emails, cacheKey).Credits
+	}
+	spend, err := c.reserveCredits("VerifyBatchFile", estimate)
+	if err != nil {
+		return nil, err
+	}
+	charged := 0
+	defer func() { c.settle(spend, charged) }()
+
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
@@ -197,16 +438,14 @@ func (c *Client) VerifyBatchFile(filePath string, name, callbackURL *string, aut
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", c.baseURL+"/verify/batch/upload", body)
+	req, err := c.newRequest("VerifyBatchFile", "POST", "/verify/batch/upload", body)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.Header.Set("User-Agent", "EmailListChecker-Go/1.0.0")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -219,38 +458,7 @@ func (c *Client) VerifyBatchFile(filePath string, name, callbackURL *string, aut
 
 	// Handle errors
 	if resp.StatusCode >= 400 {
-		var errData map[string]interface{}
-		_ = json.Unmarshal(responseBody, &errData)
-
-		switch resp.StatusCode {
-		case 401:
-			msg := "Invalid API key"
-			if errData != nil && errData["error"] != nil {
-				msg = errData["error"].(string)
-			}
-			return nil, NewAuthenticationError(msg, resp.StatusCode, errData)
-
-		case 402:
-			msg := "Insufficient credits"
-			if errData != nil && errData["error"] != nil {
-				msg = errData["error"].(string)
-			}
-			return nil, NewInsufficientCreditsError(msg, resp.StatusCode, errData)
-
-		case 422:
-			msg := "Validation error"
-			if errData != nil && errData["message"] != nil {
-				msg = errData["message"].(string)
-			}
-			return nil, NewValidationError(msg, resp.StatusCode, errData)
-
-		default:
-			msg := fmt.Sprintf("API error: %d", resp.StatusCode)
-			if errData != nil && errData["error"] != nil {
-				msg = errData["error"].(string)
-			}
-			return nil, NewAPIError(msg, resp.StatusCode, errData)
-		}
+		return nil, responseError(resp, responseBody)
 	}
 
 	var result struct {
@@ -262,6 +470,10 @@ func (c *Client) VerifyBatchFile(filePath string, name, callbackURL *string, aut
 		return nil, err
 	}
 
+	if result.Data != nil {
+		charged = result.Data.TotalEmails
+		c.spendCredits("VerifyBatchFile", charged)
+	}
 	return result.Data, nil
 }
 
@@ -272,7 +484,7 @@ func (c *Client) GetBatchStatus(batchID int) (*BatchStatusResponse, error) {
 	}
 
 	endpoint := fmt.Sprintf("/verify/batch/%d", batchID)
-	err := c.request("GET", endpoint, nil, &result)
+	err := c.request("GetBatchStatus", "GET", endpoint, nil, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -283,7 +495,7 @@ func (c *Client) GetBatchStatus(batchID int) (*BatchStatusResponse, error) {
 
 	// Fallback if response doesn't have data wrapper
 	var directResult BatchStatusResponse
-	err = c.request("GET", endpoint, nil, &directResult)
+	err = c.request("GetBatchStatus", "GET", endpoint, nil, &directResult)
 	return &directResult, err
 }
 
@@ -295,7 +507,7 @@ func (c *Client) GetBatchResults(batchID int, format, filter string) (interface{
 		Data interface{} `json:"data"`
 	}
 
-	err := c.request("GET", endpoint, nil, &result)
+	err := c.request("GetBatchResults", "GET", endpoint, nil, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -303,6 +515,38 @@ func (c *Client) GetBatchResults(batchID int, format, filter string) (interface{
 	return result.Data, nil
 }
 
+// GetBatchVerifyResults downloads the results of a batch submitted with
+// VerifyBatch as JSON and reports them under every input with ExpandResults.
+// Filter is one of "all", "valid", "invalid" or "unknown".
+func (c *Client) GetBatchVerifyResults(batch *BatchResponse, filter string) ([]VerifyResponse, error) {
+	data, err := c.GetBatchResults(batch.ID, "json", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode batch results: %w", err)
+	}
+	var results []VerifyResponse
+	if err := json.Unmarshal(raw, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode batch results: %w", err)
+	}
+
+	expanded := batch.ExpandResults(results)
+	if filter == "valid" || filter == "unknown" {
+		// Inputs left out as invalid only belong in the other results
+		kept := expanded[:0]
+		for _, r := range expanded {
+			if r.Source != SourceLocal {
+				kept = append(kept, r)
+			}
+		}
+		expanded = kept
+	}
+	return expanded, nil
+}
+
 // FindEmailRequest represents an email finder request
 type FindEmailRequest struct {
 	FirstName string `json:"first_name"`
@@ -311,27 +555,17 @@ type FindEmailRequest struct {
 }
 
 // FindEmail finds email address by name and domain
-func (c *Client) FindEmail(firstName, lastName, domain string) (map[string]interface{}, error) {
-	req := FindEmailRequest{
-		FirstName: firstName,
-		LastName:  lastName,
-		Domain:    domain,
-	}
-
-	var result struct {
-		Data map[string]interface{} `json:"data"`
-	}
+func (c *Client) FindEmail(firstName, lastName, domain string) (*FinderResult, error) {
+	return c.FindEmailWithOptions(firstName, lastName, domain, FindEmailOptions{})
+}
 
-	err := c.request("POST", "/finder/email", req, &result)
+// FindByDomain finds emails by domain
+func (c *Client) FindByDomain(domain string, limit, offset int) (map[string]interface{}, error) {
+	spend, err := c.reserveCredits("FindByDomain", 1)
 	if err != nil {
 		return nil, err
 	}
 
-	return result.Data, nil
-}
-
-// FindByDomain finds emails by domain
-func (c *Client) FindByDomain(domain string, limit, offset int) (map[string]interface{}, error) {
 	req := map[string]interface{}{
 		"domain": domain,
 		"limit":  limit,
@@ -342,16 +576,23 @@ func (c *Client) FindByDomain(domain string, limit, offset int) (map[string]inte
 		Data map[string]interface{} `json:"data"`
 	}
 
-	err := c.request("POST", "/finder/domain", req, &result)
+	err = c.request("FindByDomain", "POST", "/finder/domain", req, &result)
 	if err != nil {
+		c.settle(spend, 0)
 		return nil, err
 	}
+	c.spendCredits("FindByDomain", 1)
 
 	return result.Data, nil
 }
 
 // FindByCompany finds emails by company name
 func (c *Client) FindByCompany(company string, limit int) (map[string]interface{}, error) {
+	spend, err := c.reserveCredits("FindByCompany", 1)
+	if err != nil {
+		return nil, err
+	}
+
 	req := map[string]interface{}{
 		"company": company,
 		"limit":   limit,
@@ -361,10 +602,12 @@ func (c *Client) FindByCompany(company string, limit int) (map[string]interface{
 		Data map[string]interface{} `json:"data"`
 	}
 
-	err := c.request("POST", "/finder/company", req, &result)
+	err = c.request("FindByCompany", "POST", "/finder/company", req, &result)
 	if err != nil {
+		c.settle(spend, 0)
 		return nil, err
 	}
+	c.spendCredits("FindByCompany", 1)
 
 	return result.Data, nil
 }
@@ -375,7 +618,7 @@ func (c *Client) GetCredits() (map[string]interface{}, error) {
 		Data map[string]interface{} `json:"data"`
 	}
 
-	err := c.request("GET", "/credits", nil, &result)
+	err := c.request("GetCredits", "GET", "/credits", nil, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -383,27 +626,17 @@ func (c *Client) GetCredits() (map[string]interface{}, error) {
 	return result.Data, nil
 }
 
-// GetUsage gets API usage statistics
-func (c *Client) GetUsage() (map[string]interface{}, error) {
+// GetLists gets all verification lists.
+//
+// GetLists used to return []interface{} holding the decoded JSON objects;
+// it now returns typed Lists, so callers reading the maps must use the List
+// fields instead.
+func (c *Client) GetLists() ([]List, error) {
 	var result struct {
-		Data map[string]interface{} `json:"data"`
+		Data []List `json:"data"`
 	}
 
-	err := c.request("GET", "/usage", nil, &result)
-	if err != nil {
-		return nil, err
-	}
-
-	return result.Data, nil
-}
-
-// GetLists gets all verification lists
-func (c *Client) GetLists() ([]interface{}, error) {
-	var result struct {
-		Data []interface{} `json:"data"`
-	}
-
-	err := c.request("GET", "/lists", nil, &result)
+	err := c.request("GetLists", "GET", "/lists", nil, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -414,13 +647,11 @@ func (c *Client) GetLists() ([]interface{}, error) {
 // DeleteList deletes a verification list
 func (c *Client) DeleteList(listID int) error {
 	endpoint := fmt.Sprintf("/lists/%d", listID)
-	return c.request("DELETE", endpoint, nil, nil)
+	return c.request("DeleteList", "DELETE", endpoint, nil, nil)
 }
 
-// request makes an HTTP request to the API
-func (c *Client) request(method, endpoint string, body interface{}, result interface{}) error {
-	url := c.baseURL + endpoint
-
+// request makes an HTTP request to the API on behalf of the operation op
+func (c *Client) request(op, method, endpoint string, body interface{}, result interface{}) error {
 	var reqBody io.Reader
 	if body != nil {
 		jsonData, err := json.Marshal(body)
@@ -430,17 +661,15 @@ func (c *Client) request(method, endpoint string, body interface{}, result inter
 		reqBody = bytes.NewBuffer(jsonData)
 	}
 
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := c.newRequest(op, method, endpoint, reqBody)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "EmailListChecker-Go/1.0.0")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
@@ -453,47 +682,7 @@ func (c *Client) request(method, endpoint string, body interface{}, result inter
 
 	// Handle errors
 	if resp.StatusCode >= 400 {
-		var errData map[string]interface{}
-		_ = json.Unmarshal(respBody, &errData)
-
-		switch resp.StatusCode {
-		case 401:
-			msg := "Invalid API key"
-			if errData != nil && errData["error"] != nil {
-				msg = errData["error"].(string)
-			}
-			return NewAuthenticationError(msg, resp.StatusCode, errData)
-
-		case 402:
-			msg := "Insufficient credits"
-			if errData != nil && errData["error"] != nil {
-				msg = errData["error"].(string)
-			}
-			return NewInsufficientCreditsError(msg, resp.StatusCode, errData)
-
-		case 422:
-			msg := "Validation error"
-			if errData != nil && errData["message"] != nil {
-				msg = errData["message"].(string)
-			}
-			return NewValidationError(msg, resp.StatusCode, errData)
-
-		case 429:
-			retryAfter := 60
-			if retryHeader := resp.Header.Get("Retry-After"); retryHeader != "" {
-				if val, err := strconv.Atoi(retryHeader); err == nil {
-					retryAfter = val
-				}
-			}
-			return NewRateLimitError(retryAfter, resp.StatusCode, errData)
-
-		default:
-			msg := fmt.Sprintf("API error: %d", resp.StatusCode)
-			if errData != nil && errData["error"] != nil {
-				msg = errData["error"].(string)
-			}
-			return NewAPIError(msg, resp.StatusCode, errData)
-		}
+		return responseError(resp, respBody)
 	}
 
 	// Parse successful response
@@ -505,3 +694,76 @@ func (c *Client) request(method, endpoint string, body interface{}, result inter
 
 	return nil
 }
+
+// download makes a GET request and copies the raw response body to w
+func (c *Client) download(op, endpoint string, w io.Writer) error {
+	req, err := c.newRequest(op, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+		return responseError(resp, respBody)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to write response body: %w", err)
+	}
+
+	return nil
+}
+
+// responseError converts an error response into the matching typed error
+func responseError(resp *http.Response, body []byte) error {
+	var errData map[string]interface{}
+	_ = json.Unmarshal(body, &errData)
+
+	switch resp.StatusCode {
+	case 401:
+		msg := "Invalid API key"
+		if errData != nil && errData["error"] != nil {
+			msg = errData["error"].(string)
+		}
+		return NewAuthenticationError(msg, resp.StatusCode, errData)
+
+	case 402:
+		msg := "Insufficient credits"
+		if errData != nil && errData["error"] != nil {
+			msg = errData["error"].(string)
+		}
+		return NewInsufficientCreditsError(msg, resp.StatusCode, errData)
+
+	case 422:
+		msg := "Validation error"
+		if errData != nil && errData["message"] != nil {
+			msg = errData["message"].(string)
+		}
+		return NewValidationError(msg, resp.StatusCode, errData)
+
+	case 429:
+		retryAfter := 60
+		if retryHeader := resp.Header.Get("Retry-After"); retryHeader != "" {
+			if val, err := strconv.Atoi(retryHeader); err == nil {
+				retryAfter = val
+			}
+		}
+		return NewRateLimitError(retryAfter, resp.StatusCode, errData)
+
+	default:
+		msg := fmt.Sprintf("API error: %d", resp.StatusCode)
+		if errData != nil && errData["error"] != nil {
+			msg = errData["error"].(string)
+		}
+		return NewAPIError(msg, resp.StatusCode, errData)
+	}
+}
@@ -0,0 +1,100 @@
+package emaillistchecker_test
+
+import (
+	"testing"
+	"time"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+func TestVerifyInvalidInternationalAddress(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	// A zero-width space cannot appear in a local part
+	email := "j\u200bane@bücher.de"
+	result, err := client.Verify(email, nil, true)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if result.Email != email || result.Result != "undeliverable" ||
+		result.Reason != emaillistchecker.ReasonInvalidSyntax || result.Source != emaillistchecker.SourceLocal {
+		t.Errorf("Verify(%q) = %+v, want a local undeliverable INVALID_SYNTAX result", email, result)
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("sent %d requests, want none", n)
+	}
+}
+
+func TestGetBatchVerifyResults(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	client.SetCanonicalizer(emaillistchecker.NewCanonicalizer())
+
+	inputs := []string{
+		"John.Doe+promo@gmail.com",
+		"undeliverable@example.com",
+		"johndoe@googlemail.com",
+		"jörg@bücher.de",
+		"j\u200bane@bücher.de",
+	}
+	batch, err := client.VerifyBatch(inputs, "test", "", true)
+	if err != nil {
+		t.Fatalf("VerifyBatch: %v", err)
+	}
+	if batch.TotalEmails != 3 {
+		t.Errorf("TotalEmails = %d, want 3", batch.TotalEmails)
+	}
+	if got := batch.Aliases["johndoe@googlemail.com"]; got != "John.Doe+promo@gmail.com" {
+		t.Errorf("Aliases[johndoe@googlemail.com] = %q, want John.Doe+promo@gmail.com", got)
+	}
+	srv.Advance(time.Hour)
+
+	results, err := client.GetBatchVerifyResults(batch, "all")
+	if err != nil {
+		t.Fatalf("GetBatchVerifyResults: %v", err)
+	}
+	want := []struct {
+		email  string
+		result string
+		domain string
+	}{
+		{"John.Doe+promo@gmail.com", "deliverable", "gmail.com"},
+		{"undeliverable@example.com", "undeliverable", "example.com"},
+		{"johndoe@googlemail.com", "deliverable", "gmail.com"},
+		{"jörg@bücher.de", "deliverable", "bücher.de"},
+		{"j\u200bane@bücher.de", "undeliverable", ""},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.Email != w.email || r.Result != w.result || r.Domain != w.domain {
+			t.Errorf("results[%d] = %s %s %s, want %s %s %s", i, r.Email, r.Result, r.Domain, w.email, w.result, w.domain)
+		}
+	}
+	if !results[3].SMTPUTF8 {
+		t.Error("results[3].SMTPUTF8 = false, want true")
+	}
+
+	valid, err := client.GetBatchVerifyResults(batch, "valid")
+	if err != nil {
+		t.Fatalf("GetBatchVerifyResults: %v", err)
+	}
+	// Inputs left out as invalid are only in the invalid results
+	if len(valid) != 3 {
+		t.Errorf("got %d valid results, want 3", len(valid))
+	}
+}
+
+func TestExpandResultsWithoutInputs(t *testing.T) {
+	batch := &emaillistchecker.BatchResponse{}
+	results := []emaillistchecker.VerifyResponse{{Email: "jane@example.com"}}
+	if got := batch.ExpandResults(results); len(got) != 1 || got[0].Email != "jane@example.com" {
+		t.Errorf("ExpandResults without Inputs = %+v, want the results unchanged", got)
+	}
+}
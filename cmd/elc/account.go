@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+)
+
+var listColumns = []string{"id", "name", "status", "total", "valid", "invalid", "unknown", "created"}
+
+func runCredits(e *env, args []string) (int, error) {
+	fs, g := newFlagSet(e, "credits", "credits [flags]")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	credits, err := client.GetCredits()
+	if err != nil {
+		return exitError, err
+	}
+	return exitOK, render(e.stdout, g.output, keyValueTable(credits))
+}
+
+func runUsage(e *env, args []string) (int, error) {
+	fs, g := newFlagSet(e, "usage", "usage [flags]")
+	from := fs.String("from", "", "start of the period, as YYYY-MM-DD or RFC 3339")
+	to := fs.String("to", "", "end of the period (exclusive), as YYYY-MM-DD or RFC 3339")
+	since := fs.Duration("since", 0, "report the last duration, e.g. 168h (instead of -from)")
+	granularity := fs.String("granularity", "", "split the period into hour, day, week or month buckets")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+
+	q := emaillistchecker.UsageQuery{Granularity: emaillistchecker.Granularity(*granularity)}
+	var err error
+	if q.From, err = parseTimeFlag("from", *from); err != nil {
+		return exitUsage, err
+	}
+	if q.To, err = parseTimeFlag("to", *to); err != nil {
+		return exitUsage, err
+	}
+	if *since > 0 {
+		if *from != "" {
+			return exitUsage, newUsageError("-since and -from cannot be combined")
+		}
+		q.From = time.Now().Add(-*since)
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	usage, err := client.GetUsage(context.Background(), q)
+	if err != nil {
+		return exitError, err
+	}
+	return exitOK, render(e.stdout, g.output, usageTable(usage))
+}
+
+// parseTimeFlag parses a date or RFC 3339 timestamp; empty means unset
+func parseTimeFlag(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, newUsageError("-%s: want YYYY-MM-DD or an RFC 3339 timestamp, got %q", name, value)
+}
+
+// usageTable renders a usage time series, or the totals when there is none
+func usageTable(usage *emaillistchecker.UsageReport) *table {
+	if usage.Granularity == "" {
+		t := &table{headers: []string{"field", "value"}, value: usage}
+		for _, row := range [][2]string{
+			{"from", usage.From},
+			{"to", usage.To},
+			{"total_requests", strconv.Itoa(usage.TotalRequests)},
+			{"successful_requests", strconv.Itoa(usage.SuccessfulRequests)},
+			{"failed_requests", strconv.Itoa(usage.FailedRequests)},
+			{"credits_used", strconv.Itoa(usage.CreditsUsed)},
+		} {
+			t.rows = append(t.rows, []string{row[0], row[1]})
+		}
+		return t
+	}
+
+	t := &table{headers: []string{"period", "requests", "successful", "failed", "credits"}, value: usage}
+	for _, p := range usage.Series {
+		t.rows = append(t.rows, []string{
+			p.Period,
+			strconv.Itoa(p.Requests),
+			strconv.Itoa(p.Successful),
+			strconv.Itoa(p.Failed),
+			strconv.Itoa(p.Credits),
+		})
+	}
+	return t
+}
+
+func runLists(e *env, args []string) (int, error) {
+	if len(args) > 0 {
+		switch args[0] {
+		case "show":
+			return runListsShow(e, args[1:])
+		case "rename":
+			return runListsRename(e, args[1:])
+		case "download":
+			return runListsDownload(e, args[1:])
+		case "delete":
+			return runListsDelete(e, args[1:])
+		}
+	}
+
+	fs, g := newFlagSet(e, "lists", "lists [show|rename|download|delete] [flags] ...")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if fs.NArg() > 0 {
+		return exitUsage, newUsageError("unknown lists command %q", fs.Arg(0))
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	lists, err := client.GetLists()
+	if err != nil {
+		return exitError, err
+	}
+	return exitOK, render(e.stdout, g.output, listsTable(lists))
+}
+
+func runListsShow(e *env, args []string) (int, error) {
+	fs, g := newFlagSet(e, "lists show", "lists show [flags] LIST_ID")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	listID, err := idArg(fs.Args())
+	if err != nil {
+		return exitUsage, err
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	list, err := client.GetList(listID)
+	if err != nil {
+		return exitError, err
+	}
+	t := listsTable([]emaillistchecker.List{*list})
+	t.value = list
+	return exitOK, render(e.stdout, g.output, t)
+}
+
+func runListsRename(e *env, args []string) (int, error) {
+	fs, g := newFlagSet(e, "lists rename", "lists rename [flags] LIST_ID NAME")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return exitUsage, newUsageError("expected LIST_ID NAME")
+	}
+	listID, err := idArg(fs.Args()[:1])
+	if err != nil {
+		return exitUsage, err
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	list, err := client.RenameList(listID, fs.Arg(1))
+	if err != nil {
+		return exitError, err
+	}
+	t := listsTable([]emaillistchecker.List{*list})
+	t.value = list
+	return exitOK, render(e.stdout, g.output, t)
+}
+
+func runListsDownload(e *env, args []string) (int, error) {
+	fs, g := newFlagSet(e, "lists download", "lists download [flags] LIST_ID")
+	format := fs.String("format", "csv", "file format: csv, json or xlsx")
+	filter := fs.String("filter", "all", "results to include: all, valid, invalid or unknown")
+	outPath := fs.String("out", "", "write to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	listID, err := idArg(fs.Args())
+	if err != nil {
+		return exitUsage, err
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	if *outPath == "" {
+		return exitOK, client.DownloadList(listID, *format, *filter, e.stdout)
+	}
+
+	f, err := os.Create(*outPath)
+	if err != nil {
+		return exitError, err
+	}
+	if err := client.DownloadList(listID, *format, *filter, f); err != nil {
+		f.Close()
+		return exitError, err
+	}
+	return exitOK, f.Close()
+}
+
+func runListsDelete(e *env, args []string) (int, error) {
+	fs, g := newFlagSet(e, "lists delete", "lists delete [flags] LIST_ID...")
+	force := fs.Bool("force", false, "delete lists that are still processing")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage, newUsageError("expected at least one LIST_ID")
+	}
+
+	ids := make([]int, 0, fs.NArg())
+	for _, arg := range fs.Args() {
+		id, err := idArg([]string{arg})
+		if err != nil {
+			return exitUsage, err
+		}
+		ids = append(ids, id)
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	if err := client.DeleteLists(ids, *force); err != nil {
+		return exitError, err
+	}
+	return exitOK, nil
+}
+
+func listsTable(lists []emaillistchecker.List) *table {
+	t := &table{headers: listColumns, value: lists}
+	for _, l := range lists {
+		t.rows = append(t.rows, []string{
+			strconv.Itoa(l.ID),
+			l.Name,
+			l.Status,
+			strconv.Itoa(l.TotalEmails),
+			strconv.Itoa(l.ValidEmails),
+			strconv.Itoa(l.InvalidEmails),
+			strconv.Itoa(l.UnknownEmails),
+			l.CreatedAt,
+		})
+	}
+	return t
+}
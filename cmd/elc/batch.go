@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+)
+
+var batchStatusColumns = []string{"id", "status", "progress", "total", "processed", "valid", "invalid", "unknown"}
+
+func runBatch(e *env, args []string) (int, error) {
+	if len(args) == 0 {
+		return exitUsage, newUsageError("usage: elc batch submit|estimate|status|wait|results [flags] ...")
+	}
+
+	switch args[0] {
+	case "submit":
+		return runBatchSubmit(e, args[1:])
+	case "estimate":
+		return runBatchEstimate(e, args[1:])
+	case "status":
+		return runBatchStatus(e, args[1:])
+	case "wait":
+		return runBatchWait(e, args[1:])
+	case "results":
+		return runBatchResults(e, args[1:])
+	}
+	return exitUsage, newUsageError("unknown batch command %q", args[0])
+}
+
+func runBatchSubmit(e *env, args []string) (int, error) {
+	fs, g := newFlagSet(e, "batch submit", "batch submit [flags] (-file PATH | EMAIL...)")
+	file := fs.String("file", "", "upload a CSV, TXT or XLSX file instead of listing addresses")
+	name := fs.String("name", "", "batch name")
+	callback := fs.String("callback", "", "URL to notify when the batch completes")
+	noStart := fs.Bool("no-start", false, "create the batch without starting it")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if (*file == "") == (fs.NArg() == 0) {
+		fs.Usage()
+		return exitUsage, newUsageError("pass either -file or a list of addresses")
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	var batch *emaillistchecker.BatchResponse
+	if *file != "" {
+		var namePtr, callbackPtr *string
+		if *name != "" {
+			namePtr = name
+		}
+		if *callback != "" {
+			callbackPtr = callback
+		}
+		batch, err = client.VerifyBatchFile(*file, namePtr, callbackPtr, !*noStart)
+	} else {
+		batch, err = client.VerifyBatch(fs.Args(), *name, *callback, !*noStart)
+	}
+	if err != nil {
+		return exitError, err
+	}
+
+	t := &table{
+		headers: []string{"id", "status", "total", "created"},
+		rows: [][]string{{
+			strconv.Itoa(batch.ID), batch.Status, strconv.Itoa(batch.TotalEmails), batch.CreatedAt,
+		}},
+		value: batch,
+	}
+	return exitOK, render(e.stdout, g.output, t)
+}
+
+func runBatchEstimate(e *env, args []string) (int, error) {
+	fs, g := newFlagSet(e, "batch estimate", "batch estimate [flags] (-file PATH | EMAIL...)")
+	file := fs.String("file", "", "estimate a CSV, TXT or XLSX file instead of listed addresses")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if (*file == "") == (fs.NArg() == 0) {
+		fs.Usage()
+		return exitUsage, newUsageError("pass either -file or a list of addresses")
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	var estimate *emaillistchecker.BatchEstimate
+	if *file != "" {
+		estimate, err = client.EstimateFile(*file)
+	} else {
+		estimate, err = client.EstimateBatch(fs.Args())
+	}
+	if err != nil {
+		return exitError, err
+	}
+
+	t := &table{
+		headers: []string{"total", "unique", "duplicates", "invalid", "credits", "balance", "sufficient"},
+		rows: [][]string{{
+			strconv.Itoa(estimate.Total), strconv.Itoa(len(estimate.Addresses)),
+			strconv.Itoa(len(estimate.Duplicates)), strconv.Itoa(len(estimate.Invalid)),
+			strconv.Itoa(estimate.Credits), strconv.Itoa(estimate.Balance), strconv.FormatBool(estimate.Sufficient),
+		}},
+		value: estimate,
+	}
+	return exitOK, render(e.stdout, g.output, t)
+}
+
+func runBatchStatus(e *env, args []string) (int, error) {
+	fs, g := newFlagSet(e, "batch status", "batch status [flags] BATCH_ID")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	batchID, err := idArg(fs.Args())
+	if err != nil {
+		return exitUsage, err
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	status, err := client.GetBatchStatus(batchID)
+	if err != nil {
+		return exitError, err
+	}
+	return exitOK, render(e.stdout, g.output, batchStatusTable(status))
+}
+
+func runBatchWait(e *env, args []string) (int, error) {
+	fs, g := newFlagSet(e, "batch wait", "batch wait [flags] BATCH_ID")
+	interval := fs.Duration("interval", 5*time.Second, "polling interval")
+	maxWait := fs.Duration("max-wait", 0, "give up after this long (default: wait forever)")
+	quiet := fs.Bool("q", false, "do not report progress on stderr")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	batchID, err := idArg(fs.Args())
+	if err != nil {
+		return exitUsage, err
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	status, err := waitForBatch(e, client, batchID, *interval, *maxWait, *quiet)
+	if err != nil {
+		return exitError, err
+	}
+	if err := render(e.stdout, g.output, batchStatusTable(status)); err != nil {
+		return exitError, err
+	}
+	if status.Status != "completed" {
+		return exitError, fmt.Errorf("batch %d %s", batchID, status.Status)
+	}
+	return exitOK, nil
+}
+
+// waitForBatch polls a batch until it completes or fails
+func waitForBatch(e *env, client *emaillistchecker.Client, batchID int, interval, maxWait time.Duration, quiet bool) (*emaillistchecker.BatchStatusResponse, error) {
+	var deadline time.Time
+	if maxWait > 0 {
+		deadline = time.Now().Add(maxWait)
+	}
+
+	lastProgress := -1
+	for {
+		status, err := client.GetBatchStatus(batchID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !quiet && status.Progress != lastProgress {
+			fmt.Fprintf(e.stderr, "batch %d: %s %d%% (%d/%d)\n",
+				batchID, status.Status, status.Progress, status.ProcessedEmails, status.TotalEmails)
+			lastProgress = status.Progress
+		}
+
+		switch status.Status {
+		case "completed", "failed", "cancelled":
+			return status, nil
+		}
+
+		if !deadline.IsZero() && time.Now().Add(interval).After(deadline) {
+			return nil, fmt.Errorf("batch %d still %s after %s", batchID, status.Status, maxWait)
+		}
+		time.Sleep(interval)
+	}
+}
+
+func runBatchResults(e *env, args []string) (int, error) {
+	fs, g := newFlagSet(e, "batch results", "batch results [flags] BATCH_ID")
+	filter := fs.String("filter", "all", "results to include: all, valid, invalid or unknown")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	batchID, err := idArg(fs.Args())
+	if err != nil {
+		return exitUsage, err
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	results, err := client.GetBatchResults(batchID, "json", *filter)
+	if err != nil {
+		return exitError, err
+	}
+
+	var t *table
+	switch data := results.(type) {
+	case []interface{}:
+		t = recordsTable(data, verifyColumns)
+	case map[string]interface{}:
+		if records, ok := data["results"].([]interface{}); ok {
+			t = recordsTable(records, verifyColumns)
+		} else {
+			t = keyValueTable(data)
+		}
+	default:
+		t = &table{headers: []string{"results"}, rows: [][]string{{formatValue(data)}}, value: data}
+	}
+	return exitOK, render(e.stdout, g.output, t)
+}
+
+func batchStatusTable(s *emaillistchecker.BatchStatusResponse) *table {
+	return &table{
+		headers: batchStatusColumns,
+		rows: [][]string{{
+			strconv.Itoa(s.ID),
+			s.Status,
+			strconv.Itoa(s.Progress) + "%",
+			strconv.Itoa(s.TotalEmails),
+			strconv.Itoa(s.ProcessedEmails),
+			strconv.Itoa(s.ValidEmails),
+			strconv.Itoa(s.InvalidEmails),
+			strconv.Itoa(s.UnknownEmails),
+		}},
+		value: s,
+	}
+}
+
+// idArg parses a single numeric ID positional argument
+func idArg(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, newUsageError("expected exactly one ID argument")
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, newUsageError("invalid ID %q", args[0])
+	}
+	return id, nil
+}
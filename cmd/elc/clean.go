@@ -0,0 +1,330 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+)
+
+// cleanColumns are appended to every row written by `elc clean`
+var cleanColumns = []string{"elc_result", "elc_score", "elc_reason", "elc_disposable", "elc_role", "elc_action"}
+
+// emailHeaderNames are header names recognised as the email column, in order of preference
+var emailHeaderNames = []string{"email", "e-mail", "email_address", "email address", "emailaddress", "mail"}
+
+func runClean(e *env, args []string) (int, error) {
+	fs, g := newFlagSet(e, "clean", "clean [flags] INPUT.csv")
+	column := fs.String("column", "", "email column (default: detected from header and values)")
+	policyPath := fs.String("policy", "", "policy file deciding keep/review/remove (default: built-in policy)")
+	outDir := fs.String("out-dir", "", "directory for the keep/review/remove files (default: next to the input)")
+	name := fs.String("name", "", "batch name (default: input file name)")
+	interval := fs.Duration("interval", 5*time.Second, "batch polling interval")
+	maxWait := fs.Duration("max-wait", 0, "give up waiting for the batch after this long")
+	quiet := fs.Bool("q", false, "do not report progress on stderr")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage, newUsageError("expected exactly one input file")
+	}
+	inputPath := fs.Arg(0)
+
+	policy := emaillistchecker.DefaultPolicy()
+	if *policyPath != "" {
+		var err error
+		if policy, err = emaillistchecker.LoadPolicy(*policyPath); err != nil {
+			return exitError, err
+		}
+	}
+
+	header, rows, err := readCSVFile(inputPath)
+	if err != nil {
+		return exitError, err
+	}
+
+	emailCol, err := detectEmailColumn(header, rows, *column)
+	if err != nil {
+		return exitError, err
+	}
+
+	emails := uniqueColumnValues(rows, emailCol)
+	if len(emails) == 0 {
+		return exitError, fmt.Errorf("%s: no addresses found in column %q", inputPath, header[emailCol])
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	batchName := *name
+	if batchName == "" {
+		batchName = filepath.Base(inputPath)
+	}
+
+	batchID, err := uploadAddresses(client, emails, batchName)
+	if err != nil {
+		return exitError, err
+	}
+	if !*quiet {
+		fmt.Fprintf(e.stderr, "uploaded %d unique addresses as batch %d\n", len(emails), batchID)
+	}
+
+	status, err := waitForBatch(e, client, batchID, *interval, *maxWait, *quiet)
+	if err != nil {
+		return exitError, err
+	}
+	if status.Status != "completed" {
+		return exitError, fmt.Errorf("batch %d %s", batchID, status.Status)
+	}
+
+	raw, err := client.GetBatchResults(batchID, "json", "all")
+	if err != nil {
+		return exitError, err
+	}
+	results, err := decodeBatchResults(raw)
+	if err != nil {
+		return exitError, err
+	}
+
+	dir := *outDir
+	if dir == "" {
+		dir = filepath.Dir(inputPath)
+	}
+	base := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
+
+	counts, err := writeCleanOutputs(dir, base, header, rows, emailCol, results, policy)
+	if err != nil {
+		return exitError, err
+	}
+
+	t := &table{headers: []string{"action", "rows", "file"}, value: counts}
+	for _, action := range []emaillistchecker.Action{emaillistchecker.ActionKeep, emaillistchecker.ActionReview, emaillistchecker.ActionRemove} {
+		t.rows = append(t.rows, []string{
+			string(action),
+			strconv.Itoa(counts[action]),
+			cleanOutputPath(dir, base, action),
+		})
+	}
+	return exitOK, render(e.stdout, g.output, t)
+}
+
+// readCSVFile reads a CSV file with a header row
+func readCSVFile(path string) ([]string, [][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("%s: empty file", path)
+	}
+	return records[0], records[1:], nil
+}
+
+// detectEmailColumn returns the named column, a column with a recognised email
+// header, or the column where most values look like addresses
+func detectEmailColumn(header []string, rows [][]string, name string) (int, error) {
+	if name != "" {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), name) {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("column %q not found in header", name)
+	}
+
+	for _, candidate := range emailHeaderNames {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), candidate) {
+				return i, nil
+			}
+		}
+	}
+
+	best, bestCount := -1, 0
+	for col := range header {
+		count := 0
+		for _, row := range rows {
+			if col < len(row) && looksLikeEmail(row[col]) {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = col, count
+		}
+	}
+	if best < 0 || bestCount*2 < len(rows) {
+		return 0, fmt.Errorf("could not detect the email column; use -column")
+	}
+	return best, nil
+}
+
+func looksLikeEmail(value string) bool {
+	value = strings.TrimSpace(value)
+	at := strings.LastIndex(value, "@")
+	return at > 0 && at < len(value)-1 && !strings.ContainsAny(value, " ,;")
+}
+
+// uniqueColumnValues returns the distinct non-empty values of a column, case-insensitively
+func uniqueColumnValues(rows [][]string, col int) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for _, row := range rows {
+		if col >= len(row) {
+			continue
+		}
+		value := strings.TrimSpace(row[col])
+		key := strings.ToLower(value)
+		if value == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		values = append(values, value)
+	}
+	return values
+}
+
+// uploadAddresses uploads the addresses as a TXT file with VerifyBatchFile and starts the batch
+func uploadAddresses(client *emaillistchecker.Client, emails []string, name string) (int, error) {
+	tmp, err := os.CreateTemp("", "elc-clean-*.txt")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.WriteString(tmp, strings.Join(emails, "\n")+"\n"); err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+
+	batch, err := client.VerifyBatchFile(tmp.Name(), &name, nil, true)
+	if err != nil {
+		return 0, err
+	}
+	if batch == nil {
+		return 0, fmt.Errorf("upload returned no batch")
+	}
+	return batch.ID, nil
+}
+
+// decodeBatchResults converts untyped batch results into verification results keyed by
+// lowercased address. Results may be a list or an object with a "results" list.
+func decodeBatchResults(raw interface{}) (map[string]*emaillistchecker.VerifyResponse, error) {
+	if m, ok := raw.(map[string]interface{}); ok {
+		raw = m["results"]
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []emaillistchecker.VerifyResponse
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("unexpected batch results: %w", err)
+	}
+
+	results := make(map[string]*emaillistchecker.VerifyResponse, len(list))
+	for i := range list {
+		results[strings.ToLower(strings.TrimSpace(list[i].Email))] = &list[i]
+	}
+	return results, nil
+}
+
+// writeCleanOutputs joins results onto the original rows and writes them to the
+// keep, review and remove files. Rows without a result are reviewed. Rows with
+// more cells than the header keep them; the header gets unnamed columns so the
+// added columns still line up.
+func writeCleanOutputs(dir, base string, header []string, rows [][]string, emailCol int,
+	results map[string]*emaillistchecker.VerifyResponse, policy *emaillistchecker.Policy) (map[emaillistchecker.Action]int, error) {
+
+	writers := make(map[emaillistchecker.Action]*csv.Writer)
+	counts := make(map[emaillistchecker.Action]int)
+
+	width := len(header)
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	header = append(append([]string{}, header...), make([]string, width-len(header))...)
+
+	for _, action := range []emaillistchecker.Action{emaillistchecker.ActionKeep, emaillistchecker.ActionReview, emaillistchecker.ActionRemove} {
+		f, err := os.Create(cleanOutputPath(dir, base, action))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		w := csv.NewWriter(f)
+		if err := w.Write(append(append([]string{}, header...), cleanColumns...)); err != nil {
+			return nil, err
+		}
+		writers[action] = w
+		counts[action] = 0
+	}
+
+	for _, row := range rows {
+		// Pad short rows so the added columns line up
+		out := make([]string, width, width+len(cleanColumns))
+		copy(out, row)
+
+		var result *emaillistchecker.VerifyResponse
+		if emailCol < len(row) {
+			result = results[strings.ToLower(strings.TrimSpace(row[emailCol]))]
+		}
+
+		action := emaillistchecker.ActionReview
+		if result != nil {
+			action = policy.Decide(result)
+			out = append(out,
+				result.Result,
+				strconv.FormatFloat(result.Score, 'f', 2, 64),
+				result.Reason,
+				strconv.FormatBool(result.Disposable),
+				strconv.FormatBool(result.Role),
+				string(action),
+			)
+		} else {
+			out = append(out, "", "", "", "", "", string(action))
+		}
+
+		if err := writers[action].Write(out); err != nil {
+			return nil, err
+		}
+		counts[action]++
+	}
+
+	for _, w := range writers {
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, err
+		}
+	}
+	return counts, nil
+}
+
+func cleanOutputPath(dir, base string, action emaillistchecker.Action) string {
+	return filepath.Join(dir, base+"."+string(action)+".csv")
+}
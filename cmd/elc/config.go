@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+)
+
+// Environment variables read by elc
+const (
+	envAPIKey  = "ELC_API_KEY"
+	envBaseURL = "ELC_BASE_URL"
+	envConfig  = "ELC_CONFIG"
+)
+
+// config is the on-disk configuration file
+type config struct {
+	APIKey  string `json:"api_key"`
+	BaseURL string `json:"base_url"`
+	Timeout string `json:"timeout"`
+}
+
+// globalFlags are accepted by every subcommand
+type globalFlags struct {
+	apiKey     string
+	baseURL    string
+	configPath string
+	output     string
+	timeout    time.Duration
+}
+
+// newFlagSet creates a subcommand flag set with the global flags registered
+func newFlagSet(e *env, name, usage string) (*flag.FlagSet, *globalFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+
+	g := &globalFlags{}
+	fs.StringVar(&g.apiKey, "api-key", "", "API key (default $"+envAPIKey+" or config file)")
+	fs.StringVar(&g.baseURL, "base-url", "", "API base URL (default $"+envBaseURL+", config file or "+emaillistchecker.DefaultBaseURL+")")
+	fs.StringVar(&g.configPath, "config", "", "config file (default $"+envConfig+" or ~/.config/elc/config.json)")
+	fs.StringVar(&g.output, "o", formatTable, "output format: table, json, csv or ndjson")
+	fs.DurationVar(&g.timeout, "http-timeout", 0, "HTTP request timeout (default 30s)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: elc %s\n\nFlags:\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs, g
+}
+
+// client builds an API client from flags, environment and config file, in that order of precedence
+func (g *globalFlags) client(e *env) (*emaillistchecker.Client, error) {
+	if err := checkFormat(g.output); err != nil {
+		return nil, err
+	}
+
+	cfg, err := loadConfig(e, g.configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := firstNonEmpty(g.apiKey, e.getenv(envAPIKey), cfg.APIKey)
+	if apiKey == "" {
+		return nil, fmt.Errorf("no API key: set $%s, pass -api-key or add api_key to the config file", envAPIKey)
+	}
+
+	baseURL := firstNonEmpty(g.baseURL, e.getenv(envBaseURL), cfg.BaseURL, emaillistchecker.DefaultBaseURL)
+
+	timeout := g.timeout
+	if timeout == 0 && cfg.Timeout != "" {
+		timeout, err = time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("config: invalid timeout %q: %w", cfg.Timeout, err)
+		}
+	}
+	if timeout == 0 {
+		timeout = emaillistchecker.DefaultTimeout
+	}
+
+	return emaillistchecker.NewClientWithConfig(apiKey, baseURL, timeout), nil
+}
+
+// loadConfig reads the config file. A missing file at the default location is not an error.
+func loadConfig(e *env, path string) (*config, error) {
+	explicit := true
+	if path == "" {
+		path = e.getenv(envConfig)
+	}
+	if path == "" {
+		explicit = false
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return &config{}, nil
+		}
+		path = filepath.Join(dir, "elc", "config.json")
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return &config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	cfg := &config{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+)
+
+func runFind(e *env, args []string) (int, error) {
+	if len(args) == 0 {
+		return exitUsage, newUsageError("usage: elc find email|domain|company [flags] ...")
+	}
+
+	switch args[0] {
+	case "email":
+		return runFindEmail(e, args[1:])
+	case "domain":
+		return runFindDomain(e, args[1:])
+	case "company":
+		return runFindCompany(e, args[1:])
+	}
+	return exitUsage, newUsageError("unknown find command %q", args[0])
+}
+
+func runFindEmail(e *env, args []string) (int, error) {
+	fs, g := newFlagSet(e, "find email", "find email [flags] FIRST_NAME LAST_NAME DOMAIN")
+	verifyTop := fs.Int("verify-top", 0, "verify the N most likely alternatives")
+	raw := fs.Bool("raw-names", false, "send names as given instead of normalizing them")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if fs.NArg() != 3 {
+		fs.Usage()
+		return exitUsage, newUsageError("expected FIRST_NAME LAST_NAME DOMAIN")
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	result, err := client.FindEmailWithOptions(fs.Arg(0), fs.Arg(1), fs.Arg(2), emaillistchecker.FindEmailOptions{
+		VerifyTop:     *verifyTop,
+		SMTPCheck:     true,
+		SkipNormalize: *raw,
+	})
+	if err != nil {
+		return exitError, err
+	}
+
+	t := &table{
+		headers: []string{"email", "confidence", "pattern", "verified"},
+		value:   result,
+	}
+	t.rows = append(t.rows, []string{
+		result.Email,
+		strconv.FormatFloat(result.Confidence, 'f', -1, 64),
+		result.Pattern,
+		strconv.FormatBool(result.Verified),
+	})
+	for _, alt := range result.Alternatives {
+		verified := strconv.FormatBool(alt.Verified)
+		if alt.Verification != nil {
+			verified = alt.Verification.Result
+		}
+		t.rows = append(t.rows, []string{
+			alt.Email,
+			strconv.FormatFloat(alt.Confidence, 'f', -1, 64),
+			alt.Pattern,
+			verified,
+		})
+	}
+	return exitOK, render(e.stdout, g.output, t)
+}
+
+func runFindDomain(e *env, args []string) (int, error) {
+	fs, g := newFlagSet(e, "find domain", "find domain [flags] DOMAIN")
+	limit := fs.Int("limit", 10, "maximum number of addresses")
+	offset := fs.Int("offset", 0, "number of addresses to skip")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage, newUsageError("expected DOMAIN")
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	data, err := client.FindByDomain(fs.Arg(0), *limit, *offset)
+	if err != nil {
+		return exitError, err
+	}
+
+	if emails, ok := data["emails"].([]interface{}); ok && g.output != formatJSON {
+		return exitOK, render(e.stdout, g.output, recordsTable(emails, nil))
+	}
+	return exitOK, render(e.stdout, g.output, keyValueTable(data))
+}
+
+func runFindCompany(e *env, args []string) (int, error) {
+	fs, g := newFlagSet(e, "find company", "find company [flags] COMPANY")
+	limit := fs.Int("limit", 10, "maximum number of results")
+	resolve := fs.Bool("resolve", false, "rank possible domains and check their MX records")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage, newUsageError("expected COMPANY")
+	}
+	company := strings.Join(fs.Args(), " ")
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	if !*resolve {
+		data, err := client.FindByCompany(company, *limit)
+		if err != nil {
+			return exitError, err
+		}
+		return exitOK, render(e.stdout, g.output, keyValueTable(data))
+	}
+
+	resolution, err := client.ResolveCompanyDomain(context.Background(), company)
+	if err != nil {
+		return exitError, err
+	}
+
+	t := &table{headers: []string{"domain", "confidence", "mx", "evidence"}, value: resolution}
+	for _, c := range resolution.Candidates {
+		t.rows = append(t.rows, []string{
+			c.Domain,
+			strconv.FormatFloat(c.Confidence, 'f', 2, 64),
+			strings.Join(c.MXRecords, " "),
+			strings.Join(c.Evidence, "; "),
+		})
+	}
+	return exitOK, render(e.stdout, g.output, t)
+}
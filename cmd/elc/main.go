@@ -0,0 +1,137 @@
+// Command elc is a command-line client for the EmailListChecker API.
+//
+// Usage:
+//
+//	elc <command> [flags] [arguments]
+//
+// Commands:
+//
+//	verify EMAIL...                      verify one or more addresses
+//	batch submit|estimate|status|wait|results  manage batch verifications
+//	find email|domain|company            run the email finder
+//	credits                              show the credit balance
+//	usage                                show API usage statistics
+//	lists [show|rename|download|delete]  manage verification lists
+//	clean INPUT.csv                      verify a CSV and split it into keep/review/remove files
+//
+// The API key is read from the -api-key flag, the ELC_API_KEY environment
+// variable or the config file (~/.config/elc/config.json), in that order.
+//
+// verify exits with a status that reflects the worst result so scripts can
+// branch on it: 0 deliverable, 3 risky, 4 undeliverable, 5 unknown.
+// Errors exit with 1 and usage mistakes with 2.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes
+const (
+	exitOK            = 0
+	exitError         = 1
+	exitUsage         = 2
+	exitRisky         = 3
+	exitUndeliverable = 4
+	exitUnknown       = 5
+)
+
+// command is a top-level elc subcommand
+type command struct {
+	name    string
+	summary string
+	run     func(env *env, args []string) (int, error)
+}
+
+var commands = []command{
+	{"verify", "verify one or more email addresses", runVerify},
+	{"batch", "submit, inspect and download batch verifications", runBatch},
+	{"find", "find addresses by name, domain or company", runFind},
+	{"credits", "show the credit balance", runCredits},
+	{"usage", "show API usage statistics", runUsage},
+	{"lists", "manage verification lists", runLists},
+	{"clean", "verify a CSV export and split it by policy", runClean},
+}
+
+// env carries the process streams so commands can be exercised without a terminal
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+}
+
+// usageError is returned for invalid command-line usage
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func newUsageError(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+func main() {
+	e := &env{
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		getenv: os.Getenv,
+	}
+	os.Exit(run(e, os.Args[1:]))
+}
+
+// run dispatches to a subcommand and converts its outcome into an exit code
+func run(e *env, args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		printUsage(e.stderr)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+
+		code, err := cmd.run(e, args[1:])
+		if err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return exitOK
+			}
+			fmt.Fprintf(e.stderr, "elc %s: %v\n", cmd.name, err)
+
+			// Flag parse errors come back with exitUsage rather than a usageError
+			var usage *usageError
+			if errors.As(err, &usage) || code == exitUsage {
+				return exitUsage
+			}
+			return exitError
+		}
+		return code
+	}
+
+	fmt.Fprintf(e.stderr, "elc: unknown command %q\n\n", args[0])
+	printUsage(e.stderr)
+	return exitUsage
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: elc <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'elc <command> -h' for command flags.")
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+// testEnv runs elc against srv with the API key and base URL taken from the
+// environment and an empty config file
+func testEnv(t *testing.T, srv *emaillistcheckertest.Server, stdin string) (*env, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	vars := map[string]string{
+		envAPIKey:  emaillistcheckertest.APIKey,
+		envBaseURL: srv.URL,
+		envConfig:  configPath,
+	}
+	var stdout, stderr bytes.Buffer
+	return &env{
+		stdin:  strings.NewReader(stdin),
+		stdout: &stdout,
+		stderr: &stderr,
+		getenv: func(key string) string { return vars[key] },
+	}, &stdout, &stderr
+}
+
+func TestExitCodes(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{nil, exitUsage},
+		{[]string{"help"}, exitOK},
+		{[]string{"nope"}, exitUsage},
+		{[]string{"verify"}, exitUsage},
+		{[]string{"verify", "-bogus", "jane@example.com"}, exitUsage},
+		{[]string{"verify", "jane@example.com"}, exitOK},
+		{[]string{"verify", "risky@example.com"}, exitRisky},
+		{[]string{"verify", "unknown@example.com"}, exitUnknown},
+		{[]string{"verify", "undeliverable@example.com"}, exitUndeliverable},
+		// The worst result wins
+		{[]string{"verify", "risky@example.com", "undeliverable@example.com", "jane@example.com"}, exitUndeliverable},
+		{[]string{"verify", "-o", "xml", "jane@example.com"}, exitUsage},
+		{[]string{"verify", "-api-key", "wrong", "jane@example.com"}, exitError},
+		{[]string{"credits"}, exitOK},
+		{[]string{"lists", "-o", "json"}, exitOK},
+	}
+	for _, tt := range tests {
+		e, _, stderr := testEnv(t, srv, "")
+		if got := run(e, tt.args); got != tt.want {
+			t.Errorf("elc %s = %d, want %d (stderr: %s)", strings.Join(tt.args, " "), got, tt.want, stderr)
+		}
+	}
+}
+
+func TestVerifyStdin(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+
+	e, stdout, stderr := testEnv(t, srv, "jane@example.com\nundeliverable@example.com\nJane@example.com\n")
+	if got := run(e, []string{"verify", "-unique", "-"}); got != exitOK {
+		t.Fatalf("elc verify - = %d, want %d (stderr: %s)", got, exitOK, stderr)
+	}
+	if lines := strings.Count(stdout.String(), "\n"); lines != 2 {
+		t.Errorf("got %d NDJSON lines, want 2:\n%s", lines, stdout)
+	}
+
+	srv.Fail("/verify", emaillistcheckertest.Fault{Status: 500})
+	e, _, stderr = testEnv(t, srv, "jane@example.com\n")
+	if got := run(e, []string{"verify", "-"}); got != exitError {
+		t.Errorf("elc verify - with a failing API = %d, want %d (stderr: %s)", got, exitError, stderr)
+	}
+}
+
+// failWriter fails every write, like stdout after the reader closed the pipe
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) {
+	return 0, os.ErrClosed
+}
+
+func TestVerifyStdinStopsOnWriteError(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+
+	var input strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&input, "user%d@example.com\n", i)
+	}
+	e, _, _ := testEnv(t, srv, input.String())
+	e.stdout = failWriter{}
+
+	if got := run(e, []string{"verify", "-concurrency", "2", "-"}); got != exitError {
+		t.Errorf("elc verify - = %d, want %d", got, exitError)
+	}
+	if n := len(srv.Requests()); n > 10 {
+		t.Errorf("sent %d requests after the first write error, want verification to stop", n)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats
+const (
+	formatTable  = "table"
+	formatJSON   = "json"
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+)
+
+func checkFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatCSV, formatNDJSON:
+		return nil
+	}
+	return newUsageError("unknown output format %q (want table, json, csv or ndjson)", format)
+}
+
+// table is tabular output; value is what gets encoded in JSON mode
+type table struct {
+	headers []string
+	rows    [][]string
+	value   interface{}
+}
+
+// render writes t to w in the requested format
+func render(w io.Writer, format string, t *table) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(t.value)
+
+	case formatNDJSON:
+		// One object per row, keyed by column name
+		enc := json.NewEncoder(w)
+		for _, row := range t.rows {
+			obj := make(map[string]string, len(row))
+			for i, cell := range row {
+				if i < len(t.headers) {
+					obj[t.headers[i]] = cell
+				}
+			}
+			if err := enc.Encode(obj); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(t.headers); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(t.rows); err != nil {
+			return err
+		}
+		return cw.Error()
+
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(t.headers, "\t")))
+		for _, row := range t.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+// keyValueTable renders an untyped object as FIELD/VALUE rows, sorted by key
+func keyValueTable(data map[string]interface{}) *table {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	t := &table{headers: []string{"field", "value"}, value: data}
+	for _, k := range keys {
+		t.rows = append(t.rows, []string{k, formatValue(data[k])})
+	}
+	return t
+}
+
+// recordsTable renders a list of objects with the given columns; when columns is
+// empty, the union of all keys is used
+func recordsTable(records []interface{}, columns []string) *table {
+	if len(columns) == 0 {
+		seen := map[string]bool{}
+		for _, r := range records {
+			if m, ok := r.(map[string]interface{}); ok {
+				for k := range m {
+					if !seen[k] {
+						seen[k] = true
+						columns = append(columns, k)
+					}
+				}
+			}
+		}
+		sort.Strings(columns)
+	}
+
+	t := &table{headers: columns, value: records}
+	for _, r := range records {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			t.rows = append(t.rows, []string{formatValue(r)})
+			continue
+		}
+		row := make([]string, len(columns))
+		for i, col := range columns {
+			row[i] = formatValue(m[col])
+		}
+		t.rows = append(t.rows, row)
+	}
+	return t
+}
+
+// formatValue renders a decoded JSON value for a table cell
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = formatValue(item)
+		}
+		return strings.Join(parts, ", ")
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
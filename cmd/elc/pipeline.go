@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+)
+
+// pipelineOptions configures `elc verify -`
+type pipelineOptions struct {
+	column      string
+	policyPath  string
+	unique      bool
+	cacheTTL    time.Duration
+	concurrency int
+	timeout     *int
+	smtpCheck   bool
+}
+
+// pipelineRecord is one NDJSON output line of `elc verify -`
+type pipelineRecord struct {
+	Input string `json:"input"`
+	*emaillistchecker.VerifyResponse
+	Action string `json:"action,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// runVerifyPipeline verifies addresses as they are read from stdin and streams
+// the results to stdout in input order, so it works on unbounded input such as
+// `tail -f`. It exits with exitError if any address failed, and stops reading
+// at the first write error, such as a closed pipe. With opts.unique every
+// distinct address is kept in memory for the life of the run.
+func runVerifyPipeline(e *env, g *globalFlags, opts pipelineOptions) (int, error) {
+	if g.output != formatNDJSON && g.output != formatCSV {
+		return exitUsage, newUsageError("reading from stdin supports -o ndjson or -o csv")
+	}
+
+	var policy *emaillistchecker.Policy
+	if opts.policyPath != "" {
+		var err error
+		policy, err = emaillistchecker.LoadPolicy(opts.policyPath)
+		if err != nil {
+			return exitError, err
+		}
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+	if opts.cacheTTL > 0 {
+		client.SetCache(emaillistchecker.NewMemoryCache(opts.cacheTTL))
+	}
+
+	out := newPipelineWriter(e.stdout, g.output, policy != nil)
+	if err := out.writeHeader(); err != nil {
+		return exitError, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	emails := make(chan string)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(emails)
+		readErr <- scanAddresses(e.stdin, opts.column, func(email string) bool {
+			select {
+			case emails <- email:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+
+	code := exitOK
+	var writeErr error
+	seen := make(map[string]bool)
+
+	client.VerifyStream(ctx, emails, emaillistchecker.VerifyManyOptions{
+		Concurrency: opts.concurrency,
+		Timeout:     opts.timeout,
+		SMTPCheck:   opts.smtpCheck,
+		OnResult: func(r emaillistchecker.VerifyManyResult) {
+			key := strings.ToLower(r.Email)
+			if writeErr != nil || (opts.unique && seen[key]) {
+				return
+			}
+			seen[key] = true
+
+			record := pipelineRecord{Input: r.Email, VerifyResponse: r.Result}
+			if r.Err != nil {
+				record.Error = r.Err.Error()
+				code = exitError
+			} else if policy != nil {
+				record.Action = string(policy.Decide(r.Result))
+			}
+			if writeErr = out.write(record); writeErr != nil {
+				cancel()
+			}
+		},
+	})
+
+	if writeErr != nil {
+		return exitError, writeErr
+	}
+	if err := <-readErr; err != nil {
+		return exitError, err
+	}
+	return code, out.flush()
+}
+
+// scanAddresses calls emit for each address as it is read: one per line, or
+// the named column of a CSV with a header row. Reading stops early when emit
+// returns false.
+func scanAddresses(r io.Reader, column string, emit func(string) bool) error {
+	if column == "" {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" && !emit(line) {
+				return nil
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return nil
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	index := -1
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), column) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("column %q not found in CSV header", column)
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if index < len(record) {
+			if email := strings.TrimSpace(record[index]); email != "" && !emit(email) {
+				return nil
+			}
+		}
+	}
+}
+
+// pipelineWriter streams pipeline records as NDJSON or CSV
+type pipelineWriter struct {
+	json       *json.Encoder
+	csv        *csv.Writer
+	withAction bool
+}
+
+func newPipelineWriter(w io.Writer, format string, withAction bool) *pipelineWriter {
+	if format == formatNDJSON {
+		return &pipelineWriter{json: json.NewEncoder(w), withAction: withAction}
+	}
+	return &pipelineWriter{csv: csv.NewWriter(w), withAction: withAction}
+}
+
+func (w *pipelineWriter) writeHeader() error {
+	if w.csv == nil {
+		return nil
+	}
+	header := append([]string{"input"}, verifyColumns...)
+	if w.withAction {
+		header = append(header, "action")
+	}
+	return w.csv.Write(append(header, "error"))
+}
+
+func (w *pipelineWriter) write(r pipelineRecord) error {
+	if w.json != nil {
+		return w.json.Encode(r)
+	}
+
+	row := []string{r.Input}
+	if r.VerifyResponse != nil {
+		row = append(row, verifyRow(r.VerifyResponse)...)
+	} else {
+		row = append(row, make([]string, len(verifyColumns))...)
+	}
+	if w.withAction {
+		row = append(row, r.Action)
+	}
+	if err := w.csv.Write(append(row, r.Error)); err != nil {
+		return err
+	}
+	// Flush each line so downstream tools see results as they arrive
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *pipelineWriter) flush() error {
+	if w.csv == nil {
+		return nil
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
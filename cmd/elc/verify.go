@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+)
+
+// verifyColumns are the columns shown for verification results
+var verifyColumns = []string{"email", "result", "reason", "score", "disposable", "role", "free"}
+
+func runVerify(e *env, args []string) (int, error) {
+	fs, g := newFlagSet(e, "verify", "verify [flags] (EMAIL... | -)")
+	smtpCheck := fs.Bool("smtp", true, "perform an SMTP check")
+	timeout := fs.Int("timeout", 0, "per-address verification timeout in seconds (default: server default)")
+	concurrency := fs.Int("concurrency", 5, "addresses verified in parallel when reading stdin")
+	policyPath := fs.String("policy", "", "policy file used to add a keep/review/remove action to stdin results")
+	column := fs.String("column", "", "read addresses from this column of CSV input on stdin")
+	unique := fs.Bool("unique", false, "when reading stdin, output each address only once (keeps every address in memory)")
+	cacheTTL := fs.Duration("cache", 0, "when reading stdin, reuse results for repeated addresses for this long (default off)")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage, err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage, newUsageError("at least one email address is required")
+	}
+
+	var verifyTimeout *int
+	if *timeout > 0 {
+		verifyTimeout = timeout
+	}
+
+	if fs.NArg() == 1 && fs.Arg(0) == "-" {
+		outputSet := false
+		fs.Visit(func(f *flag.Flag) {
+			outputSet = outputSet || f.Name == "o"
+		})
+		if !outputSet {
+			g.output = formatNDJSON
+		}
+
+		return runVerifyPipeline(e, g, pipelineOptions{
+			column:      *column,
+			policyPath:  *policyPath,
+			unique:      *unique,
+			cacheTTL:    *cacheTTL,
+			concurrency: *concurrency,
+			timeout:     verifyTimeout,
+			smtpCheck:   *smtpCheck,
+		})
+	}
+
+	client, err := g.client(e)
+	if err != nil {
+		return exitError, err
+	}
+
+	code := exitOK
+	var results []*emaillistchecker.VerifyResponse
+	for _, email := range fs.Args() {
+		result, err := client.Verify(email, verifyTimeout, *smtpCheck)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "elc verify: %s: %v\n", email, err)
+			code = exitError
+			continue
+		}
+		results = append(results, result)
+		code = worseExit(code, resultExitCode(result.Result))
+	}
+
+	t := &table{headers: verifyColumns, value: results}
+	for _, r := range results {
+		t.rows = append(t.rows, verifyRow(r))
+	}
+	if err := render(e.stdout, g.output, t); err != nil {
+		return exitError, err
+	}
+
+	return code, nil
+}
+
+// verifyRow formats a verification result for table and CSV output
+func verifyRow(r *emaillistchecker.VerifyResponse) []string {
+	return []string{
+		r.Email,
+		r.Result,
+		r.Reason,
+		strconv.FormatFloat(r.Score, 'f', 2, 64),
+		strconv.FormatBool(r.Disposable),
+		strconv.FormatBool(r.Role),
+		strconv.FormatBool(r.Free),
+	}
+}
+
+// resultExitCode maps a verification result to the verify exit code
+func resultExitCode(result string) int {
+	switch result {
+	case "deliverable":
+		return exitOK
+	case "risky":
+		return exitRisky
+	case "undeliverable":
+		return exitUndeliverable
+	default:
+		return exitUnknown
+	}
+}
+
+// exitSeverity orders exit codes so the worst outcome of several wins
+var exitSeverity = map[int]int{
+	exitOK:            0,
+	exitRisky:         1,
+	exitUnknown:       2,
+	exitUndeliverable: 3,
+	exitError:         4,
+}
+
+func worseExit(a, b int) int {
+	if exitSeverity[b] > exitSeverity[a] {
+		return b
+	}
+	return a
+}
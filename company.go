@@ -0,0 +1,224 @@
+package emaillistchecker
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strings"
+)
+
+// Resolver performs the DNS lookups used for local domain checks.
+// *net.Resolver satisfies it; tests can supply a fake.
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// SetResolver replaces the DNS resolver used for local domain checks (default net.DefaultResolver)
+func (c *Client) SetResolver(resolver Resolver) {
+	c.resolver = resolver
+}
+
+// dnsResolver returns the configured resolver or the system default
+func (c *Client) dnsResolver() Resolver {
+	if c.resolver != nil {
+		return c.resolver
+	}
+	return net.DefaultResolver
+}
+
+// CompanyDomain is a candidate domain for a company with the evidence behind its confidence
+type CompanyDomain struct {
+	Domain     string
+	Confidence float64 // 0.0 - 1.0
+	MXRecords  []string
+	// AcceptsMail is set when the domain has MX records or, failing that, an A/AAAA record
+	AcceptsMail bool
+	Evidence    []string
+}
+
+// CompanyResolution is the result of ResolveCompanyDomain
+type CompanyResolution struct {
+	Company string
+	// Best is the highest-ranked domain that accepts mail, or nil if none does
+	Best       *CompanyDomain
+	Candidates []CompanyDomain
+}
+
+// legalSuffixes are dropped from company names before comparing them with domains
+var legalSuffixes = map[string]bool{
+	"inc": true, "incorporated": true, "llc": true, "llp": true, "ltd": true, "limited": true,
+	"corp": true, "corporation": true, "co": true, "company": true, "plc": true,
+	"gmbh": true, "ag": true, "sa": true, "sas": true, "sarl": true, "bv": true, "nv": true,
+	"srl": true, "spa": true, "oy": true, "ab": true, "as": true, "pty": true, "the": true,
+}
+
+// ResolveCompanyDomain ranks the possible domains FindByCompany reports for a company.
+// Each domain is checked for MX (or, failing that, A/AAAA) records through the client's
+// Resolver and scored on the API ranking, how closely it matches the company name and
+// whether it can receive mail.
+func (c *Client) ResolveCompanyDomain(ctx context.Context, company string) (*CompanyResolution, error) {
+	data, err := c.WithContext(ctx).FindByCompany(company, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	resolution := &CompanyResolution{Company: company}
+	possible := parsePossibleDomains(data["possible_domains"])
+	slug, acronym := companySlug(company)
+
+	for i, p := range possible {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		candidate := CompanyDomain{Domain: p.domain}
+
+		// API ranking: reported confidence if present, otherwise list position.
+		// FindByCompany reports confidence as a percentage (0-100).
+		apiScore := math.Min(p.confidence/100, 1)
+		if apiScore <= 0 {
+			apiScore = 1 - float64(i)/float64(len(possible))
+		}
+		candidate.Evidence = append(candidate.Evidence,
+			fmt.Sprintf("ranked #%d of %d by FindByCompany (score %.2f)", i+1, len(possible), apiScore))
+
+		nameScore := domainNameScore(p.domain, slug, acronym)
+		switch {
+		case nameScore == 1:
+			candidate.Evidence = append(candidate.Evidence, "domain matches company name")
+		case nameScore > 0:
+			candidate.Evidence = append(candidate.Evidence, "domain partially matches company name")
+		}
+
+		mailScore := 0.0
+		mx, err := c.dnsResolver().LookupMX(ctx, p.domain)
+		if err == nil && len(mx) > 0 {
+			mailScore = 1
+			candidate.AcceptsMail = true
+			for _, record := range mx {
+				candidate.MXRecords = append(candidate.MXRecords, strings.TrimSuffix(record.Host, "."))
+			}
+			candidate.Evidence = append(candidate.Evidence,
+				fmt.Sprintf("MX records: %s", strings.Join(candidate.MXRecords, ", ")))
+		} else if hosts, err := c.dnsResolver().LookupHost(ctx, p.domain); err == nil && len(hosts) > 0 {
+			// Mail may be delivered to the A record when no MX exists (RFC 5321 §5.1)
+			mailScore = 0.5
+			candidate.AcceptsMail = true
+			candidate.Evidence = append(candidate.Evidence, "no MX records, falls back to A record")
+		} else {
+			candidate.Evidence = append(candidate.Evidence, "domain does not resolve")
+		}
+
+		candidate.Confidence = 0.4*apiScore + 0.3*nameScore + 0.3*mailScore
+		resolution.Candidates = append(resolution.Candidates, candidate)
+	}
+
+	sort.SliceStable(resolution.Candidates, func(i, j int) bool {
+		return resolution.Candidates[i].Confidence > resolution.Candidates[j].Confidence
+	})
+
+	for i := range resolution.Candidates {
+		if resolution.Candidates[i].AcceptsMail {
+			resolution.Best = &resolution.Candidates[i]
+			break
+		}
+	}
+
+	return resolution, nil
+}
+
+// FindCompanyEmail resolves a company's domain with ResolveCompanyDomain and then
+// looks up the contact's address on it with FindEmail
+func (c *Client) FindCompanyEmail(ctx context.Context, firstName, lastName, company string) (*FinderResult, *CompanyResolution, error) {
+	resolution, err := c.ResolveCompanyDomain(ctx, company)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resolution.Best == nil {
+		return nil, resolution, fmt.Errorf("no mail-enabled domain found for company %q", company)
+	}
+
+	result, err := c.WithContext(ctx).FindEmail(firstName, lastName, resolution.Best.Domain)
+	return result, resolution, err
+}
+
+// possibleDomain is one entry of the possible_domains list
+type possibleDomain struct {
+	domain     string
+	confidence float64
+}
+
+// parsePossibleDomains accepts possible_domains as plain domains or objects with
+// "domain" and "confidence" keys, dropping duplicates
+func parsePossibleDomains(raw interface{}) []possibleDomain {
+	entries, ok := raw.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var domains []possibleDomain
+	for _, entry := range entries {
+		var p possibleDomain
+		switch v := entry.(type) {
+		case string:
+			p.domain = v
+		case map[string]interface{}:
+			p.domain, _ = v["domain"].(string)
+			p.confidence, _ = v["confidence"].(float64)
+		}
+
+		p.domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(p.domain)), ".")
+		p.domain = strings.TrimPrefix(p.domain, "www.")
+		if p.domain == "" || seen[p.domain] {
+			continue
+		}
+		seen[p.domain] = true
+		domains = append(domains, p)
+	}
+
+	return domains
+}
+
+// companySlug reduces a company name to its significant words joined together,
+// along with their acronym, e.g. "Acme Widgets, Inc." -> "acmewidgets", "aw"
+func companySlug(company string) (string, string) {
+	folded, _ := foldName(company)
+	words := strings.FieldsFunc(folded, func(r rune) bool {
+		return r == ' ' || r == '-'
+	})
+
+	var slug, acronym strings.Builder
+	for _, w := range words {
+		if legalSuffixes[w] {
+			continue
+		}
+		slug.WriteString(w)
+		acronym.WriteByte(w[0])
+	}
+	return slug.String(), acronym.String()
+}
+
+// domainNameScore scores how well the registrable label of domain matches the company
+func domainNameScore(domain, slug, acronym string) float64 {
+	label := domain
+	if dot := strings.Index(label, "."); dot >= 0 {
+		label = label[:dot]
+	}
+	label = strings.ReplaceAll(label, "-", "")
+
+	switch {
+	case slug == "" || label == "":
+		return 0
+	case label == slug:
+		return 1
+	case strings.Contains(label, slug) || strings.Contains(slug, label):
+		return 0.7
+	case len(acronym) > 1 && label == acronym:
+		return 0.5
+	}
+	return 0
+}
@@ -0,0 +1,57 @@
+package emaillistchecker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+func TestResolveCompanyDomain(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	tests := []struct {
+		name  string
+		setup func(r *emaillistcheckertest.Resolver)
+		best  string
+	}{
+		{"MX", func(r *emaillistcheckertest.Resolver) {
+			r.SetMX("acme.com", "mx.acme.com")
+			r.SetMX("acme.io", "mx.acme.io")
+		}, "acme.com"},
+		// An A record counts as accepting mail, so the better match still wins
+		{"A record on the top domain", func(r *emaillistcheckertest.Resolver) {
+			r.SetHosts("acme.com", "192.0.2.1")
+			r.SetMX("acme.io", "mx.acme.io")
+		}, "acme.com"},
+		// Mail falls back to the A record when no domain has MX records
+		{"A record only", func(r *emaillistcheckertest.Resolver) {
+			r.SetHosts("acme.io", "192.0.2.1")
+		}, "acme.io"},
+		{"nothing resolves", func(r *emaillistcheckertest.Resolver) {}, ""},
+	}
+	for _, tt := range tests {
+		resolver := emaillistcheckertest.NewResolver()
+		tt.setup(resolver)
+		client.SetResolver(resolver)
+
+		resolution, err := client.ResolveCompanyDomain(context.Background(), "Acme Inc")
+		if err != nil {
+			t.Fatalf("%s: ResolveCompanyDomain: %v", tt.name, err)
+		}
+		best := ""
+		if resolution.Best != nil {
+			best = resolution.Best.Domain
+		}
+		if best != tt.best {
+			t.Errorf("%s: Best = %q, want %q", tt.name, best, tt.best)
+		}
+		for _, c := range resolution.Candidates {
+			if c.Confidence < 0 || c.Confidence > 1 {
+				t.Errorf("%s: %s confidence %.2f is outside 0-1", tt.name, c.Domain, c.Confidence)
+			}
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package emaillistchecker
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// bundledDomains is the domain dataset shipped with the package
+//
+//go:embed domains.txt
+var bundledDomains string
+
+// defaultDomainLists is shared by IsDisposableDomain, IsFreeProvider and
+// clients without their own lists
+var defaultDomainLists = NewDomainLists()
+
+// Domain list file sections
+const (
+	sectionDisposable        = "disposable"
+	sectionFree              = "free"
+	sectionAllow             = "allow"
+	sectionDeny              = "deny"
+	sectionLearnedDisposable = "learned-disposable"
+	sectionLearnedFree       = "learned-free"
+)
+
+// DomainLists classifies domains as disposable or free-mail providers. It
+// starts from the bundled dataset, which LoadFile can replace with a newer
+// one, and layers an overlay on top: domains allowed or denied by the caller
+// and domains learned from API results. A disposable domain also covers its
+// subdomains; the most specific allowed or denied entry wins.
+//
+// Lists are plain text with "# version: X" in the header, "[section]" lines
+// and one domain per line. The sections are disposable and free (which
+// replace the base lists) and allow, deny, learned-disposable and
+// learned-free (which are merged into the overlay).
+type DomainLists struct {
+	mu         sync.RWMutex
+	version    string
+	disposable map[string]bool
+	free       map[string]bool
+
+	allow             map[string]bool
+	deny              map[string]bool
+	learnedDisposable map[string]bool
+	learnedFree       map[string]bool
+}
+
+// NewDomainLists creates lists from the bundled dataset with an empty overlay
+func NewDomainLists() *DomainLists {
+	l := &DomainLists{
+		disposable:        make(map[string]bool),
+		free:              make(map[string]bool),
+		allow:             make(map[string]bool),
+		deny:              make(map[string]bool),
+		learnedDisposable: make(map[string]bool),
+		learnedFree:       make(map[string]bool),
+	}
+	if err := l.Load(strings.NewReader(bundledDomains)); err != nil {
+		panic("emaillistchecker: bundled domain lists: " + err.Error())
+	}
+	return l
+}
+
+// DefaultDomainLists returns the lists used by IsDisposableDomain,
+// IsFreeProvider and clients without SetDomainLists. Loading into them
+// affects all of those.
+func DefaultDomainLists() *DomainLists {
+	return defaultDomainLists
+}
+
+// IsDisposableDomain reports whether domain, or a parent of it, is a
+// disposable mail provider according to DefaultDomainLists
+func IsDisposableDomain(domain string) bool {
+	return defaultDomainLists.IsDisposable(domain)
+}
+
+// IsFreeProvider reports whether domain is a free mail provider according to
+// DefaultDomainLists
+func IsFreeProvider(domain string) bool {
+	return defaultDomainLists.IsFree(domain)
+}
+
+// SetDomainLists makes the client use l for local checks such as VerifyLocal
+// and teaches l from every Verify result the API returns. Pass nil to go back
+// to DefaultDomainLists, which the client never modifies.
+func (c *Client) SetDomainLists(l *DomainLists) {
+	c.domains = l
+}
+
+// domainLists returns the configured lists or the shared default
+func (c *Client) domainLists() *DomainLists {
+	if c.domains != nil {
+		return c.domains
+	}
+	return defaultDomainLists
+}
+
+// Version returns the version of the base lists
+func (l *DomainLists) Version() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.version
+}
+
+// IsDisposable reports whether domain, or a parent of it, is disposable
+func (l *DomainLists) IsDisposable(domain string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	disposable, _ := l.classifyLocked(normalizeDomain(domain))
+	return disposable
+}
+
+// classifyLocked reports whether a domain is disposable and whether that was
+// decided by the caller's allow and deny lists rather than the data
+func (l *DomainLists) classifyLocked(domain string) (disposable, overridden bool) {
+	for name := domain; strings.Contains(name, "."); name = name[strings.Index(name, ".")+1:] {
+		switch {
+		case l.allow[name]:
+			return false, true
+		case l.deny[name]:
+			return true, true
+		}
+	}
+	for name := domain; strings.Contains(name, "."); name = name[strings.Index(name, ".")+1:] {
+		if l.learnedDisposable[name] || l.disposable[name] {
+			return true, false
+		}
+	}
+	return false, false
+}
+
+// IsFree reports whether domain is a free mail provider
+func (l *DomainLists) IsFree(domain string) bool {
+	domain = normalizeDomain(domain)
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.free[domain] || l.learnedFree[domain]
+}
+
+// Allow marks domains and their subdomains as never disposable, overriding
+// the base lists and learned results
+func (l *DomainLists) Allow(domains ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, domain := range domains {
+		domain = normalizeDomain(domain)
+		l.allow[domain] = true
+		delete(l.deny, domain)
+	}
+}
+
+// Deny marks domains and their subdomains as disposable
+func (l *DomainLists) Deny(domains ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, domain := range domains {
+		domain = normalizeDomain(domain)
+		l.deny[domain] = true
+		delete(l.allow, domain)
+	}
+}
+
+// Learn adds the domain of an API result to the overlay when the API flags it
+// as disposable or free and the lists do not already say so. Local results,
+// and domains the caller allowed or denied, are ignored.
+//
+// Clients only call Learn on lists given to SetDomainLists; the shared
+// DefaultDomainLists learn nothing unless you call Learn on them yourself.
+func (l *DomainLists) Learn(result *VerifyResponse) {
+	if result == nil || result.Source == SourceLocal || !result.Disposable && !result.Free {
+		return
+	}
+	domain := result.Domain
+	if domain == "" {
+		if at := strings.LastIndex(result.Email, "@"); at >= 0 {
+			domain = result.Email[at+1:]
+		}
+	}
+	domain = normalizeDomain(domain)
+	if !strings.Contains(domain, ".") {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if known, overridden := l.classifyLocked(domain); result.Disposable && !known && !overridden {
+		l.learnedDisposable[domain] = true
+	}
+	if result.Free && !l.free[domain] {
+		l.learnedFree[domain] = true
+	}
+}
+
+// Learned returns the domains learned from API results, sorted
+func (l *DomainLists) Learned() (disposable, free []string) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return sortedDomains(l.learnedDisposable), sortedDomains(l.learnedFree)
+}
+
+// LoadFile reads a list file, for example a newer dataset or a saved overlay
+func (l *DomainLists) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open domain lists: %w", err)
+	}
+	defer file.Close()
+
+	if err := l.Load(file); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Load reads lists in the file format described on DomainLists. Nothing is
+// changed if the input is malformed.
+func (l *DomainLists) Load(r io.Reader) error {
+	version := ""
+	sections := make(map[string]map[string]bool)
+	section := ""
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "#"):
+			if v, ok := cutPrefix(strings.TrimSpace(line[1:]), "version:"); ok {
+				version = strings.TrimSpace(v)
+			}
+		case line == "":
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			switch section {
+			case sectionDisposable, sectionFree, sectionAllow, sectionDeny, sectionLearnedDisposable, sectionLearnedFree:
+			default:
+				return fmt.Errorf("line %d: unknown section %q", n, section)
+			}
+			if sections[section] == nil {
+				sections[section] = make(map[string]bool)
+			}
+		case section == "":
+			return fmt.Errorf("line %d: domain outside a section", n)
+		default:
+			sections[section][normalizeDomain(line)] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if list, ok := sections[sectionDisposable]; ok {
+		l.disposable = list
+		l.version = version
+	}
+	if list, ok := sections[sectionFree]; ok {
+		l.free = list
+		l.version = version
+	}
+	for domain := range sections[sectionAllow] {
+		l.allow[domain] = true
+		delete(l.deny, domain)
+	}
+	for domain := range sections[sectionDeny] {
+		l.deny[domain] = true
+		delete(l.allow, domain)
+	}
+	for domain := range sections[sectionLearnedDisposable] {
+		l.learnedDisposable[domain] = true
+	}
+	for domain := range sections[sectionLearnedFree] {
+		l.learnedFree[domain] = true
+	}
+	return nil
+}
+
+// WriteOverlay writes the allowed, denied and learned domains in the list
+// file format, so they can be saved and loaded again with LoadFile
+func (l *DomainLists) WriteOverlay(w io.Writer) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	for _, section := range []struct {
+		name    string
+		domains map[string]bool
+	}{
+		{sectionAllow, l.allow},
+		{sectionDeny, l.deny},
+		{sectionLearnedDisposable, l.learnedDisposable},
+		{sectionLearnedFree, l.learnedFree},
+	} {
+		if len(section.domains) == 0 {
+			continue
+		}
+		fmt.Fprintf(bw, "[%s]\n", section.name)
+		for _, domain := range sortedDomains(section.domains) {
+			fmt.Fprintln(bw, domain)
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
+
+// normalizeDomain lowercases a domain and strips a trailing dot. Unicode
+// domains are converted to ASCII, so both spellings of an IDN match.
+func normalizeDomain(domain string) string {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if !isASCII(domain) {
+		if ascii, err := DomainToASCII(domain); err == nil {
+			return ascii
+		}
+	}
+	return domain
+}
+
+func sortedDomains(set map[string]bool) []string {
+	domains := make([]string, 0, len(set))
+	for domain := range set {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	return domains
+}
+
+// cutPrefix is strings.CutPrefix, which needs Go 1.20
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
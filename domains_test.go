@@ -0,0 +1,168 @@
+package emaillistchecker_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+func TestBundledDomainLists(t *testing.T) {
+	tests := []struct {
+		domain           string
+		disposable, free bool
+	}{
+		{"mailinator.com", true, false},
+		{"inbox.mailinator.com", true, false},
+		{"MAILINATOR.COM.", true, false},
+		{"gmail.com", false, true},
+		{"cock.li", false, true},
+		{"example.com", false, false},
+	}
+	for _, tt := range tests {
+		if got := emaillistchecker.IsDisposableDomain(tt.domain); got != tt.disposable {
+			t.Errorf("IsDisposableDomain(%q) = %v, want %v", tt.domain, got, tt.disposable)
+		}
+		if got := emaillistchecker.IsFreeProvider(tt.domain); got != tt.free {
+			t.Errorf("IsFreeProvider(%q) = %v, want %v", tt.domain, got, tt.free)
+		}
+	}
+	if emaillistchecker.DefaultDomainLists().Version() == "" {
+		t.Error("bundled lists have no version")
+	}
+}
+
+func TestDomainListsLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "domains.txt")
+	data := "# version: test-1\n\n[disposable]\nthrowaway.example\n\n[allow]\nthrowaway.example\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	lists := emaillistchecker.NewDomainLists()
+	if err := lists.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if v := lists.Version(); v != "test-1" {
+		t.Errorf("Version() = %q, want test-1", v)
+	}
+	// [disposable] replaces the bundled list, so only the allowed domain is in it
+	if lists.IsDisposable("mailinator.com") {
+		t.Error("bundled disposable list was not replaced")
+	}
+	if lists.IsDisposable("throwaway.example") {
+		t.Error("allowed domain reported as disposable")
+	}
+	// [free] was not in the file, so the bundled list stays
+	if !lists.IsFree("gmail.com") {
+		t.Error("bundled free list was dropped")
+	}
+
+	if err := lists.LoadFile(filepath.Join(t.TempDir(), "missing.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: got %v", err)
+	}
+	for _, bad := range []string{"gmail.com\n", "[other]\nx.example\n"} {
+		if err := lists.Load(strings.NewReader(bad)); err == nil {
+			t.Errorf("Load(%q) succeeded", bad)
+		}
+	}
+	if v := lists.Version(); v != "test-1" {
+		t.Errorf("malformed input changed the version to %q", v)
+	}
+}
+
+func TestDomainListsAllowDeny(t *testing.T) {
+	lists := emaillistchecker.NewDomainLists()
+
+	lists.Allow("mailinator.com")
+	lists.Deny("bad.mailinator.com", "corp.example")
+	if lists.IsDisposable("mailinator.com") || lists.IsDisposable("inbox.mailinator.com") {
+		t.Error("allowed domain reported as disposable")
+	}
+	// The most specific entry wins
+	if !lists.IsDisposable("x.bad.mailinator.com") {
+		t.Error("denied subdomain of an allowed domain not reported as disposable")
+	}
+	if !lists.IsDisposable("corp.example") {
+		t.Error("denied domain not reported as disposable")
+	}
+
+	// Deny after Allow moves a domain between the lists, and so does loading
+	lists.Deny("mailinator.com")
+	if !lists.IsDisposable("mailinator.com") {
+		t.Error("Deny did not override an earlier Allow")
+	}
+	if err := lists.Load(strings.NewReader("[allow]\ncorp.example\n")); err != nil {
+		t.Fatal(err)
+	}
+	if lists.IsDisposable("corp.example") {
+		t.Error("loaded [allow] did not override an earlier Deny")
+	}
+
+	var overlay strings.Builder
+	if err := lists.WriteOverlay(&overlay); err != nil {
+		t.Fatal(err)
+	}
+	want := "[allow]\ncorp.example\n\n[deny]\nbad.mailinator.com\nmailinator.com\n\n"
+	if overlay.String() != want {
+		t.Errorf("WriteOverlay() = %q, want %q", overlay.String(), want)
+	}
+
+	reloaded := emaillistchecker.NewDomainLists()
+	if err := reloaded.Load(strings.NewReader(overlay.String())); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.IsDisposable("corp.example") || !reloaded.IsDisposable("mailinator.com") {
+		t.Error("overlay did not round-trip")
+	}
+}
+
+func TestDomainListsLearn(t *testing.T) {
+	lists := emaillistchecker.NewDomainLists()
+	lists.Allow("partner.example")
+
+	lists.Learn(&emaillistchecker.VerifyResponse{Email: "a@throwaway.example", Disposable: true})
+	lists.Learn(&emaillistchecker.VerifyResponse{Email: "a@partner.example", Disposable: true})
+	lists.Learn(&emaillistchecker.VerifyResponse{Email: "a@mailinator.com", Disposable: true})
+	lists.Learn(&emaillistchecker.VerifyResponse{Email: "a@mail.example", Domain: "Mail.Example", Free: true})
+	lists.Learn(&emaillistchecker.VerifyResponse{Email: "a@local.example", Disposable: true, Source: emaillistchecker.SourceLocal})
+
+	disposable, free := lists.Learned()
+	if want := []string{"throwaway.example"}; !reflect.DeepEqual(disposable, want) {
+		t.Errorf("learned disposable = %v, want %v", disposable, want)
+	}
+	if want := []string{"mail.example"}; !reflect.DeepEqual(free, want) {
+		t.Errorf("learned free = %v, want %v", free, want)
+	}
+	if !lists.IsDisposable("throwaway.example") || !lists.IsFree("mail.example") {
+		t.Error("learned domains not applied")
+	}
+}
+
+func TestClientLearnsIntoOwnDomainLists(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+
+	// Without its own lists a client leaves the shared defaults alone
+	client := srv.Client()
+	if _, err := client.Verify("disposable@shared.example", nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if emaillistchecker.IsDisposableDomain("shared.example") {
+		t.Error("client taught the default lists")
+	}
+
+	lists := emaillistchecker.NewDomainLists()
+	client.SetDomainLists(lists)
+	if _, err := client.Verify("disposable@own.example", nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if !lists.IsDisposable("own.example") {
+		t.Error("client did not teach its own lists")
+	}
+}
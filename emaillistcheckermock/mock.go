@@ -0,0 +1,109 @@
+// Package emaillistcheckermock provides mock implementations of the
+// emaillistchecker service interfaces.
+//
+// Every mock records its calls and answers them, in order of preference, from
+// responses queued with the Return* methods, from the matching *Func field, or
+// with an error reporting an unexpected call:
+//
+//	m := &emaillistcheckermock.Verifier{}
+//	m.ReturnVerify(&emaillistchecker.VerifyResponse{Result: "deliverable"}, nil)
+//
+//	signup := NewSignupHandler(m) // accepts an emaillistchecker.Verifier
+//	...
+//	if calls := m.CallsTo("Verify"); len(calls) != 1 { ... }
+package emaillistcheckermock
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Call is a recorded method call
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// recorder stores the calls made to a mock
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns every call made to the mock, in order
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns the calls made to one method
+func (r *recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var calls []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Reset forgets recorded calls
+func (r *recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// scripted is a queued response
+type scripted[T any] struct {
+	value T
+	err   error
+}
+
+// script is a FIFO of queued responses for one method
+type script[T any] struct {
+	mu    sync.Mutex
+	queue []scripted[T]
+}
+
+func (s *script[T]) push(value T, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, scripted[T]{value: value, err: err})
+}
+
+func (s *script[T]) pop() (T, error, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.queue) == 0 {
+		var zero T
+		return zero, nil, false
+	}
+	next := s.queue[0]
+	s.queue = s.queue[1:]
+	return next.value, next.err, true
+}
+
+// UnexpectedCallError is returned when a mock has no response for a call
+type UnexpectedCallError struct {
+	Method string
+}
+
+func (e *UnexpectedCallError) Error() string {
+	return fmt.Sprintf("emaillistcheckermock: unexpected call to %s", e.Method)
+}
+
+func unexpected(method string) error {
+	return &UnexpectedCallError{Method: method}
+}
@@ -0,0 +1,90 @@
+package emaillistcheckermock_test
+
+import (
+	"errors"
+	"testing"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckermock"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+func TestVerifier(t *testing.T) {
+	m := &emaillistcheckermock.Verifier{}
+	var v emaillistchecker.Verifier = m
+
+	// Queued responses come first, in order
+	m.ReturnVerify(&emaillistchecker.VerifyResponse{Result: "deliverable"}, nil)
+	m.ReturnVerifyWithOptions(&emaillistchecker.VerifyResponse{Result: "risky"}, nil)
+	if r, err := v.Verify("jane@example.com", nil, true); err != nil || r.Result != "deliverable" {
+		t.Errorf("Verify = %v, %v, want the queued result", r, err)
+	}
+	opts := emaillistchecker.VerifyOptions{Suggest: emaillistchecker.SuggestInstead}
+	if r, err := v.VerifyWithOptions("jane@gmial.com", opts); err != nil || r.Result != "risky" {
+		t.Errorf("VerifyWithOptions = %v, %v, want the queued result", r, err)
+	}
+
+	// Then the Func field
+	m.VerifyWithOptionsFunc = func(email string, opts emaillistchecker.VerifyOptions) (*emaillistchecker.VerifyResponse, error) {
+		return &emaillistchecker.VerifyResponse{Email: email, Result: "unknown"}, nil
+	}
+	if r, err := v.VerifyWithOptions("john@example.com", opts); err != nil || r.Email != "john@example.com" {
+		t.Errorf("VerifyWithOptions = %v, %v, want the VerifyWithOptionsFunc result", r, err)
+	}
+
+	// Then an error
+	var unexpected *emaillistcheckermock.UnexpectedCallError
+	if _, err := v.Verify("jane@example.com", nil, true); !errors.As(err, &unexpected) || unexpected.Method != "Verify" {
+		t.Errorf("Verify error = %v, want an UnexpectedCallError for Verify", err)
+	}
+
+	calls := m.CallsTo("VerifyWithOptions")
+	if len(calls) != 2 || calls[0].Args[0] != "jane@gmial.com" || calls[0].Args[1] != opts {
+		t.Errorf("VerifyWithOptions calls = %+v", calls)
+	}
+	if n := len(m.Calls()); n != 4 {
+		t.Errorf("recorded %d calls, want 4", n)
+	}
+	m.Reset()
+	if n := len(m.Calls()); n != 0 {
+		t.Errorf("recorded %d calls after Reset, want 0", n)
+	}
+}
+
+func TestBatchVerifier(t *testing.T) {
+	m := &emaillistcheckermock.BatchVerifier{}
+	var v emaillistchecker.BatchVerifier = m
+
+	batch := &emaillistchecker.BatchResponse{ID: 7}
+	failure := errors.New("boom")
+	m.ReturnGetBatchVerifyResults([]emaillistchecker.VerifyResponse{{Email: "jane@example.com"}}, nil)
+	m.ReturnGetBatchVerifyResults(nil, failure)
+
+	if results, err := v.GetBatchVerifyResults(batch, "all"); err != nil || len(results) != 1 {
+		t.Errorf("GetBatchVerifyResults = %v, %v, want the queued results", results, err)
+	}
+	if _, err := v.GetBatchVerifyResults(batch, "valid"); err != failure {
+		t.Errorf("GetBatchVerifyResults error = %v, want the queued error", err)
+	}
+	var unexpected *emaillistcheckermock.UnexpectedCallError
+	if _, err := v.GetBatchVerifyResults(batch, "all"); !errors.As(err, &unexpected) {
+		t.Errorf("GetBatchVerifyResults error = %v, want an UnexpectedCallError", err)
+	}
+
+	calls := m.CallsTo("GetBatchVerifyResults")
+	if len(calls) != 3 || calls[1].Args[0] != batch || calls[1].Args[1] != "valid" {
+		t.Errorf("GetBatchVerifyResults calls = %+v", calls)
+	}
+}
+
+// The mocks and the client are interchangeable behind the interfaces
+func TestClientImplementsInterfaces(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+
+	var v emaillistchecker.Verifier = srv.Client()
+	r, err := v.VerifyWithOptions("jane@example.com", emaillistchecker.VerifyOptions{})
+	if err != nil || r.Result != "deliverable" {
+		t.Errorf("VerifyWithOptions = %v, %v, want deliverable", r, err)
+	}
+}
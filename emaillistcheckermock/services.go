@@ -0,0 +1,408 @@
+package emaillistcheckermock
+
+import (
+	"context"
+	"io"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+)
+
+// Compile-time checks that the mocks implement the service interfaces
+var (
+	_ emaillistchecker.Verifier       = (*Verifier)(nil)
+	_ emaillistchecker.BatchVerifier  = (*BatchVerifier)(nil)
+	_ emaillistchecker.Finder         = (*Finder)(nil)
+	_ emaillistchecker.AccountService = (*AccountService)(nil)
+	_ emaillistchecker.ListService    = (*ListService)(nil)
+)
+
+// Verifier is a mock emaillistchecker.Verifier
+type Verifier struct {
+	recorder
+
+	VerifyFunc            func(email string, timeout *int, smtpCheck bool) (*emaillistchecker.VerifyResponse, error)
+	VerifyWithOptionsFunc func(email string, opts emaillistchecker.VerifyOptions) (*emaillistchecker.VerifyResponse, error)
+
+	verify            script[*emaillistchecker.VerifyResponse]
+	verifyWithOptions script[*emaillistchecker.VerifyResponse]
+}
+
+// ReturnVerify queues the response for the next Verify call
+func (m *Verifier) ReturnVerify(result *emaillistchecker.VerifyResponse, err error) {
+	m.verify.push(result, err)
+}
+
+// ReturnVerifyWithOptions queues the response for the next VerifyWithOptions call
+func (m *Verifier) ReturnVerifyWithOptions(result *emaillistchecker.VerifyResponse, err error) {
+	m.verifyWithOptions.push(result, err)
+}
+
+// Verify implements emaillistchecker.Verifier
+func (m *Verifier) Verify(email string, timeout *int, smtpCheck bool) (*emaillistchecker.VerifyResponse, error) {
+	m.record("Verify", email, timeout, smtpCheck)
+	if v, err, ok := m.verify.pop(); ok {
+		return v, err
+	}
+	if m.VerifyFunc != nil {
+		return m.VerifyFunc(email, timeout, smtpCheck)
+	}
+	return nil, unexpected("Verify")
+}
+
+// VerifyWithOptions implements emaillistchecker.Verifier
+func (m *Verifier) VerifyWithOptions(email string, opts emaillistchecker.VerifyOptions) (*emaillistchecker.VerifyResponse, error) {
+	m.record("VerifyWithOptions", email, opts)
+	if v, err, ok := m.verifyWithOptions.pop(); ok {
+		return v, err
+	}
+	if m.VerifyWithOptionsFunc != nil {
+		return m.VerifyWithOptionsFunc(email, opts)
+	}
+	return nil, unexpected("VerifyWithOptions")
+}
+
+// BatchVerifier is a mock emaillistchecker.BatchVerifier
+type BatchVerifier struct {
+	recorder
+
+	VerifyBatchFunc           func(emails []string, name, callbackURL string, autoStart bool) (*emaillistchecker.BatchResponse, error)
+	VerifyBatchFileFunc       func(filePath string, name, callbackURL *string, autoStart bool) (*emaillistchecker.BatchResponse, error)
+	GetBatchStatusFunc        func(batchID int) (*emaillistchecker.BatchStatusResponse, error)
+	GetBatchResultsFunc       func(batchID int, format, filter string) (interface{}, error)
+	GetBatchVerifyResultsFunc func(batch *emaillistchecker.BatchResponse, filter string) ([]emaillistchecker.VerifyResponse, error)
+
+	verifyBatch           script[*emaillistchecker.BatchResponse]
+	verifyBatchFile       script[*emaillistchecker.BatchResponse]
+	getBatchStatus        script[*emaillistchecker.BatchStatusResponse]
+	getBatchResults       script[interface{}]
+	getBatchVerifyResults script[[]emaillistchecker.VerifyResponse]
+}
+
+// ReturnVerifyBatch queues the response for the next VerifyBatch call
+func (m *BatchVerifier) ReturnVerifyBatch(batch *emaillistchecker.BatchResponse, err error) {
+	m.verifyBatch.push(batch, err)
+}
+
+// ReturnVerifyBatchFile queues the response for the next VerifyBatchFile call
+func (m *BatchVerifier) ReturnVerifyBatchFile(batch *emaillistchecker.BatchResponse, err error) {
+	m.verifyBatchFile.push(batch, err)
+}
+
+// ReturnGetBatchStatus queues the response for the next GetBatchStatus call
+func (m *BatchVerifier) ReturnGetBatchStatus(status *emaillistchecker.BatchStatusResponse, err error) {
+	m.getBatchStatus.push(status, err)
+}
+
+// ReturnGetBatchResults queues the response for the next GetBatchResults call
+func (m *BatchVerifier) ReturnGetBatchResults(results interface{}, err error) {
+	m.getBatchResults.push(results, err)
+}
+
+// ReturnGetBatchVerifyResults queues the response for the next GetBatchVerifyResults call
+func (m *BatchVerifier) ReturnGetBatchVerifyResults(results []emaillistchecker.VerifyResponse, err error) {
+	m.getBatchVerifyResults.push(results, err)
+}
+
+// VerifyBatch implements emaillistchecker.BatchVerifier
+func (m *BatchVerifier) VerifyBatch(emails []string, name, callbackURL string, autoStart bool) (*emaillistchecker.BatchResponse, error) {
+	m.record("VerifyBatch", emails, name, callbackURL, autoStart)
+	if v, err, ok := m.verifyBatch.pop(); ok {
+		return v, err
+	}
+	if m.VerifyBatchFunc != nil {
+		return m.VerifyBatchFunc(emails, name, callbackURL, autoStart)
+	}
+	return nil, unexpected("VerifyBatch")
+}
+
+// VerifyBatchFile implements emaillistchecker.BatchVerifier
+func (m *BatchVerifier) VerifyBatchFile(filePath string, name, callbackURL *string, autoStart bool) (*emaillistchecker.BatchResponse, error) {
+	m.record("VerifyBatchFile", filePath, name, callbackURL, autoStart)
+	if v, err, ok := m.verifyBatchFile.pop(); ok {
+		return v, err
+	}
+	if m.VerifyBatchFileFunc != nil {
+		return m.VerifyBatchFileFunc(filePath, name, callbackURL, autoStart)
+	}
+	return nil, unexpected("VerifyBatchFile")
+}
+
+// GetBatchStatus implements emaillistchecker.BatchVerifier
+func (m *BatchVerifier) GetBatchStatus(batchID int) (*emaillistchecker.BatchStatusResponse, error) {
+	m.record("GetBatchStatus", batchID)
+	if v, err, ok := m.getBatchStatus.pop(); ok {
+		return v, err
+	}
+	if m.GetBatchStatusFunc != nil {
+		return m.GetBatchStatusFunc(batchID)
+	}
+	return nil, unexpected("GetBatchStatus")
+}
+
+// GetBatchResults implements emaillistchecker.BatchVerifier
+func (m *BatchVerifier) GetBatchResults(batchID int, format, filter string) (interface{}, error) {
+	m.record("GetBatchResults", batchID, format, filter)
+	if v, err, ok := m.getBatchResults.pop(); ok {
+		return v, err
+	}
+	if m.GetBatchResultsFunc != nil {
+		return m.GetBatchResultsFunc(batchID, format, filter)
+	}
+	return nil, unexpected("GetBatchResults")
+}
+
+// GetBatchVerifyResults implements emaillistchecker.BatchVerifier
+func (m *BatchVerifier) GetBatchVerifyResults(batch *emaillistchecker.BatchResponse, filter string) ([]emaillistchecker.VerifyResponse, error) {
+	m.record("GetBatchVerifyResults", batch, filter)
+	if v, err, ok := m.getBatchVerifyResults.pop(); ok {
+		return v, err
+	}
+	if m.GetBatchVerifyResultsFunc != nil {
+		return m.GetBatchVerifyResultsFunc(batch, filter)
+	}
+	return nil, unexpected("GetBatchVerifyResults")
+}
+
+// Finder is a mock emaillistchecker.Finder
+type Finder struct {
+	recorder
+
+	FindEmailFunc     func(firstName, lastName, domain string) (*emaillistchecker.FinderResult, error)
+	FindByDomainFunc  func(domain string, limit, offset int) (map[string]interface{}, error)
+	FindByCompanyFunc func(company string, limit int) (map[string]interface{}, error)
+
+	findEmail     script[*emaillistchecker.FinderResult]
+	findByDomain  script[map[string]interface{}]
+	findByCompany script[map[string]interface{}]
+}
+
+// ReturnFindEmail queues the response for the next FindEmail call
+func (m *Finder) ReturnFindEmail(result *emaillistchecker.FinderResult, err error) {
+	m.findEmail.push(result, err)
+}
+
+// ReturnFindByDomain queues the response for the next FindByDomain call
+func (m *Finder) ReturnFindByDomain(data map[string]interface{}, err error) {
+	m.findByDomain.push(data, err)
+}
+
+// ReturnFindByCompany queues the response for the next FindByCompany call
+func (m *Finder) ReturnFindByCompany(data map[string]interface{}, err error) {
+	m.findByCompany.push(data, err)
+}
+
+// FindEmail implements emaillistchecker.Finder
+func (m *Finder) FindEmail(firstName, lastName, domain string) (*emaillistchecker.FinderResult, error) {
+	m.record("FindEmail", firstName, lastName, domain)
+	if v, err, ok := m.findEmail.pop(); ok {
+		return v, err
+	}
+	if m.FindEmailFunc != nil {
+		return m.FindEmailFunc(firstName, lastName, domain)
+	}
+	return nil, unexpected("FindEmail")
+}
+
+// FindByDomain implements emaillistchecker.Finder
+func (m *Finder) FindByDomain(domain string, limit, offset int) (map[string]interface{}, error) {
+	m.record("FindByDomain", domain, limit, offset)
+	if v, err, ok := m.findByDomain.pop(); ok {
+		return v, err
+	}
+	if m.FindByDomainFunc != nil {
+		return m.FindByDomainFunc(domain, limit, offset)
+	}
+	return nil, unexpected("FindByDomain")
+}
+
+// FindByCompany implements emaillistchecker.Finder
+func (m *Finder) FindByCompany(company string, limit int) (map[string]interface{}, error) {
+	m.record("FindByCompany", company, limit)
+	if v, err, ok := m.findByCompany.pop(); ok {
+		return v, err
+	}
+	if m.FindByCompanyFunc != nil {
+		return m.FindByCompanyFunc(company, limit)
+	}
+	return nil, unexpected("FindByCompany")
+}
+
+// AccountService is a mock emaillistchecker.AccountService
+type AccountService struct {
+	recorder
+
+	GetCreditsFunc func() (map[string]interface{}, error)
+	GetUsageFunc   func(ctx context.Context, q emaillistchecker.UsageQuery) (*emaillistchecker.UsageReport, error)
+
+	getCredits script[map[string]interface{}]
+	getUsage   script[*emaillistchecker.UsageReport]
+}
+
+// ReturnGetCredits queues the response for the next GetCredits call
+func (m *AccountService) ReturnGetCredits(data map[string]interface{}, err error) {
+	m.getCredits.push(data, err)
+}
+
+// ReturnGetUsage queues the response for the next GetUsage call
+func (m *AccountService) ReturnGetUsage(report *emaillistchecker.UsageReport, err error) {
+	m.getUsage.push(report, err)
+}
+
+// GetCredits implements emaillistchecker.AccountService
+func (m *AccountService) GetCredits() (map[string]interface{}, error) {
+	m.record("GetCredits")
+	if v, err, ok := m.getCredits.pop(); ok {
+		return v, err
+	}
+	if m.GetCreditsFunc != nil {
+		return m.GetCreditsFunc()
+	}
+	return nil, unexpected("GetCredits")
+}
+
+// GetUsage implements emaillistchecker.AccountService
+func (m *AccountService) GetUsage(ctx context.Context, q emaillistchecker.UsageQuery) (*emaillistchecker.UsageReport, error) {
+	m.record("GetUsage", q)
+	if v, err, ok := m.getUsage.pop(); ok {
+		return v, err
+	}
+	if m.GetUsageFunc != nil {
+		return m.GetUsageFunc(ctx, q)
+	}
+	return nil, unexpected("GetUsage")
+}
+
+// ListService is a mock emaillistchecker.ListService
+type ListService struct {
+	recorder
+
+	GetListsFunc     func() ([]emaillistchecker.List, error)
+	GetListFunc      func(listID int) (*emaillistchecker.List, error)
+	RenameListFunc   func(listID int, name string) (*emaillistchecker.List, error)
+	DownloadListFunc func(listID int, format, filter string, w io.Writer) error
+	DeleteListFunc   func(listID int) error
+	DeleteListsFunc  func(listIDs []int, force bool) error
+
+	getLists     script[[]emaillistchecker.List]
+	getList      script[*emaillistchecker.List]
+	renameList   script[*emaillistchecker.List]
+	downloadList script[[]byte]
+	deleteList   script[struct{}]
+	deleteLists  script[struct{}]
+}
+
+// ReturnGetLists queues the response for the next GetLists call
+func (m *ListService) ReturnGetLists(lists []emaillistchecker.List, err error) {
+	m.getLists.push(lists, err)
+}
+
+// ReturnGetList queues the response for the next GetList call
+func (m *ListService) ReturnGetList(list *emaillistchecker.List, err error) {
+	m.getList.push(list, err)
+}
+
+// ReturnRenameList queues the response for the next RenameList call
+func (m *ListService) ReturnRenameList(list *emaillistchecker.List, err error) {
+	m.renameList.push(list, err)
+}
+
+// ReturnDownloadList queues content written by the next DownloadList call
+func (m *ListService) ReturnDownloadList(content []byte, err error) {
+	m.downloadList.push(content, err)
+}
+
+// ReturnDeleteList queues the error for the next DeleteList call
+func (m *ListService) ReturnDeleteList(err error) {
+	m.deleteList.push(struct{}{}, err)
+}
+
+// ReturnDeleteLists queues the error for the next DeleteLists call
+func (m *ListService) ReturnDeleteLists(err error) {
+	m.deleteLists.push(struct{}{}, err)
+}
+
+// GetLists implements emaillistchecker.ListService
+func (m *ListService) GetLists() ([]emaillistchecker.List, error) {
+	m.record("GetLists")
+	if v, err, ok := m.getLists.pop(); ok {
+		return v, err
+	}
+	if m.GetListsFunc != nil {
+		return m.GetListsFunc()
+	}
+	return nil, unexpected("GetLists")
+}
+
+// GetList implements emaillistchecker.ListService
+func (m *ListService) GetList(listID int) (*emaillistchecker.List, error) {
+	m.record("GetList", listID)
+	if v, err, ok := m.getList.pop(); ok {
+		return v, err
+	}
+	if m.GetListFunc != nil {
+		return m.GetListFunc(listID)
+	}
+	return nil, unexpected("GetList")
+}
+
+// RenameList implements emaillistchecker.ListService
+func (m *ListService) RenameList(listID int, name string) (*emaillistchecker.List, error) {
+	m.record("RenameList", listID, name)
+	if v, err, ok := m.renameList.pop(); ok {
+		return v, err
+	}
+	if m.RenameListFunc != nil {
+		return m.RenameListFunc(listID, name)
+	}
+	return nil, unexpected("RenameList")
+}
+
+// DownloadList implements emaillistchecker.ListService
+func (m *ListService) DownloadList(listID int, format, filter string, w io.Writer) error {
+	m.record("DownloadList", listID, format, filter)
+	if content, err, ok := m.downloadList.pop(); ok {
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(content)
+		return err
+	}
+	if m.DownloadListFunc != nil {
+		return m.DownloadListFunc(listID, format, filter, w)
+	}
+	return unexpected("DownloadList")
+}
+
+// DeleteList implements emaillistchecker.ListService
+func (m *ListService) DeleteList(listID int) error {
+	m.record("DeleteList", listID)
+	if _, err, ok := m.deleteList.pop(); ok {
+		return err
+	}
+	if m.DeleteListFunc != nil {
+		return m.DeleteListFunc(listID)
+	}
+	return unexpected("DeleteList")
+}
+
+// DeleteLists implements emaillistchecker.ListService
+func (m *ListService) DeleteLists(listIDs []int, force bool) error {
+	m.record("DeleteLists", listIDs, force)
+	if _, err, ok := m.deleteLists.pop(); ok {
+		return err
+	}
+	if m.DeleteListsFunc != nil {
+		return m.DeleteListsFunc(listIDs, force)
+	}
+	return unexpected("DeleteLists")
+}
+
+// Client is a mock implementing every service interface.
+// Calls are recorded separately by each embedded mock.
+type Client struct {
+	Verifier
+	BatchVerifier
+	Finder
+	AccountService
+	ListService
+}
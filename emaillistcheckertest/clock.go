@@ -0,0 +1,96 @@
+package emaillistcheckertest
+
+import (
+	"sync"
+	"time"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+)
+
+// Clock is a fake emaillistchecker.Clock whose time only moves with Advance.
+// Tickers fire, at most once per Advance like time.Ticker drops missed ticks,
+// when the clock passes their next tick.
+//
+//	clock := emaillistcheckertest.NewClock(time.Now())
+//	monitor := client.NewMonitor(emaillistchecker.MonitorOptions{Clock: clock, Interval: time.Hour})
+//	monitor.Start()
+//	clock.Advance(time.Hour) // triggers the next poll
+type Clock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+}
+
+// Compile-time check that *Clock implements emaillistchecker.Clock
+var _ emaillistchecker.Clock = (*Clock)(nil)
+
+// NewClock creates a fake clock set to start
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+// Now returns the fake time
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTicker creates a ticker driven by Advance
+func (c *Clock) NewTicker(d time.Duration) emaillistchecker.Ticker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTicker{
+		clock:  c,
+		period: d,
+		next:   c.now.Add(d),
+		ch:     make(chan time.Time, 1),
+	}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// Advance moves the clock forward by d and fires any tickers that are due
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	for _, t := range c.tickers {
+		if t.next.After(c.now) {
+			continue
+		}
+		for !t.next.After(c.now) {
+			t.next = t.next.Add(t.period)
+		}
+		select {
+		case t.ch <- c.now:
+		default:
+		}
+	}
+}
+
+// fakeTicker is a ticker created by Clock
+type fakeTicker struct {
+	clock  *Clock
+	period time.Duration
+	next   time.Time
+	ch     chan time.Time
+}
+
+func (t *fakeTicker) Chan() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	for i, other := range t.clock.tickers {
+		if other == t {
+			t.clock.tickers = append(t.clock.tickers[:i], t.clock.tickers[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package emaillistcheckertest
+
+import (
+	"strings"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+)
+
+// Magic local parts select the verification result. The match is on the part
+// before any "+" tag, so "undeliverable+42@example.com" is undeliverable too.
+// Every other address is deliverable.
+const (
+	Deliverable   = "deliverable"   // deliverable, VALID
+	Undeliverable = "undeliverable" // undeliverable, INVALID
+	Risky         = "risky"         // risky, ACCEPT_ALL
+	Unknown       = "unknown"       // unknown, TIMEOUT
+	Disposable    = "disposable"    // risky, DISPOSABLE with Disposable set
+	SpamTrap      = "spamtrap"      // undeliverable, SPAM_TRAP with SpamTrap set
+)
+
+// DisposableDomain is treated as a disposable provider for every local part
+const DisposableDomain = "mailinator.com"
+
+// roleLocalParts are reported with Role set
+var roleLocalParts = map[string]bool{
+	"admin": true, "info": true, "sales": true, "support": true, "contact": true, "noreply": true,
+}
+
+// freeDomains are reported with Free set
+var freeDomains = map[string]bool{
+	"gmail.com": true, "yahoo.com": true, "outlook.com": true, "hotmail.com": true, "aol.com": true,
+}
+
+// Result returns the verification result the server reports for email
+func Result(email string) *emaillistchecker.VerifyResponse {
+	local, domain := email, ""
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		local, domain = email[:at], strings.ToLower(email[at+1:])
+	}
+	tag := strings.ToLower(local)
+	if plus := strings.Index(tag, "+"); plus >= 0 {
+		tag = tag[:plus]
+	}
+
+	result := &emaillistchecker.VerifyResponse{
+		Email:        email,
+		Result:       "deliverable",
+		Reason:       "VALID",
+		Score:        0.95,
+		Domain:       domain,
+		SMTPProvider: "test",
+		MXRecords:    []string{"mx1." + domain, "mx2." + domain},
+		MXFound:      true,
+		Role:         roleLocalParts[tag],
+		Free:         freeDomains[domain],
+	}
+
+	switch {
+	case tag == Undeliverable:
+		result.Result, result.Reason, result.Score = "undeliverable", "INVALID", 0.05
+	case tag == Risky:
+		result.Result, result.Reason, result.Score = "risky", "ACCEPT_ALL", 0.5
+	case tag == Unknown:
+		result.Result, result.Reason, result.Score = "unknown", "TIMEOUT", 0.3
+	case tag == Disposable || domain == DisposableDomain:
+		result.Result, result.Reason, result.Score = "risky", "DISPOSABLE", 0.2
+		result.Disposable = true
+	case tag == SpamTrap:
+		result.Result, result.Reason, result.Score = "undeliverable", "SPAM_TRAP", 0
+		result.SpamTrap = true
+	}
+
+	return result
+}
@@ -0,0 +1,106 @@
+package emaillistcheckertest
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+)
+
+// Resolver is a fake emaillistchecker.Resolver answering from records set with
+// SetMX, SetHosts and Fail. Names without records do not exist.
+//
+//	resolver := emaillistcheckertest.NewResolver()
+//	resolver.SetMX("example.com", "mx1.example.com")
+//	client.SetResolver(resolver)
+type Resolver struct {
+	mu     sync.Mutex
+	mx     map[string][]*net.MX
+	hosts  map[string][]string
+	errors map[string]error
+}
+
+// Compile-time check that *Resolver implements emaillistchecker.Resolver
+var _ emaillistchecker.Resolver = (*Resolver)(nil)
+
+// NewResolver creates a fake resolver with no records
+func NewResolver() *Resolver {
+	return &Resolver{
+		mx:     make(map[string][]*net.MX),
+		hosts:  make(map[string][]string),
+		errors: make(map[string]error),
+	}
+}
+
+// SetMX sets the MX records of domain, in order of preference. Pass "." alone
+// for a null MX.
+func (r *Resolver) SetMX(domain string, hosts ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	records := make([]*net.MX, len(hosts))
+	for i, host := range hosts {
+		records[i] = &net.MX{Host: host, Pref: uint16(10 * (i + 1))}
+	}
+	r.mx[normalizeName(domain)] = records
+}
+
+// SetHosts sets the A/AAAA addresses of host
+func (r *Resolver) SetHosts(host string, addrs ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hosts[normalizeName(host)] = addrs
+}
+
+// Fail makes every lookup of name return a temporary DNS error
+func (r *Resolver) Fail(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors[normalizeName(name)] = &net.DNSError{Err: "server misbehaving", Name: name, IsTemporary: true}
+}
+
+// LookupMX implements emaillistchecker.Resolver
+func (r *Resolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := normalizeName(name)
+	if err := r.errors[key]; err != nil {
+		return nil, err
+	}
+	if records, ok := r.mx[key]; ok {
+		return records, nil
+	}
+	return nil, notFound(name)
+}
+
+// LookupHost implements emaillistchecker.Resolver
+func (r *Resolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := normalizeName(host)
+	if err := r.errors[key]; err != nil {
+		return nil, err
+	}
+	if addrs, ok := r.hosts[key]; ok {
+		return addrs, nil
+	}
+	return nil, notFound(host)
+}
+
+// normalizeName lowercases a name and converts an IDN to ASCII, so records
+// can be set under either form
+func normalizeName(name string) string {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	if ascii, err := emaillistchecker.DomainToASCII(name); err == nil {
+		return ascii
+	}
+	return name
+}
+
+func notFound(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
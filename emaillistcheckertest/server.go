@@ -0,0 +1,763 @@
+// Package emaillistcheckertest provides an in-memory fake of the EmailListChecker
+// API for tests.
+//
+// The fake answers every endpoint the client uses with deterministic results
+// chosen by magic addresses, simulates batch progress over time against an
+// adjustable clock, charges credits, and can be told to fail with any status:
+//
+//	srv := emaillistcheckertest.NewServer()
+//	defer srv.Close()
+//
+//	client := srv.Client()
+//	result, err := client.Verify("undeliverable@example.com", nil, true)
+package emaillistcheckertest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+)
+
+// Defaults for a new Server
+const (
+	// APIKey is the only key the fake accepts
+	APIKey = "test_api_key"
+	// DefaultCredits is the starting credit balance
+	DefaultCredits = 1000
+	// DefaultBatchDuration is how long a batch takes to reach 100%
+	DefaultBatchDuration = 10 * time.Second
+)
+
+// Fault describes an error the server returns instead of handling a request
+type Fault struct {
+	// Status is the HTTP status to return, e.g. 401, 402, 422, 429 or 503
+	Status int
+	// Message is returned in the "error" (or, for 422, "message") field
+	Message string
+	// RetryAfter sets the Retry-After header in seconds for 429 and 503 responses
+	RetryAfter int
+	// Times is how many requests fail; zero fails every matching request
+	Times int
+}
+
+// Request is a request received by the server
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Body   []byte
+}
+
+// Server is an in-memory EmailListChecker API
+type Server struct {
+	// URL is the base URL to pass to NewClientWithConfig
+	URL string
+
+	srv *httptest.Server
+
+	mu            sync.Mutex
+	credits       int
+	usedCredits   int
+	batchDuration time.Duration
+	clockOffset   time.Duration
+	batches       map[int]*batch
+	nextBatchID   int
+	faults        map[string]*Fault
+	requests      []Request
+	usage         []usageEvent
+}
+
+// usageEvent is a handled request as reported by /usage
+type usageEvent struct {
+	at      time.Time
+	failed  bool
+	credits int
+}
+
+// batch is a batch verification, also exposed as a list
+type batch struct {
+	id        int
+	name      string
+	emails    []string
+	started   bool
+	startedAt time.Time
+	createdAt time.Time
+}
+
+// NewServer starts a fake API server. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		credits:       DefaultCredits,
+		batchDuration: DefaultBatchDuration,
+		batches:       make(map[int]*batch),
+		nextBatchID:   1,
+		faults:        make(map[string]*Fault),
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns a client configured for the server
+func (s *Server) Client() *emaillistchecker.Client {
+	return emaillistchecker.NewClientWithConfig(APIKey, s.URL, 5*time.Second)
+}
+
+// SetCredits sets the credit balance
+func (s *Server) SetCredits(credits int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.credits = credits
+}
+
+// Credits returns the current credit balance
+func (s *Server) Credits() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.credits
+}
+
+// SetBatchDuration sets how long batches take to complete
+func (s *Server) SetBatchDuration(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batchDuration = d
+}
+
+// Advance moves the server clock forward, progressing running batches
+func (s *Server) Advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clockOffset += d
+}
+
+// Fail makes requests whose path starts with pathPrefix (e.g. "/verify" or "/credits")
+// return the fault. The most specific matching prefix wins.
+func (s *Server) Fail(pathPrefix string, fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := fault
+	s.faults[pathPrefix] = &f
+}
+
+// ClearFaults removes all injected faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = make(map[string]*Fault)
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// now returns the server clock
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.clockOffset)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Body:   body,
+	})
+
+	used := s.usedCredits
+	rw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	s.route(rw, r, body)
+
+	s.usage = append(s.usage, usageEvent{
+		at:      s.now(),
+		failed:  rw.status >= 400,
+		credits: s.usedCredits - used,
+	})
+}
+
+func (s *Server) route(w *statusRecorder, r *http.Request, body []byte) {
+	if r.Header.Get("Authorization") != "Bearer "+APIKey {
+		writeError(w, http.StatusUnauthorized, "error", "Invalid API key")
+		return
+	}
+
+	if s.injectFault(w, r.URL.Path) {
+		return
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+
+	switch {
+	case r.Method == "POST" && path == "/verify":
+		s.handleVerify(w, body)
+	case r.Method == "POST" && path == "/verify/batch":
+		s.handleBatch(w, body)
+	case r.Method == "POST" && path == "/verify/batch/upload":
+		s.handleUpload(w, r, body)
+	case r.Method == "GET" && len(parts) == 3 && parts[0] == "verify" && parts[1] == "batch":
+		s.withBatch(w, parts[2], s.handleBatchStatus)
+	case r.Method == "GET" && len(parts) == 4 && parts[0] == "verify" && parts[1] == "batch" && parts[3] == "results":
+		s.withBatch(w, parts[2], func(w *statusRecorder, b *batch) {
+			s.handleBatchResults(w, b, r.URL.Query().Get("filter"))
+		})
+	case r.Method == "POST" && path == "/finder/email":
+		s.handleFindEmail(w, body)
+	case r.Method == "POST" && path == "/finder/domain":
+		s.handleFindDomain(w, body)
+	case r.Method == "POST" && path == "/finder/company":
+		s.handleFindCompany(w, body)
+	case r.Method == "GET" && path == "/credits":
+		writeData(w, map[string]interface{}{
+			"balance":         s.credits,
+			"used_this_month": s.usedCredits,
+			"plan":            "test",
+		})
+	case r.Method == "GET" && path == "/usage":
+		s.handleUsage(w, r.URL.Query())
+	case r.Method == "GET" && path == "/lists":
+		s.handleLists(w)
+	case len(parts) == 2 && parts[0] == "lists":
+		s.withBatch(w, parts[1], func(w *statusRecorder, b *batch) {
+			s.handleList(w, r.Method, b, body)
+		})
+	case r.Method == "GET" && len(parts) == 3 && parts[0] == "lists" && parts[2] == "download":
+		s.withBatch(w, parts[1], func(w *statusRecorder, b *batch) {
+			s.handleDownload(w, b, r.URL.Query().Get("format"), r.URL.Query().Get("filter"))
+		})
+	default:
+		writeError(w, http.StatusNotFound, "error", "Not found")
+	}
+}
+
+// injectFault writes the most specific matching fault, if any
+func (s *Server) injectFault(w *statusRecorder, path string) bool {
+	var match string
+	for prefix := range s.faults {
+		if strings.HasPrefix(path, prefix) && len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+	if match == "" {
+		return false
+	}
+
+	fault := s.faults[match]
+	if fault.Times > 0 {
+		fault.Times--
+		if fault.Times == 0 {
+			delete(s.faults, match)
+		}
+	}
+
+	msg := fault.Message
+	if msg == "" {
+		msg = http.StatusText(fault.Status)
+	}
+	if (fault.Status == http.StatusTooManyRequests || fault.Status == http.StatusServiceUnavailable) && fault.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(fault.RetryAfter))
+	}
+
+	key := "error"
+	if fault.Status == http.StatusUnprocessableEntity {
+		key = "message"
+	}
+	writeError(w, fault.Status, key, msg)
+	return true
+}
+
+// charge spends credits, writing a 402 if the balance is too low
+func (s *Server) charge(w *statusRecorder, n int) bool {
+	if s.credits < n {
+		writeError(w, http.StatusPaymentRequired, "error", "Insufficient credits")
+		return false
+	}
+	s.credits -= n
+	s.usedCredits += n
+	return true
+}
+
+func (s *Server) handleVerify(w *statusRecorder, body []byte) {
+	var req emaillistchecker.VerifyRequest
+	if err := json.Unmarshal(body, &req); err != nil || !strings.Contains(req.Email, "@") {
+		writeError(w, http.StatusUnprocessableEntity, "message", "The email field must be a valid email address.")
+		return
+	}
+	if !s.charge(w, 1) {
+		return
+	}
+	writeData(w, Result(req.Email))
+}
+
+func (s *Server) handleBatch(w *statusRecorder, body []byte) {
+	var req emaillistchecker.BatchRequest
+	if err := json.Unmarshal(body, &req); err != nil || len(req.Emails) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "message", "The emails field is required.")
+		return
+	}
+	s.createBatch(w, req.Name, req.Emails, req.AutoStart)
+}
+
+func (s *Server) handleUpload(w *statusRecorder, r *http.Request, body []byte) {
+	r.Body = io.NopCloser(strings.NewReader(string(body)))
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "message", "The file field is required.")
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "message", "The file field is required.")
+		return
+	}
+	defer file.Close()
+
+	// Take every field that looks like an address from TXT or CSV content
+	var emails []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		for _, field := range strings.FieldsFunc(scanner.Text(), func(r rune) bool {
+			return r == ',' || r == ';' || r == '\t' || r == ' ' || r == '"'
+		}) {
+			if strings.Contains(field, "@") {
+				emails = append(emails, field)
+			}
+		}
+	}
+	if len(emails) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "message", "No valid emails found in file.")
+		return
+	}
+
+	autoStart, _ := strconv.ParseBool(r.FormValue("auto_start"))
+	s.createBatch(w, r.FormValue("name"), emails, autoStart)
+}
+
+func (s *Server) createBatch(w *statusRecorder, name string, emails []string, autoStart bool) {
+	if !s.charge(w, len(emails)) {
+		return
+	}
+
+	now := s.now()
+	b := &batch{
+		id:        s.nextBatchID,
+		name:      name,
+		emails:    emails,
+		started:   autoStart,
+		startedAt: now,
+		createdAt: now,
+	}
+	s.nextBatchID++
+	s.batches[b.id] = b
+
+	writeData(w, emaillistchecker.BatchResponse{
+		ID:          b.id,
+		Status:      s.batchStatus(b),
+		TotalEmails: len(b.emails),
+		CreatedAt:   b.createdAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// withBatch resolves a batch ID path segment
+func (s *Server) withBatch(w *statusRecorder, id string, fn func(*statusRecorder, *batch)) {
+	batchID, err := strconv.Atoi(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "error", "Not found")
+		return
+	}
+	b, ok := s.batches[batchID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "error", "Batch not found")
+		return
+	}
+	fn(w, b)
+}
+
+// progress returns the percentage of a batch processed at the current server time
+func (s *Server) progress(b *batch) int {
+	if !b.started {
+		return 0
+	}
+	if s.batchDuration <= 0 {
+		return 100
+	}
+	elapsed := s.now().Sub(b.startedAt)
+	if elapsed >= s.batchDuration {
+		return 100
+	}
+	return int(elapsed * 100 / s.batchDuration)
+}
+
+func (s *Server) batchStatus(b *batch) string {
+	switch p := s.progress(b); {
+	case !b.started:
+		return "pending"
+	case p >= 100:
+		return "completed"
+	default:
+		return "processing"
+	}
+}
+
+func (s *Server) handleBatchStatus(w *statusRecorder, b *batch) {
+	status := s.batchStatusResponse(b)
+	writeData(w, status)
+}
+
+func (s *Server) batchStatusResponse(b *batch) emaillistchecker.BatchStatusResponse {
+	progress := s.progress(b)
+	processed := len(b.emails) * progress / 100
+
+	status := emaillistchecker.BatchStatusResponse{
+		ID:              b.id,
+		Status:          s.batchStatus(b),
+		Progress:        progress,
+		TotalEmails:     len(b.emails),
+		ProcessedEmails: processed,
+	}
+	for _, email := range b.emails[:processed] {
+		switch Result(email).Result {
+		case "deliverable":
+			status.ValidEmails++
+		case "undeliverable":
+			status.InvalidEmails++
+		default:
+			status.UnknownEmails++
+		}
+	}
+	return status
+}
+
+func (s *Server) handleBatchResults(w *statusRecorder, b *batch, filter string) {
+	if s.progress(b) < 100 {
+		writeError(w, http.StatusUnprocessableEntity, "message", "Batch is still processing.")
+		return
+	}
+	writeData(w, filterResults(b.emails, filter))
+}
+
+// filterResults returns the results for emails matching a results filter
+func filterResults(emails []string, filter string) []*emaillistchecker.VerifyResponse {
+	results := make([]*emaillistchecker.VerifyResponse, 0, len(emails))
+	for _, email := range emails {
+		result := Result(email)
+		switch filter {
+		case "valid":
+			if result.Result != "deliverable" {
+				continue
+			}
+		case "invalid":
+			if result.Result != "undeliverable" {
+				continue
+			}
+		case "unknown":
+			if result.Result == "deliverable" || result.Result == "undeliverable" {
+				continue
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func (s *Server) handleFindEmail(w *statusRecorder, body []byte) {
+	var req emaillistchecker.FindEmailRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.FirstName == "" || req.LastName == "" || req.Domain == "" {
+		writeError(w, http.StatusUnprocessableEntity, "message", "first_name, last_name and domain are required.")
+		return
+	}
+	if !s.charge(w, 1) {
+		return
+	}
+
+	first, last := strings.ToLower(req.FirstName), strings.ToLower(req.LastName)
+	writeData(w, map[string]interface{}{
+		"email":      first + "." + last + "@" + req.Domain,
+		"confidence": 90,
+		"pattern":    "{first}.{last}",
+		"verified":   true,
+		"domain":     req.Domain,
+		"first_name": req.FirstName,
+		"last_name":  req.LastName,
+		"alternatives": []map[string]interface{}{
+			{"email": first[:1] + last + "@" + req.Domain, "confidence": 60, "pattern": "{f}{last}"},
+			{"email": first + "@" + req.Domain, "confidence": 30, "pattern": "{first}"},
+		},
+	})
+}
+
+func (s *Server) handleFindDomain(w *statusRecorder, body []byte) {
+	var req struct {
+		Domain string `json:"domain"`
+		Limit  int    `json:"limit"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil || req.Domain == "" {
+		writeError(w, http.StatusUnprocessableEntity, "message", "The domain field is required.")
+		return
+	}
+	if !s.charge(w, 1) {
+		return
+	}
+
+	emails := []interface{}{
+		map[string]interface{}{"email": "jane.doe@" + req.Domain, "first_name": "Jane", "last_name": "Doe"},
+		map[string]interface{}{"email": "john.smith@" + req.Domain, "first_name": "John", "last_name": "Smith"},
+		map[string]interface{}{"email": "asmith@" + req.Domain, "first_name": "Alice", "last_name": "Smith"},
+	}
+	if req.Limit > 0 && req.Limit < len(emails) {
+		emails = emails[:req.Limit]
+	}
+
+	writeData(w, map[string]interface{}{
+		"domain":      req.Domain,
+		"total_found": len(emails),
+		"emails":      emails,
+		"patterns": []map[string]interface{}{
+			{"pattern": "{first}.{last}", "frequency": 67},
+			{"pattern": "{f}{last}", "frequency": 33},
+		},
+	})
+}
+
+func (s *Server) handleFindCompany(w *statusRecorder, body []byte) {
+	var req struct {
+		Company string `json:"company"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil || req.Company == "" {
+		writeError(w, http.StatusUnprocessableEntity, "message", "The company field is required.")
+		return
+	}
+	if !s.charge(w, 1) {
+		return
+	}
+
+	slug := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, strings.ToLower(strings.Fields(req.Company + " x")[0]))
+
+	writeData(w, map[string]interface{}{
+		"company":     req.Company,
+		"total_found": 2,
+		"possible_domains": []map[string]interface{}{
+			{"domain": slug + ".com", "confidence": 80},
+			{"domain": slug + ".io", "confidence": 40},
+		},
+	})
+}
+
+// handleUsage reports usage between the from and to parameters, bucketed by
+// granularity when given
+func (s *Server) handleUsage(w *statusRecorder, query url.Values) {
+	var from, to time.Time
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		if v := query.Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeError(w, http.StatusUnprocessableEntity, "message", "The "+p.name+" field must be an RFC 3339 timestamp.")
+				return
+			}
+			*p.dst = t
+		}
+	}
+
+	granularity := emaillistchecker.Granularity(query.Get("granularity"))
+	switch granularity {
+	case "", emaillistchecker.GranularityHour, emaillistchecker.GranularityDay,
+		emaillistchecker.GranularityWeek, emaillistchecker.GranularityMonth:
+	default:
+		writeError(w, http.StatusUnprocessableEntity, "message", "The granularity field must be hour, day, week or month.")
+		return
+	}
+
+	report := emaillistchecker.UsageReport{Granularity: granularity}
+	if !from.IsZero() {
+		report.From = from.UTC().Format(time.RFC3339)
+	}
+	if !to.IsZero() {
+		report.To = to.UTC().Format(time.RFC3339)
+	}
+
+	var points map[time.Time]*emaillistchecker.UsagePoint
+	var periods []time.Time
+	if granularity != "" {
+		points = make(map[time.Time]*emaillistchecker.UsagePoint)
+	}
+
+	for _, event := range s.usage {
+		if !from.IsZero() && event.at.Before(from) || !to.IsZero() && !event.at.Before(to) {
+			continue
+		}
+		report.TotalRequests++
+		if event.failed {
+			report.FailedRequests++
+		} else {
+			report.SuccessfulRequests++
+		}
+		report.CreditsUsed += event.credits
+
+		if points == nil {
+			continue
+		}
+		period := bucketStart(event.at, granularity)
+		point := points[period]
+		if point == nil {
+			point = &emaillistchecker.UsagePoint{Period: period.Format(time.RFC3339)}
+			points[period] = point
+			periods = append(periods, period)
+		}
+		point.Requests++
+		if event.failed {
+			point.Failed++
+		} else {
+			point.Successful++
+		}
+		point.Credits += event.credits
+	}
+
+	if points != nil {
+		report.Series = make([]emaillistchecker.UsagePoint, 0, len(periods))
+		for _, period := range periods {
+			report.Series = append(report.Series, *points[period])
+		}
+	}
+	writeData(w, report)
+}
+
+// bucketStart returns the start of the UTC bucket holding t
+func bucketStart(t time.Time, granularity emaillistchecker.Granularity) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch granularity {
+	case emaillistchecker.GranularityHour:
+		return t.Truncate(time.Hour)
+	case emaillistchecker.GranularityWeek:
+		// Weeks start on Monday
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case emaillistchecker.GranularityMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func (s *Server) listResponse(b *batch) emaillistchecker.List {
+	status := s.batchStatusResponse(b)
+	return emaillistchecker.List{
+		ID:            b.id,
+		Name:          b.name,
+		Status:        status.Status,
+		Progress:      status.Progress,
+		TotalEmails:   status.TotalEmails,
+		ValidEmails:   status.ValidEmails,
+		InvalidEmails: status.InvalidEmails,
+		UnknownEmails: status.UnknownEmails,
+		CreatedAt:     b.createdAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     s.now().UTC().Format(time.RFC3339),
+	}
+}
+
+func (s *Server) handleLists(w *statusRecorder) {
+	lists := make([]emaillistchecker.List, 0, len(s.batches))
+	for id := 1; id < s.nextBatchID; id++ {
+		if b, ok := s.batches[id]; ok {
+			lists = append(lists, s.listResponse(b))
+		}
+	}
+	writeData(w, lists)
+}
+
+func (s *Server) handleList(w *statusRecorder, method string, b *batch, body []byte) {
+	switch method {
+	case "GET":
+		writeData(w, s.listResponse(b))
+	case "PUT", "PATCH":
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil || req.Name == "" {
+			writeError(w, http.StatusUnprocessableEntity, "message", "The name field is required.")
+			return
+		}
+		b.name = req.Name
+		writeData(w, s.listResponse(b))
+	case "DELETE":
+		delete(s.batches, b.id)
+		writeData(w, map[string]interface{}{"deleted": true})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "error", "Method not allowed")
+	}
+}
+
+func (s *Server) handleDownload(w *statusRecorder, b *batch, format, filter string) {
+	results := filterResults(b.emails, filter)
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(results)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	fmt.Fprintln(w, "email,result,reason,score")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s,%s,%s,%.2f\n", r.Email, r.Result, r.Reason, r.Score)
+	}
+}
+
+// statusRecorder remembers the status written to a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    data,
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, key, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": false,
+		key:       msg,
+	})
+}
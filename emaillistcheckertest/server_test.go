@@ -0,0 +1,199 @@
+package emaillistcheckertest_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+func TestMagicAddresses(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	tests := []struct {
+		email  string
+		result string
+		reason string
+	}{
+		{"deliverable@example.com", "deliverable", "VALID"},
+		{"jane@example.com", "deliverable", "VALID"},
+		{"undeliverable@example.com", "undeliverable", "INVALID"},
+		{"Undeliverable+42@example.com", "undeliverable", "INVALID"},
+		{"risky@example.com", "risky", "ACCEPT_ALL"},
+		{"unknown@example.com", "unknown", "TIMEOUT"},
+		{"disposable@example.com", "risky", "DISPOSABLE"},
+		{"jane@" + emaillistcheckertest.DisposableDomain, "risky", "DISPOSABLE"},
+		{"spamtrap@example.com", "undeliverable", "SPAM_TRAP"},
+	}
+	for _, tt := range tests {
+		result, err := client.Verify(tt.email, nil, true)
+		if err != nil {
+			t.Fatalf("Verify(%q): %v", tt.email, err)
+		}
+		if result.Email != tt.email || result.Result != tt.result || result.Reason != tt.reason {
+			t.Errorf("Verify(%q) = %s %s %s, want %s %s", tt.email, result.Email, result.Result, result.Reason, tt.result, tt.reason)
+		}
+	}
+
+	if got, want := srv.Credits(), emaillistcheckertest.DefaultCredits-len(tests); got != want {
+		t.Errorf("credits = %d, want %d", got, want)
+	}
+}
+
+func TestResultFlags(t *testing.T) {
+	if r := emaillistcheckertest.Result("info@gmail.com"); !r.Role || !r.Free || r.Domain != "gmail.com" {
+		t.Errorf("Result(info@gmail.com) = %+v, want Role and Free set", r)
+	}
+	if r := emaillistcheckertest.Result("jane@example.com"); r.Role || r.Free || !r.MXFound {
+		t.Errorf("Result(jane@example.com) = %+v, want MX found and no flags", r)
+	}
+}
+
+func TestBatchProgress(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	srv.SetBatchDuration(10 * time.Second)
+	client := srv.Client()
+
+	emails := []string{"a@example.com", "undeliverable@example.com", "unknown@example.com", "b@example.com"}
+	batch, err := client.VerifyBatch(emails, "test", "", true)
+	if err != nil {
+		t.Fatalf("VerifyBatch: %v", err)
+	}
+	if batch.TotalEmails != len(emails) {
+		t.Errorf("TotalEmails = %d, want %d", batch.TotalEmails, len(emails))
+	}
+	if got, want := srv.Credits(), emaillistcheckertest.DefaultCredits-len(emails); got != want {
+		t.Errorf("credits = %d, want %d", got, want)
+	}
+
+	for _, step := range []struct {
+		advance   time.Duration
+		status    string
+		progress  int
+		processed int
+	}{
+		{0, "processing", 0, 0},
+		{5 * time.Second, "processing", 50, 2},
+		{5 * time.Second, "completed", 100, 4},
+	} {
+		srv.Advance(step.advance)
+		status, err := client.GetBatchStatus(batch.ID)
+		if err != nil {
+			t.Fatalf("GetBatchStatus: %v", err)
+		}
+		if status.Status != step.status || status.Progress != step.progress || status.ProcessedEmails != step.processed {
+			t.Errorf("status = %s %d%% %d processed, want %s %d%% %d processed",
+				status.Status, status.Progress, status.ProcessedEmails, step.status, step.progress, step.processed)
+		}
+	}
+
+	for filter, want := range map[string]int{"all": 4, "valid": 2, "invalid": 1, "unknown": 1} {
+		data, err := client.GetBatchResults(batch.ID, "json", filter)
+		if err != nil {
+			t.Fatalf("GetBatchResults(%s): %v", filter, err)
+		}
+		results, ok := data.([]interface{})
+		if !ok {
+			t.Fatalf("GetBatchResults(%s) = %v, want an array", filter, data)
+		}
+		if len(results) != want {
+			t.Errorf("GetBatchResults(%s) has %d results, want %d", filter, len(results), want)
+		}
+	}
+}
+
+func TestPendingBatch(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	batch, err := client.VerifyBatch([]string{"a@example.com"}, "test", "", false)
+	if err != nil {
+		t.Fatalf("VerifyBatch: %v", err)
+	}
+	srv.Advance(time.Hour)
+	status, err := client.GetBatchStatus(batch.ID)
+	if err != nil {
+		t.Fatalf("GetBatchStatus: %v", err)
+	}
+	if status.Status != "pending" || status.Progress != 0 {
+		t.Errorf("status = %s %d%%, want pending 0%%", status.Status, status.Progress)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	srv.Fail("/verify", emaillistcheckertest.Fault{Status: 429, RetryAfter: 7, Times: 1})
+	_, err := client.Verify("jane@example.com", nil, true)
+	var rateLimit *emaillistchecker.RateLimitError
+	if !errors.As(err, &rateLimit) || rateLimit.RetryAfter != 7 {
+		t.Errorf("Verify with a 429 fault: err = %v, want *RateLimitError with RetryAfter 7", err)
+	}
+	// The fault was used up
+	if _, err := client.Verify("jane@example.com", nil, true); err != nil {
+		t.Errorf("Verify after the fault: %v", err)
+	}
+
+	srv.Fail("/verify", emaillistcheckertest.Fault{Status: 401})
+	_, err = client.Verify("jane@example.com", nil, true)
+	var auth *emaillistchecker.AuthenticationError
+	if !errors.As(err, &auth) {
+		t.Errorf("Verify with a 401 fault: err = %v, want *AuthenticationError", err)
+	}
+	// Faults only match their path
+	if _, err := client.GetCredits(); err != nil {
+		t.Errorf("GetCredits with a /verify fault: %v", err)
+	}
+
+	srv.ClearFaults()
+	srv.SetCredits(0)
+	_, err = client.Verify("jane@example.com", nil, true)
+	var credits *emaillistchecker.InsufficientCreditsError
+	if !errors.As(err, &credits) {
+		t.Errorf("Verify without credits: err = %v, want *InsufficientCreditsError", err)
+	}
+}
+
+func TestInvalidAPIKey(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := emaillistchecker.NewClientWithConfig("wrong_key", srv.URL, 5*time.Second)
+
+	_, err := client.GetCredits()
+	var auth *emaillistchecker.AuthenticationError
+	if !errors.As(err, &auth) {
+		t.Errorf("GetCredits with a wrong key: err = %v, want *AuthenticationError", err)
+	}
+}
+
+func TestRequests(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	if _, err := client.Verify("jane@example.com", nil, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetCredits(); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := srv.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	if r := requests[0]; r.Method != "POST" || r.Path != "/verify" {
+		t.Errorf("requests[0] = %s %s, want POST /verify", r.Method, r.Path)
+	}
+	if r := requests[1]; r.Method != "GET" || r.Path != "/credits" {
+		t.Errorf("requests[1] = %s %s, want GET /credits", r.Method, r.Path)
+	}
+}
@@ -1,6 +1,10 @@
 package emaillistchecker
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
 
 // Error represents a base error from the EmailListChecker API
 type Error struct {
@@ -13,15 +17,19 @@ func (e *Error) Error() string {
 	return e.Message
 }
 
+// baseError is embedded by the typed errors below. Embedding *Error directly
+// would add a field named Error that hides the Error method.
+type baseError = Error
+
 // AuthenticationError is returned when API authentication fails
 type AuthenticationError struct {
-	*Error
+	*baseError
 }
 
 // NewAuthenticationError creates a new authentication error
 func NewAuthenticationError(message string, statusCode int, responseData map[string]interface{}) *AuthenticationError {
 	return &AuthenticationError{
-		Error: &Error{
+		baseError: &Error{
 			Message:      message,
 			StatusCode:   statusCode,
 			ResponseData: responseData,
@@ -31,13 +39,13 @@ func NewAuthenticationError(message string, statusCode int, responseData map[str
 
 // InsufficientCreditsError is returned when account has insufficient credits
 type InsufficientCreditsError struct {
-	*Error
+	*baseError
 }
 
 // NewInsufficientCreditsError creates a new insufficient credits error
 func NewInsufficientCreditsError(message string, statusCode int, responseData map[string]interface{}) *InsufficientCreditsError {
 	return &InsufficientCreditsError{
-		Error: &Error{
+		baseError: &Error{
 			Message:      message,
 			StatusCode:   statusCode,
 			ResponseData: responseData,
@@ -47,14 +55,14 @@ func NewInsufficientCreditsError(message string, statusCode int, responseData ma
 
 // RateLimitError is returned when API rate limit is exceeded
 type RateLimitError struct {
-	*Error
+	*baseError
 	RetryAfter int
 }
 
 // NewRateLimitError creates a new rate limit error
 func NewRateLimitError(retryAfter int, statusCode int, responseData map[string]interface{}) *RateLimitError {
 	return &RateLimitError{
-		Error: &Error{
+		baseError: &Error{
 			Message:      fmt.Sprintf("Rate limit exceeded. Retry after %d seconds", retryAfter),
 			StatusCode:   statusCode,
 			ResponseData: responseData,
@@ -65,13 +73,13 @@ func NewRateLimitError(retryAfter int, statusCode int, responseData map[string]i
 
 // ValidationError is returned when request validation fails
 type ValidationError struct {
-	*Error
+	*baseError
 }
 
 // NewValidationError creates a new validation error
 func NewValidationError(message string, statusCode int, responseData map[string]interface{}) *ValidationError {
 	return &ValidationError{
-		Error: &Error{
+		baseError: &Error{
 			Message:      message,
 			StatusCode:   statusCode,
 			ResponseData: responseData,
@@ -81,16 +89,44 @@ func NewValidationError(message string, statusCode int, responseData map[string]
 
 // APIError is returned for general API errors
 type APIError struct {
-	*Error
+	*baseError
 }
 
 // NewAPIError creates a new API error
 func NewAPIError(message string, statusCode int, responseData map[string]interface{}) *APIError {
 	return &APIError{
-		Error: &Error{
+		baseError: &Error{
 			Message:      message,
 			StatusCode:   statusCode,
 			ResponseData: responseData,
 		},
 	}
 }
+
+// ListInProgressError is returned when deleting a list that has not finished processing
+type ListInProgressError struct {
+	ListID int
+	Status string
+}
+
+func (e *ListInProgressError) Error() string {
+	return fmt.Sprintf("list %d is still %s", e.ListID, e.Status)
+}
+
+// ListErrors is returned by bulk list operations and maps each failed list ID to its error
+type ListErrors map[int]error
+
+func (e ListErrors) Error() string {
+	ids := make([]int, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprintf("list %d: %v", id, e[id]))
+	}
+
+	return fmt.Sprintf("%d list operations failed: %s", len(e), strings.Join(parts, "; "))
+}
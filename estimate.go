@@ -0,0 +1,423 @@
+package emaillistchecker
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// BatchEstimate is the expected cost of a batch, worked out locally before upload
+type BatchEstimate struct {
+	// Total is the number of addresses read from the input
+	Total int `json:"total"`
+	// Addresses are the unique, syntactically valid addresses that would be verified
+	Addresses []string `json:"addresses"`
+	// Duplicates are repeated addresses, compared case-insensitively; each is listed once
+	Duplicates []string `json:"duplicates"`
+	// Invalid are values that fail the local syntax check
+	Invalid []string `json:"invalid"`
+	// Credits is the expected cost, one credit per address in Addresses
+	Credits int `json:"credits"`
+	// Balance is the current balance from GetCredits
+	Balance int `json:"balance"`
+	// Sufficient reports whether Balance covers Credits; Shortfall is the difference if not
+	Sufficient bool `json:"sufficient"`
+	Shortfall  int  `json:"shortfall"`
+}
+
+// EstimateBatch works out what verifying emails as a batch would cost and
+// whether the balance covers it. Nothing is submitted.
+func (c *Client) EstimateBatch(emails []string) (*BatchEstimate, error) {
+	return c.estimate(emails, c.dedupeKey)
+}
+
+// EstimateFile works out what uploading a TXT, CSV or XLSX file with
+// VerifyBatchFile would cost. CSV files are read from the column headed
+// "email" (or similar), or the column where most values contain "@".
+func (c *Client) EstimateFile(path string) (*BatchEstimate, error) {
+	emails, err := readFileAddresses(path)
+	if err != nil {
+		return nil, err
+	}
+	// Uploads are not canonicalized, so only exact duplicates are free
+	return c.estimate(emails, cacheKey)
+}
+
+// estimate dedupes addresses on key and checks the balance
+func (c *Client) estimate(emails []string, key func(string) string) (*BatchEstimate, error) {
+	estimate := estimateAddresses(emails, key)
+	if err := c.checkBalance(estimate); err != nil {
+		return nil, err
+	}
+	return estimate, nil
+}
+
+// checkBalance fills in the balance fields of an estimate
+func (c *Client) checkBalance(estimate *BatchEstimate) error {
+	data, err := c.GetCredits()
+	if err != nil {
+		return err
+	}
+	balance, ok := creditBalance(data)
+	if !ok {
+		return errors.New("credits response has no balance")
+	}
+
+	estimate.Balance = balance
+	estimate.Sufficient = estimate.Credits <= balance
+	if !estimate.Sufficient {
+		estimate.Shortfall = estimate.Credits - balance
+	}
+	return nil
+}
+
+// estimateAddresses dedupes addresses on key and syntax-checks them
+func estimateAddresses(emails []string, key func(string) string) *BatchEstimate {
+	estimate := &BatchEstimate{}
+	seen := make(map[string]int)
+
+	for _, email := range emails {
+		email = strings.TrimSpace(email)
+		if email == "" {
+			continue
+		}
+		estimate.Total++
+
+		k := key(email)
+		seen[k]++
+		switch {
+		case seen[k] == 2:
+			estimate.Duplicates = append(estimate.Duplicates, email)
+		case seen[k] > 2:
+		case !validSyntax(email):
+			estimate.Invalid = append(estimate.Invalid, email)
+		default:
+			estimate.Addresses = append(estimate.Addresses, email)
+		}
+	}
+
+	estimate.Credits = len(estimate.Addresses)
+	return estimate
+}
+
+// validSyntax is a conservative local check that an address could be deliverable
+func validSyntax(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 1 || at > 64 || len(email) > 254 {
+		return false
+	}
+	local, domain := email[:at], email[at+1:]
+
+	switch {
+	case strings.HasPrefix(local, `"`):
+		if checkQuotedLocalPart(local) != "" {
+			return false
+		}
+	case strings.HasPrefix(local, ".") || strings.HasSuffix(local, ".") || strings.Contains(local, ".."):
+		return false
+	default:
+		for _, r := range local {
+			if r <= ' ' || strings.ContainsRune(`"(),:;<>@[\]`, r) {
+				return false
+			}
+		}
+	}
+
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 127) {
+				return false
+			}
+		}
+	}
+	tld := labels[len(labels)-1]
+	return len(tld) >= 2 && strings.Trim(tld, "0123456789") != ""
+}
+
+// emailHeaders are CSV header names recognised as the email column
+var emailHeaders = []string{"email", "e-mail", "email_address", "email address", "emailaddress", "mail"}
+
+// readFileAddresses reads the addresses of a batch upload file
+func readFileAddresses(path string) ([]string, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".txt":
+		return readTXTAddresses(path)
+	case ".csv":
+		return readCSVAddresses(path)
+	case ".xlsx":
+		return readXLSXAddresses(path)
+	default:
+		return nil, fmt.Errorf("unsupported file type %q (want .txt, .csv or .xlsx)", ext)
+	}
+}
+
+func readTXTAddresses(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	var emails []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			emails = append(emails, line)
+		}
+	}
+	return emails, scanner.Err()
+}
+
+func readCSVAddresses(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	col, hasHeader := csvEmailColumn(records)
+	if hasHeader {
+		records = records[1:]
+	}
+
+	var emails []string
+	for _, record := range records {
+		if col < len(record) {
+			emails = append(emails, record[col])
+		}
+	}
+	return emails, nil
+}
+
+// csvEmailColumn finds the email column by header name, or else the column
+// where most values contain "@". hasHeader reports whether the first record is
+// a header rather than data.
+func csvEmailColumn(records [][]string) (col int, hasHeader bool) {
+	for _, name := range emailHeaders {
+		for i, h := range records[0] {
+			if strings.EqualFold(strings.TrimSpace(h), name) {
+				return i, true
+			}
+		}
+	}
+
+	counts := make(map[int]int)
+	for _, record := range records {
+		for i, value := range record {
+			if strings.Contains(value, "@") {
+				counts[i]++
+			}
+		}
+	}
+	for i, n := range counts {
+		if n > counts[col] || (n == counts[col] && i < col) {
+			col = i
+		}
+	}
+
+	first := records[0]
+	return col, col >= len(first) || !strings.Contains(first[col], "@")
+}
+
+// readXLSXAddresses reads the email column of each worksheet that has one,
+// found as in a CSV. Cells are resolved through the shared string table, which
+// stores each distinct text once, so every occurrence of an address counts.
+func readXLSXAddresses(path string) ([]string, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open workbook: %w", err)
+	}
+	defer archive.Close()
+
+	var shared []string
+	for _, f := range archive.File {
+		if f.Name == "xl/sharedStrings.xml" {
+			if shared, err = readXLSXSharedStrings(f); err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+			}
+		}
+	}
+
+	var emails []string
+	for _, f := range archive.File {
+		if !strings.HasPrefix(f.Name, "xl/worksheets/") || !strings.HasSuffix(f.Name, ".xml") {
+			continue
+		}
+		records, err := readXLSXSheet(f, shared)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		if !hasAddress(records) {
+			continue
+		}
+
+		col, hasHeader := csvEmailColumn(records)
+		if hasHeader {
+			records = records[1:]
+		}
+		for _, record := range records {
+			if col < len(record) && strings.TrimSpace(record[col]) != "" {
+				emails = append(emails, record[col])
+			}
+		}
+	}
+	return emails, nil
+}
+
+// xlsxText is a shared or inline string, either plain or in rich text runs
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	s := t.Text
+	for _, run := range t.Runs {
+		s += run.Text
+	}
+	return s
+}
+
+// xlsxRow is a worksheet row; cells are referenced like "B2"
+type xlsxRow struct {
+	Cells []struct {
+		Ref    string   `xml:"r,attr"`
+		Type   string   `xml:"t,attr"`
+		Value  string   `xml:"v"`
+		Inline xlsxText `xml:"is"`
+	} `xml:"c"`
+}
+
+// readXLSXSharedStrings reads the shared string table in index order
+func readXLSXSharedStrings(f *zip.File) ([]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var strs []string
+	err = decodeXMLElements(rc, "si", func(decoder *xml.Decoder, start xml.StartElement) error {
+		var item xlsxText
+		if err := decoder.DecodeElement(&item, &start); err != nil {
+			return err
+		}
+		strs = append(strs, item.String())
+		return nil
+	})
+	return strs, err
+}
+
+// readXLSXSheet reads the cell values of a worksheet as records
+func readXLSXSheet(f *zip.File, shared []string) ([][]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var records [][]string
+	err = decodeXMLElements(rc, "row", func(decoder *xml.Decoder, start xml.StartElement) error {
+		var row xlsxRow
+		if err := decoder.DecodeElement(&row, &start); err != nil {
+			return err
+		}
+		var record []string
+		for _, cell := range row.Cells {
+			col := len(record)
+			if ref, ok := xlsxColumn(cell.Ref); ok && ref >= col {
+				col = ref
+			}
+			for len(record) <= col {
+				record = append(record, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(strings.TrimSpace(cell.Value))
+				if err != nil || index < 0 || index >= len(shared) {
+					return fmt.Errorf("cell %s: bad shared string index %q", cell.Ref, cell.Value)
+				}
+				record[col] = shared[index]
+			case "inlineStr":
+				record[col] = cell.Inline.String()
+			default:
+				record[col] = cell.Value
+			}
+		}
+		records = append(records, record)
+		return nil
+	})
+	return records, err
+}
+
+// xlsxColumn returns the zero-based column of a cell reference such as "AB12"
+func xlsxColumn(ref string) (int, bool) {
+	col, letters := 0, 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		letters++
+	}
+	return col - 1, letters > 0
+}
+
+// decodeXMLElements calls fn for every element with the given local name
+func decodeXMLElements(r io.Reader, name string, fn func(*xml.Decoder, xml.StartElement) error) error {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == name {
+			if err := fn(decoder, start); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// hasAddress reports whether any value in records contains "@"
+func hasAddress(records [][]string) bool {
+	for _, record := range records {
+		for _, value := range record {
+			if strings.Contains(value, "@") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package emaillistchecker
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEstimateAddresses(t *testing.T) {
+	emails := []string{
+		"jane@example.com",
+		" Jane@Example.com ",
+		"",
+		"john@example.com",
+		"jane@example.com",
+		"not an address",
+		"john.doe+promo@gmail.com",
+		"johndoe@googlemail.com",
+	}
+
+	estimate := estimateAddresses(emails, cacheKey)
+	want := &BatchEstimate{
+		Total:      7,
+		Addresses:  []string{"jane@example.com", "john@example.com", "john.doe+promo@gmail.com", "johndoe@googlemail.com"},
+		Duplicates: []string{"Jane@Example.com"},
+		Invalid:    []string{"not an address"},
+		Credits:    4,
+	}
+	if !reflect.DeepEqual(estimate, want) {
+		t.Errorf("estimateAddresses = %+v, want %+v", estimate, want)
+	}
+
+	// Keys from a canonicalizer merge provider aliases
+	estimate = estimateAddresses(emails, Canonicalize)
+	if estimate.Credits != 3 || len(estimate.Duplicates) != 2 {
+		t.Errorf("estimateAddresses with Canonicalize = %+v, want 3 credits and 2 duplicates", estimate)
+	}
+}
+
+func TestValidSyntax(t *testing.T) {
+	tests := []struct {
+		email string
+		want  bool
+	}{
+		{"jane@example.com", true},
+		{"jane.doe+tag@sub.example.co.uk", true},
+		{"jane@example", false},
+		{"jane@example.123", false},
+		{"@example.com", false},
+		{"jane.@example.com", false},
+		{"ja..ne@example.com", false},
+		{"ja ne@example.com", false},
+		{"jörg@bücher.de", true},
+		{`"ja ne"@example.com`, true},
+		{`"a\"b"@example.com`, true},
+		{`"ja"ne"@example.com`, false},
+		{"jane@-example.com", false},
+		{"jane@exa_mple.com", false},
+	}
+	for _, tt := range tests {
+		if got := validSyntax(tt.email); got != tt.want {
+			t.Errorf("validSyntax(%q) = %v, want %v", tt.email, got, tt.want)
+		}
+	}
+}
+
+func TestReadXLSXAddresses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "list.xlsx")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	for name, content := range map[string]string{
+		"xl/sharedStrings.xml": `<sst><si><t>Name</t></si><si><t>Email</t></si>` +
+			`<si><t>jane@example.com</t></si><si><r><t>john@</t></r><r><t>example.com</t></r></si></sst>`,
+		// The shared string for jane is used twice and must be counted twice
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>` +
+			`<row><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>` +
+			`<row><c r="A2" t="inlineStr"><is><t>Jane</t></is></c><c r="B2" t="s"><v>2</v></c></row>` +
+			`<row><c r="B3" t="s"><v>3</v></c></row>` +
+			`<row><c r="A4" t="inlineStr"><is><t>Jane again</t></is></c><c r="B4" t="s"><v>2</v></c></row>` +
+			`<row><c r="A5" t="inlineStr"><is><t>Inline</t></is></c><c r="B5" t="inlineStr"><is><t>ann@example.com</t></is></c></row>` +
+			`</sheetData></worksheet>`,
+		// Sheets without addresses are skipped
+		"xl/worksheets/sheet2.xml": `<worksheet><sheetData><row><c r="A1"><v>42</v></c></row></sheetData></worksheet>`,
+	} {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	emails, err := readXLSXAddresses(path)
+	if err != nil {
+		t.Fatalf("readXLSXAddresses: %v", err)
+	}
+	want := []string{"jane@example.com", "john@example.com", "jane@example.com", "ann@example.com"}
+	if !reflect.DeepEqual(emails, want) {
+		t.Errorf("readXLSXAddresses = %q, want %q", emails, want)
+	}
+}
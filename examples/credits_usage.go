@@ -1,8 +1,12 @@
+//go:build ignore
+
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
 )
@@ -25,22 +29,29 @@ func main() {
 	fmt.Printf("Used this month: %v\n", credits["used_this_month"])
 	fmt.Printf("Current plan: %v\n\n", credits["plan"])
 
-	// Get usage statistics
-	fmt.Println("=== Usage Statistics ===")
-	usage, err := client.GetUsage()
+	// Get usage statistics for the last 7 days, day by day
+	fmt.Println("=== Usage Statistics (last 7 days) ===")
+	usage, err := client.GetUsage(context.Background(), emaillistchecker.UsageQuery{
+		From:        time.Now().AddDate(0, 0, -7),
+		Granularity: emaillistchecker.GranularityDay,
+	})
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	fmt.Printf("Total API requests: %v\n", usage["total_requests"])
-	fmt.Printf("Successful requests: %v\n", usage["successful_requests"])
-	fmt.Printf("Failed requests: %v\n", usage["failed_requests"])
+	fmt.Printf("Total API requests: %d\n", usage.TotalRequests)
+	fmt.Printf("Successful requests: %d\n", usage.SuccessfulRequests)
+	fmt.Printf("Failed requests: %d\n", usage.FailedRequests)
+	fmt.Printf("Credits used: %d\n", usage.CreditsUsed)
 
 	// Calculate success rate
-	if totalRequests, ok := usage["total_requests"].(float64); ok && totalRequests > 0 {
-		if successfulRequests, ok := usage["successful_requests"].(float64); ok {
-			successRate := (successfulRequests / totalRequests) * 100
-			fmt.Printf("Success rate: %.2f%%\n", successRate)
-		}
+	if usage.TotalRequests > 0 {
+		successRate := float64(usage.SuccessfulRequests) / float64(usage.TotalRequests) * 100
+		fmt.Printf("Success rate: %.2f%%\n", successRate)
+	}
+
+	fmt.Println()
+	for _, day := range usage.Series {
+		fmt.Printf("%s: %d requests, %d credits\n", day.Period, day.Requests, day.Credits)
 	}
 }
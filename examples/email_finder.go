@@ -1,3 +1,5 @@
+//go:build ignore
+
 package main
 
 import (
@@ -21,15 +23,15 @@ func main() {
 		log.Fatal(err)
 	}
 
-	fmt.Printf("Email found: %v\n", result["email"])
-	fmt.Printf("Confidence: %v%%\n", result["confidence"])
-	fmt.Printf("Pattern: %v\n", result["pattern"])
-	fmt.Printf("Verified: %v\n", result["verified"])
+	fmt.Printf("Email found: %s\n", result.Email)
+	fmt.Printf("Confidence: %.0f%%\n", result.Confidence)
+	fmt.Printf("Pattern: %s\n", result.Pattern)
+	fmt.Printf("Verified: %t\n", result.Verified)
 
-	if alternatives, ok := result["alternatives"].([]interface{}); ok && len(alternatives) > 0 {
+	if len(result.Alternatives) > 0 {
 		fmt.Println("\nAlternative patterns:")
-		for _, alt := range alternatives {
-			fmt.Printf("  - %v\n", alt)
+		for _, alt := range result.Alternatives {
+			fmt.Printf("  - %s (%.0f%%)\n", alt.Email, alt.Confidence)
 		}
 	}
 
@@ -1,3 +1,5 @@
+//go:build ignore
+
 package main
 
 import (
@@ -55,7 +57,7 @@ func main() {
 		}
 
 		if status.Status == "completed" {
-			fmt.Println("\nBatch verification completed!\n")
+			fmt.Print("\nBatch verification completed!\n\n")
 			break
 		} else if status.Status == "failed" {
 			fmt.Println("\nBatch verification failed!")
@@ -0,0 +1,128 @@
+package emaillistchecker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+// SourceLocal marks results worked out without the API, by VerifyLocal or for
+// international addresses that fail ParseAddress.
+// Results from the API leave VerifyResponse.Source empty.
+const SourceLocal = "local"
+
+// Reasons reported by VerifyLocal
+const (
+	ReasonInvalidSyntax = "INVALID_SYNTAX"
+	ReasonNoMailServer  = "NO_MAIL_SERVER"
+	ReasonNullMX        = "NULL_MX"
+	ReasonDisposable    = "DISPOSABLE"
+	ReasonDNSError      = "DNS_ERROR"
+	ReasonNotChecked    = "MAILBOX_NOT_CHECKED"
+)
+
+// localLookupTimeout bounds the DNS lookups of VerifyLocal
+const localLookupTimeout = 5 * time.Second
+
+// SetFallback makes Verify check addresses locally with VerifyLocal, instead
+// of returning an error, when the API call fails with an error for which when
+// returns true. FallbackOnOutage is a sensible default. Pass nil to disable.
+// Local results are not cached and cost no credits.
+func (c *Client) SetFallback(when func(error) bool) {
+	c.fallback = when
+}
+
+// FallbackOnOutage reports whether err means the API could not verify an
+// address for reasons unrelated to the address: the API is unreachable or
+// failing, rate limited, or out of credits or budget
+func FallbackOnOutage(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var (
+		credits   *InsufficientCreditsError
+		budget    *BudgetExceededError
+		rateLimit *RateLimitError
+		apiErr    *APIError
+		netErr    net.Error
+	)
+	switch {
+	case errors.As(err, &credits), errors.As(err, &budget), errors.As(err, &rateLimit):
+		return true
+	case errors.As(err, &apiErr):
+		return apiErr.StatusCode >= 500
+	case errors.As(err, &netErr):
+		return true
+	}
+	return false
+}
+
+// VerifyLocal checks an address without the API: its syntax as checked by
+// ParseAddress, the client's DomainLists and the MX (or A/AAAA) records of its
+// domain, looked up through the client's Resolver. The mailbox itself cannot be checked, so
+// the result is undeliverable when the address or domain cannot receive mail
+// and unknown otherwise. Source is set to SourceLocal.
+func (c *Client) VerifyLocal(ctx context.Context, email string) *VerifyResponse {
+	result := &VerifyResponse{
+		Email:  email,
+		Result: "unknown",
+		Source: SourceLocal,
+	}
+
+	addr, err := ParseAddress(email)
+	if err != nil || !validSyntax(addr.ASCII()) {
+		result.Result, result.Reason = "undeliverable", ReasonInvalidSyntax
+		return result
+	}
+	// DNS and the domain lists use the ASCII form of an IDN
+	domain := addr.ASCIIDomain
+	result.Domain, result.SMTPUTF8 = addr.Domain, addr.SMTPUTF8
+	lists := c.domainLists()
+	result.Disposable = lists.IsDisposable(domain)
+	result.Free = lists.IsFree(domain)
+
+	ctx, cancel := context.WithTimeout(ctx, localLookupTimeout)
+	defer cancel()
+
+	mx, err := c.dnsResolver().LookupMX(ctx, domain)
+	switch {
+	case err == nil && len(mx) == 1 && (mx[0].Host == "." || mx[0].Host == ""):
+		// A null MX record declares that the domain accepts no mail (RFC 7505)
+		result.Result, result.Reason = "undeliverable", ReasonNullMX
+		return result
+	case err == nil && len(mx) > 0:
+		result.MXFound = true
+		for _, record := range mx {
+			result.MXRecords = append(result.MXRecords, strings.TrimSuffix(record.Host, "."))
+		}
+	case isNotFound(err) || err == nil:
+		// Mail may be delivered to the A record when no MX exists (RFC 5321 §5.1)
+		hosts, err := c.dnsResolver().LookupHost(ctx, domain)
+		if isNotFound(err) || err == nil && len(hosts) == 0 {
+			result.Result, result.Reason = "undeliverable", ReasonNoMailServer
+			return result
+		}
+		if err != nil {
+			result.Reason = ReasonDNSError
+			return result
+		}
+	default:
+		result.Reason = ReasonDNSError
+		return result
+	}
+
+	result.Reason, result.Score = ReasonNotChecked, 0.5
+	if result.Disposable {
+		result.Reason, result.Score = ReasonDisposable, 0.2
+	}
+	return result
+}
+
+// isNotFound reports whether a DNS error means the name or record does not exist
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,76 @@
+package emaillistchecker_test
+
+import (
+	"context"
+	"testing"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+func TestFallbackOnOutage(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	srv.Fail("/verify", emaillistcheckertest.Fault{Status: 503})
+	client := srv.Client()
+
+	resolver := emaillistcheckertest.NewResolver()
+	resolver.SetMX("example.com", "mx.example.com")
+	client.SetResolver(resolver)
+
+	if _, err := client.Verify("jane@example.com", nil, true); err == nil {
+		t.Fatal("Verify without fallback: want an error")
+	}
+
+	client.SetFallback(emaillistchecker.FallbackOnOutage)
+	result, err := client.Verify("jane@example.com", nil, true)
+	if err != nil {
+		t.Fatalf("Verify with fallback: %v", err)
+	}
+	if result.Source != emaillistchecker.SourceLocal || result.Result != "unknown" || result.Reason != emaillistchecker.ReasonNotChecked {
+		t.Errorf("Verify with fallback = %s %s %s, want local unknown %s", result.Source, result.Result, result.Reason, emaillistchecker.ReasonNotChecked)
+	}
+
+	// Errors about the request are not outages
+	srv.Fail("/verify", emaillistcheckertest.Fault{Status: 401})
+	if _, err := client.Verify("jane@example.com", nil, true); err == nil {
+		t.Error("Verify with an invalid key: want an error")
+	}
+}
+
+func TestVerifyLocal(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	resolver := emaillistcheckertest.NewResolver()
+	resolver.SetMX("example.com", "mx1.example.com", "mx2.example.com")
+	resolver.SetMX("null.example", ".")
+	resolver.SetHosts("a-only.example", "192.0.2.1")
+	resolver.SetMX("mailinator.com", "mx.mailinator.com")
+	resolver.Fail("broken.example")
+	client.SetResolver(resolver)
+
+	tests := []struct {
+		email  string
+		result string
+		reason string
+	}{
+		{"jane@example.com", "unknown", emaillistchecker.ReasonNotChecked},
+		{"jane@a-only.example", "unknown", emaillistchecker.ReasonNotChecked},
+		{"jane@null.example", "undeliverable", emaillistchecker.ReasonNullMX},
+		{"jane@missing.example", "undeliverable", emaillistchecker.ReasonNoMailServer},
+		{"jane@broken.example", "unknown", emaillistchecker.ReasonDNSError},
+		{"jane@@example.com", "undeliverable", emaillistchecker.ReasonInvalidSyntax},
+		{"jane@mailinator.com", "unknown", emaillistchecker.ReasonDisposable},
+	}
+	for _, tt := range tests {
+		result := client.VerifyLocal(context.Background(), tt.email)
+		if result.Source != emaillistchecker.SourceLocal || result.Result != tt.result || result.Reason != tt.reason {
+			t.Errorf("VerifyLocal(%q) = %s %s, want %s %s", tt.email, result.Result, result.Reason, tt.result, tt.reason)
+		}
+	}
+	if len(srv.Requests()) != 0 {
+		t.Errorf("VerifyLocal sent %d requests", len(srv.Requests()))
+	}
+}
@@ -0,0 +1,201 @@
+package emaillistchecker
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// FinderResult represents the result of an email finder lookup
+type FinderResult struct {
+	Email        string              `json:"email"`
+	Confidence   float64             `json:"confidence"`
+	Pattern      string              `json:"pattern"`
+	Verified     bool                `json:"verified"`
+	Domain       string              `json:"domain"`
+	FirstName    string              `json:"first_name"`
+	LastName     string              `json:"last_name"`
+	Alternatives []FinderAlternative `json:"alternatives"`
+}
+
+// FinderAlternative is a candidate address returned alongside the best finder match
+type FinderAlternative struct {
+	Email      string  `json:"email"`
+	Confidence float64 `json:"confidence"`
+	Pattern    string  `json:"pattern"`
+	// Verified is the API's own flag and is left as returned
+	Verified bool `json:"verified"`
+
+	// Verification holds the Verify result when the alternative was checked
+	// through FindEmailOptions.VerifyTop; it is nil otherwise.
+	Verification *VerifyResponse `json:"verification,omitempty"`
+}
+
+// UnmarshalJSON accepts alternatives given either as plain addresses or as objects
+func (a *FinderAlternative) UnmarshalJSON(data []byte) error {
+	var email string
+	if err := json.Unmarshal(data, &email); err == nil {
+		*a = FinderAlternative{Email: email}
+		return nil
+	}
+
+	type alternative FinderAlternative
+	var alt alternative
+	if err := json.Unmarshal(data, &alt); err != nil {
+		return err
+	}
+	*a = FinderAlternative(alt)
+	return nil
+}
+
+// FindEmailOptions configures FindEmailWithOptions
+type FindEmailOptions struct {
+	// VerifyTop verifies the N alternatives with the highest confidence using Verify
+	VerifyTop int
+	// SMTPCheck and Timeout are passed to Verify for alternatives checked through VerifyTop
+	SMTPCheck bool
+	Timeout   *int
+	// SkipNormalize sends names exactly as given instead of normalizing them first
+	SkipNormalize bool
+}
+
+// FindEmailWithOptions finds an email address by name and domain.
+// Names are normalized with NormalizeFirstName and NormalizeLastName before the request
+// unless SkipNormalize is set.
+func (c *Client) FindEmailWithOptions(firstName, lastName, domain string, opts FindEmailOptions) (*FinderResult, error) {
+	if !opts.SkipNormalize {
+		firstName = NormalizeFirstName(firstName)
+		lastName = NormalizeLastName(lastName)
+	}
+
+	spend, err := c.reserveCredits("FindEmail", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	req := FindEmailRequest{
+		FirstName: firstName,
+		LastName:  lastName,
+		Domain:    strings.ToLower(strings.TrimSpace(domain)),
+	}
+
+	var result struct {
+		Data *FinderResult `json:"data"`
+	}
+
+	err = c.request("FindEmail", "POST", "/finder/email", req, &result)
+	if err != nil {
+		c.settle(spend, 0)
+		return nil, err
+	}
+	c.spendCredits("FindEmail", 1)
+
+	if result.Data == nil {
+		return &FinderResult{}, nil
+	}
+
+	finder := result.Data
+	sort.SliceStable(finder.Alternatives, func(i, j int) bool {
+		return finder.Alternatives[i].Confidence > finder.Alternatives[j].Confidence
+	})
+
+	for i := 0; i < opts.VerifyTop && i < len(finder.Alternatives); i++ {
+		alt := &finder.Alternatives[i]
+		verification, err := c.Verify(alt.Email, opts.Timeout, opts.SMTPCheck)
+		if err != nil {
+			return finder, err
+		}
+		alt.Verification = verification
+	}
+
+	return finder, nil
+}
+
+// NormalizeFirstName prepares a first name for the finder: diacritics are folded to ASCII
+// and the name is lowercased. Compound and middle names are kept, separated by single
+// spaces ("Mary Ann" -> "mary ann"). Names with letters that have no ASCII spelling,
+// such as Cyrillic or Han names, are only lowercased and trimmed.
+func NormalizeFirstName(name string) string {
+	folded, ok := foldName(name)
+	if !ok {
+		return keepName(name)
+	}
+	return folded
+}
+
+// NormalizeLastName prepares a last name for the finder: diacritics are folded to ASCII,
+// the name is lowercased and multi-part surnames such as "van der Berg" are joined.
+// Hyphenated surnames keep their hyphen. Names with letters that have no ASCII spelling
+// are only lowercased and trimmed.
+func NormalizeLastName(name string) string {
+	folded, ok := foldName(name)
+	if !ok {
+		return keepName(name)
+	}
+	return strings.Join(strings.Fields(folded), "")
+}
+
+// keepName lowercases a name that cannot be folded and collapses its spaces
+func keepName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// foldName lowercases a name, folds diacritics and drops characters that cannot
+// appear in a local part, keeping spaces and hyphens as word separators.
+// ok is false if the name has a letter or digit outside ASCII that cannot be folded.
+func foldName(name string) (folded string, ok bool) {
+	ok = true
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if spelling, found := diacriticFolds[r]; found {
+			b.WriteString(spelling)
+			continue
+		}
+
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+		case r == '-':
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			b.WriteRune(' ')
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			ok = false
+		}
+	}
+
+	// Trim stray hyphens left around dropped characters, e.g. "o'neil - smith"
+	fields := strings.Fields(b.String())
+	for i, f := range fields {
+		fields[i] = strings.Trim(f, "-")
+	}
+	return strings.Join(fields, " "), ok
+}
+
+// diacriticFolds maps lowercase Latin letters with diacritics to their ASCII spelling
+var diacriticFolds = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae",
+	'ç': "c", 'ć': "c", 'č': "c", 'ĉ': "c", 'ċ': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ĕ': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ĝ': "g", 'ğ': "g", 'ġ': "g", 'ģ': "g",
+	'ĥ': "h", 'ħ': "h",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ĩ': "i", 'ī': "i", 'ĭ': "i", 'į': "i", 'ı': "i",
+	'ĵ': "j",
+	'ķ': "k",
+	'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ŀ': "l", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ņ': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ŏ': "o", 'ő': "o",
+	'œ': "oe",
+	'ŕ': "r", 'ŗ': "r", 'ř': "r",
+	'ś': "s", 'ŝ': "s", 'ş': "s", 'š': "s", 'ș': "s",
+	'ß': "ss",
+	'ţ': "t", 'ť': "t", 'ŧ': "t", 'ț': "t",
+	'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ũ': "u", 'ū': "u", 'ŭ': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ŵ': "w",
+	'ý': "y", 'ÿ': "y", 'ŷ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}
@@ -0,0 +1,448 @@
+package emaillistchecker
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Output formats supported by FindEmails
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// FindEmailsOptions configures a bulk finder run
+type FindEmailsOptions struct {
+	// Column names in the input header. Defaults: first_name, last_name, domain.
+	FirstNameColumn string
+	LastNameColumn  string
+	DomainColumn    string
+
+	// Output receives the enriched rows. Format is FormatCSV (default) or FormatNDJSON.
+	Output io.Writer
+	Format string
+
+	// Concurrency is the number of lookups in flight (default 5).
+	Concurrency int
+	// RequestsPerSecond limits API calls across all workers; zero means unlimited.
+	RequestsPerSecond float64
+
+	// Verify checks every found address with Verify using SMTPCheck.
+	Verify    bool
+	SMTPCheck bool
+
+	// CheckpointFile records progress so an interrupted run can resume.
+	// When it exists, rows already written are skipped and no CSV header is emitted,
+	// so Output should be opened in append mode.
+	CheckpointFile string
+}
+
+// FindEmailsSummary reports the outcome of a bulk finder run
+type FindEmailsSummary struct {
+	Processed int
+	Found     int
+	Failed    int
+	Skipped   int
+}
+
+// FindEmailsRow is one enriched row written by FindEmails in NDJSON format.
+// Record holds the input cells as read; Input maps header names to them, keeping
+// the first cell for a repeated name and leaving out cells beyond the header.
+type FindEmailsRow struct {
+	Row          int               `json:"row"`
+	Record       []string          `json:"record"`
+	Input        map[string]string `json:"input"`
+	Email        string            `json:"email,omitempty"`
+	Confidence   float64           `json:"confidence,omitempty"`
+	Pattern      string            `json:"pattern,omitempty"`
+	Verification string            `json:"verification,omitempty"`
+	Error        string            `json:"error,omitempty"`
+}
+
+// findEmailsColumns are appended to the input header in CSV output. Input cells
+// are written back in place, padded to the header; cells beyond the header
+// follow these columns.
+var findEmailsColumns = []string{"email", "confidence", "pattern", "verification", "error"}
+
+// findEmailsCheckpoint is the persisted progress of a bulk finder run
+type findEmailsCheckpoint struct {
+	Rows int `json:"rows"`
+}
+
+// findJob is a single input row queued for lookup
+type findJob struct {
+	index  int
+	record []string
+}
+
+// FindEmails reads contacts from a CSV with a header row and looks up an address for each
+// one with FindEmail. Lookups run concurrently and rows are written to opts.Output in input order.
+func (c *Client) FindEmails(ctx context.Context, r io.Reader, opts FindEmailsOptions) (*FindEmailsSummary, error) {
+	if opts.Output == nil {
+		return nil, errors.New("FindEmails: Output is required")
+	}
+	if opts.Format == "" {
+		opts.Format = FormatCSV
+	}
+	if opts.Format != FormatCSV && opts.Format != FormatNDJSON {
+		return nil, fmt.Errorf("FindEmails: unsupported format %q", opts.Format)
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 5
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	firstCol, lastCol, domainCol, err := findEmailsColumnIndexes(header, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint, err := loadFindEmailsCheckpoint(opts.CheckpointFile)
+	if err != nil {
+		return nil, err
+	}
+
+	out := newFindEmailsWriter(opts.Output, opts.Format, header)
+	if checkpoint.Rows == 0 {
+		if err := out.writeHeader(); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var throttle <-chan time.Time
+	if opts.RequestsPerSecond > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.RequestsPerSecond))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	// wait blocks until the rate limiter allows another API call
+	wait := func() error {
+		if throttle == nil {
+			return ctx.Err()
+		}
+		select {
+		case <-throttle:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	client := c.WithContext(ctx)
+	jobs := make(chan findJob)
+	results := make(chan FindEmailsRow)
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				row, ok := client.findEmailsRow(job, header, firstCol, lastCol, domainCol, opts, wait)
+				if !ok {
+					// Cancelled before the row was looked up; it is left for a resumed run
+					return
+				}
+				select {
+				case results <- row:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	summary := &FindEmailsSummary{Skipped: checkpoint.Rows}
+
+	// Feed rows to the workers; reading stops early if the context is cancelled.
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(jobs)
+		for index := 0; ; index++ {
+			record, err := reader.Read()
+			if err == io.EOF {
+				readErr <- nil
+				return
+			}
+			if err != nil {
+				readErr <- fmt.Errorf("failed to read CSV row %d: %w", index+1, err)
+				return
+			}
+			if index < checkpoint.Rows {
+				continue
+			}
+			select {
+			case jobs <- findJob{index: index, record: record}:
+			case <-ctx.Done():
+				readErr <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Write rows in input order, checkpointing after each one. Nothing more is
+	// written once the context is done, so the checkpoint never passes a row
+	// that was not written.
+	pending := map[int]FindEmailsRow{}
+	next := checkpoint.Rows
+	var writeErr error
+	for row := range results {
+		if writeErr != nil || ctx.Err() != nil {
+			continue
+		}
+		pending[row.Row] = row
+
+		for ctx.Err() == nil {
+			ready, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+
+			if err := out.writeRow(ready); err != nil {
+				writeErr = err
+				cancel()
+				break
+			}
+
+			summary.Processed++
+			if ready.Error != "" {
+				summary.Failed++
+			} else if ready.Email != "" {
+				summary.Found++
+			}
+
+			next++
+			if err := saveFindEmailsCheckpoint(opts.CheckpointFile, next); err != nil {
+				writeErr = err
+				cancel()
+				break
+			}
+		}
+	}
+
+	if writeErr != nil {
+		return summary, writeErr
+	}
+	if err := <-readErr; err != nil {
+		return summary, err
+	}
+	if err := out.flush(); err != nil {
+		return summary, err
+	}
+
+	return summary, ctx.Err()
+}
+
+// findEmailsRow runs the finder (and optionally Verify) for a single input row.
+// ok is false when the client's context was cancelled before the row was done.
+func (c *Client) findEmailsRow(job findJob, header []string, firstCol, lastCol, domainCol int, opts FindEmailsOptions, wait func() error) (row FindEmailsRow, ok bool) {
+	row = FindEmailsRow{
+		Row:    job.index,
+		Record: job.record,
+		Input:  make(map[string]string, len(header)),
+	}
+	fail := func(err error) (FindEmailsRow, bool) {
+		if ctxErr := c.context().Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+			return row, false
+		}
+		row.Error = err.Error()
+		return row, true
+	}
+	for i, name := range header {
+		if _, ok := row.Input[name]; !ok && i < len(job.record) {
+			row.Input[name] = job.record[i]
+		}
+	}
+
+	field := func(col int) string {
+		if col < len(job.record) {
+			return strings.TrimSpace(job.record[col])
+		}
+		return ""
+	}
+
+	firstName, lastName, domain := field(firstCol), field(lastCol), field(domainCol)
+	if firstName == "" || lastName == "" || domain == "" {
+		row.Error = "missing first name, last name or domain"
+		return row, true
+	}
+
+	if err := wait(); err != nil {
+		return fail(err)
+	}
+
+	result, err := c.FindEmail(firstName, lastName, domain)
+	if err != nil {
+		return fail(err)
+	}
+
+	row.Email = result.Email
+	row.Confidence = result.Confidence
+	row.Pattern = result.Pattern
+
+	if opts.Verify && row.Email != "" {
+		if err := wait(); err != nil {
+			return fail(err)
+		}
+
+		verification, err := c.Verify(row.Email, nil, opts.SMTPCheck)
+		if err != nil {
+			return fail(err)
+		}
+		row.Verification = verification.Result
+	}
+
+	return row, true
+}
+
+// findEmailsColumnIndexes resolves the configured column names against the header
+func findEmailsColumnIndexes(header []string, opts FindEmailsOptions) (int, int, int, error) {
+	names := []string{opts.FirstNameColumn, opts.LastNameColumn, opts.DomainColumn}
+	defaults := []string{"first_name", "last_name", "domain"}
+
+	var indexes [3]int
+	for i, name := range names {
+		if name == "" {
+			name = defaults[i]
+		}
+
+		indexes[i] = -1
+		for col, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), name) {
+				indexes[i] = col
+				break
+			}
+		}
+		if indexes[i] < 0 {
+			return 0, 0, 0, fmt.Errorf("column %q not found in CSV header", name)
+		}
+	}
+
+	return indexes[0], indexes[1], indexes[2], nil
+}
+
+// loadFindEmailsCheckpoint reads a checkpoint file, returning an empty checkpoint if it does not exist
+func loadFindEmailsCheckpoint(path string) (findEmailsCheckpoint, error) {
+	var checkpoint findEmailsCheckpoint
+	if path == "" {
+		return checkpoint, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return checkpoint, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return checkpoint, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
+
+// saveFindEmailsCheckpoint atomically records the number of rows written
+func saveFindEmailsCheckpoint(path string, rows int) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(findEmailsCheckpoint{Rows: rows})
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// findEmailsWriter writes enriched rows as CSV or NDJSON
+type findEmailsWriter struct {
+	format string
+	header []string
+	csv    *csv.Writer
+	json   *json.Encoder
+}
+
+func newFindEmailsWriter(w io.Writer, format string, header []string) *findEmailsWriter {
+	out := &findEmailsWriter{format: format, header: header}
+	if format == FormatNDJSON {
+		out.json = json.NewEncoder(w)
+	} else {
+		out.csv = csv.NewWriter(w)
+	}
+	return out
+}
+
+func (w *findEmailsWriter) writeHeader() error {
+	if w.csv == nil {
+		return nil
+	}
+	return w.csv.Write(append(append([]string{}, w.header...), findEmailsColumns...))
+}
+
+func (w *findEmailsWriter) writeRow(row FindEmailsRow) error {
+	if w.json != nil {
+		return w.json.Encode(row)
+	}
+
+	record := make([]string, len(w.header), len(w.header)+len(findEmailsColumns)+len(row.Record))
+	copy(record, row.Record)
+
+	confidence := ""
+	if row.Email != "" {
+		confidence = strconv.FormatFloat(row.Confidence, 'f', -1, 64)
+	}
+	record = append(record, row.Email, confidence, row.Pattern, row.Verification, row.Error)
+	if len(row.Record) > len(w.header) {
+		record = append(record, row.Record[len(w.header):]...)
+	}
+
+	if err := w.csv.Write(record); err != nil {
+		return err
+	}
+	// Flush per row so the checkpoint never runs ahead of the output
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *findEmailsWriter) flush() error {
+	if w.csv == nil {
+		return nil
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
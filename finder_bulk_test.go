@@ -0,0 +1,43 @@
+package emaillistchecker_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+func TestFindEmailsKeepsRecords(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	// A repeated "note" header, a short row and a row with cells beyond the header
+	input := "first_name,last_name,domain,note,note\n" +
+		"Jane,Doe,example.com,a,b\n" +
+		"John,Smith,example.com\n" +
+		"Ann,Lee,example.com,c,d,extra\n"
+
+	var out bytes.Buffer
+	summary, err := client.FindEmails(context.Background(), strings.NewReader(input), emaillistchecker.FindEmailsOptions{
+		Output:      &out,
+		Concurrency: 2,
+	})
+	if err != nil {
+		t.Fatalf("FindEmails: %v", err)
+	}
+	if summary.Processed != 3 || summary.Found != 3 {
+		t.Errorf("summary = %+v, want 3 processed and found", summary)
+	}
+
+	want := "first_name,last_name,domain,note,note,email,confidence,pattern,verification,error\n" +
+		"Jane,Doe,example.com,a,b,jane.doe@example.com,90,{first}.{last},,\n" +
+		"John,Smith,example.com,,,john.smith@example.com,90,{first}.{last},,\n" +
+		"Ann,Lee,example.com,c,d,ann.lee@example.com,90,{first}.{last},,,extra\n"
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
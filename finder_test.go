@@ -0,0 +1,57 @@
+package emaillistchecker_test
+
+import (
+	"testing"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+func TestNormalizeNames(t *testing.T) {
+	tests := []struct {
+		first, last         string
+		wantFirst, wantLast string
+	}{
+		{"John", "Doe", "john", "doe"},
+		{"José María", "van der Berg", "jose maria", "vanderberg"},
+		{"Mary  Ann", "O'Neil-Smith", "mary ann", "oneil-smith"},
+		{"Zoë", "Müller", "zoe", "muller"},
+		// Names without an ASCII spelling are kept rather than emptied
+		{"Иван", "Петров", "иван", "петров"},
+		{" 太郎 ", "山田", "太郎", "山田"},
+	}
+	for _, tt := range tests {
+		if got := emaillistchecker.NormalizeFirstName(tt.first); got != tt.wantFirst {
+			t.Errorf("NormalizeFirstName(%q) = %q, want %q", tt.first, got, tt.wantFirst)
+		}
+		if got := emaillistchecker.NormalizeLastName(tt.last); got != tt.wantLast {
+			t.Errorf("NormalizeLastName(%q) = %q, want %q", tt.last, got, tt.wantLast)
+		}
+	}
+}
+
+func TestFindEmailVerifyTop(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	result, err := client.FindEmailWithOptions("John", "Doe", "example.com", emaillistchecker.FindEmailOptions{VerifyTop: 1})
+	if err != nil {
+		t.Fatalf("FindEmailWithOptions: %v", err)
+	}
+	if len(result.Alternatives) != 2 {
+		t.Fatalf("got %d alternatives, want 2", len(result.Alternatives))
+	}
+
+	top := result.Alternatives[0]
+	if top.Verification == nil || top.Verification.Result != "deliverable" {
+		t.Errorf("Alternatives[0].Verification = %+v, want a deliverable result", top.Verification)
+	}
+	// The API's flag is not replaced by the verification result
+	if top.Verified {
+		t.Error("Alternatives[0].Verified = true, want the API's false")
+	}
+	if result.Alternatives[1].Verification != nil {
+		t.Error("Alternatives[1] was verified, want only the top one")
+	}
+}
@@ -0,0 +1,611 @@
+package emaillistchecker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:generate go run nfc_gen.go
+
+// Address is an email address split into its parts and normalized for
+// transport. International addresses keep their Unicode form for display and
+// carry the ASCII (punycode) form of the domain for DNS and the API.
+type Address struct {
+	// Input is the address as given
+	Input string
+	// Local is the local part in Unicode normalization form C
+	Local string
+	// Domain is the domain in Unicode, lowercased and in normalization form C
+	Domain string
+	// ASCIIDomain is the domain with each label in ASCII, e.g. xn--bcher-kva.de for bücher.de
+	ASCIIDomain string
+	// SMTPUTF8 reports whether the local part is not ASCII, so mail can only
+	// be delivered by servers supporting SMTPUTF8 (RFC 6531)
+	SMTPUTF8 bool
+}
+
+// String returns the address in Unicode form
+func (a *Address) String() string {
+	return a.Local + "@" + a.Domain
+}
+
+// ASCII returns the address with an ASCII domain, the form sent to the API.
+// The local part stays Unicode when SMTPUTF8 is set; it has no ASCII form.
+func (a *Address) ASCII() string {
+	return a.Local + "@" + a.ASCIIDomain
+}
+
+// AddressError is returned when an address fails the syntax pre-checks of
+// ParseAddress
+type AddressError struct {
+	Email  string
+	Reason string
+}
+
+func (e *AddressError) Error() string {
+	return fmt.Sprintf("invalid address %q: %s", e.Email, e.Reason)
+}
+
+// ParseAddress checks the syntax of an email address, including
+// internationalized (EAI) addresses, and normalizes it. The local part is
+// normalized to NFC and may contain any printable Unicode character except
+// spaces, or be a quoted string such as "john doe", where spaces and
+// specials are allowed and backslash escapes the next character; it is kept
+// with its quotes. Domain labels follow the IDNA
+// rules: letters, digits, combining marks and hyphens, no leading mark or
+// hyphen, no trailing hyphen and at most 63 octets in ASCII form.
+func ParseAddress(email string) (*Address, error) {
+	fail := func(format string, args ...interface{}) (*Address, error) {
+		return nil, &AddressError{Email: email, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	address := strings.TrimSpace(email)
+	if !utf8.ValidString(address) {
+		return fail("not valid UTF-8")
+	}
+	at := strings.LastIndex(address, "@")
+	if at < 1 || at == len(address)-1 {
+		return fail("missing local part or domain")
+	}
+
+	local := NormalizeNFC(address[:at])
+	if len(local) > 64 {
+		return fail("local part longer than 64 octets")
+	}
+	if reason := checkLocalPart(local); reason != "" {
+		return fail("%s", reason)
+	}
+	smtpUTF8 := !isASCII(local)
+
+	domain := address[at+1:]
+	if strings.HasPrefix(domain, "[") {
+		return fail("address literals are not supported")
+	}
+	unicodeDomain, asciiDomain, reason := idnaDomain(domain)
+	if reason != "" {
+		return fail("%s", reason)
+	}
+	if !strings.Contains(asciiDomain, ".") {
+		return fail("domain has no top-level domain")
+	}
+
+	a := &Address{
+		Input:       email,
+		Local:       local,
+		Domain:      unicodeDomain,
+		ASCIIDomain: asciiDomain,
+		SMTPUTF8:    smtpUTF8,
+	}
+	if len(a.ASCII()) > 254 {
+		return fail("address longer than 254 octets")
+	}
+	return a, nil
+}
+
+// checkLocalPart checks a local part is a dot-atom or a quoted string, in
+// the internationalized forms of RFC 6531
+func checkLocalPart(local string) string {
+	if strings.HasPrefix(local, `"`) {
+		return checkQuotedLocalPart(local)
+	}
+	if strings.HasPrefix(local, ".") || strings.HasSuffix(local, ".") || strings.Contains(local, "..") {
+		return "misplaced dot in local part"
+	}
+	for _, r := range local {
+		switch {
+		case r < utf8.RuneSelf && (r <= ' ' || r == 0x7f || strings.ContainsRune(`"(),:;<>@[\]`, r)):
+			return fmt.Sprintf("character %q not allowed in local part", r)
+		case r >= utf8.RuneSelf && (!unicode.IsGraphic(r) || unicode.IsSpace(r)):
+			return fmt.Sprintf("character %U not allowed in local part", r)
+		}
+	}
+	return ""
+}
+
+// checkQuotedLocalPart checks a quoted local part: printable ASCII other
+// than a quote or backslash, non-ASCII graphic characters, and quoted pairs
+// of a backslash and a printable ASCII character (RFC 5321 §4.1.2)
+func checkQuotedLocalPart(local string) string {
+	if len(local) < 2 || !strings.HasSuffix(local, `"`) {
+		return "unterminated quoted local part"
+	}
+	escaped := false
+	for _, r := range local[1 : len(local)-1] {
+		switch {
+		case r < utf8.RuneSelf && (r < ' ' || r == 0x7f):
+			return fmt.Sprintf("character %q not allowed in local part", r)
+		case r >= utf8.RuneSelf && !unicode.IsGraphic(r):
+			return fmt.Sprintf("character %U not allowed in local part", r)
+		case escaped:
+			if r >= utf8.RuneSelf {
+				return fmt.Sprintf("character %U cannot be escaped", r)
+			}
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			return "unescaped quote in quoted local part"
+		}
+	}
+	if escaped {
+		return "unterminated quoted local part"
+	}
+	return ""
+}
+
+// DomainToASCII converts a domain to the ASCII form used by DNS, encoding
+// each Unicode label with punycode, e.g. bücher.de becomes xn--bcher-kva.de.
+// The domain is lowercased; ASCII domains are otherwise returned unchanged.
+func DomainToASCII(domain string) (string, error) {
+	_, ascii, reason := idnaDomain(domain)
+	if reason != "" {
+		return "", fmt.Errorf("invalid domain %q: %s", domain, reason)
+	}
+	return ascii, nil
+}
+
+// DomainToUnicode converts a domain to its Unicode form, decoding punycode
+// labels, e.g. xn--bcher-kva.de becomes bücher.de
+func DomainToUnicode(domain string) (string, error) {
+	unicodeDomain, _, reason := idnaDomain(domain)
+	if reason != "" {
+		return "", fmt.Errorf("invalid domain %q: %s", domain, reason)
+	}
+	return unicodeDomain, nil
+}
+
+// labelSeparators maps the ideographic and fullwidth full stops to dots, as
+// IDNA requires
+var labelSeparators = strings.NewReplacer("。", ".", "．", ".", "｡", ".")
+
+// idnaDomain returns the Unicode and ASCII forms of a domain, or the reason
+// it is invalid
+func idnaDomain(domain string) (unicodeDomain, asciiDomain, reason string) {
+	domain = strings.TrimSpace(domain)
+	if !utf8.ValidString(domain) {
+		return "", "", "not valid UTF-8"
+	}
+	domain = strings.TrimSuffix(labelSeparators.Replace(domain), ".")
+	if domain == "" {
+		return "", "", "empty domain"
+	}
+
+	labels := strings.Split(domain, ".")
+	unicodeLabels := make([]string, len(labels))
+	asciiLabels := make([]string, len(labels))
+	for i, label := range labels {
+		if label == "" {
+			return "", "", "empty label"
+		}
+
+		switch {
+		case isASCII(label) && strings.HasPrefix(strings.ToLower(label), "xn--"):
+			// An A-label must decode to a valid U-label that encodes back to it
+			label = strings.ToLower(label)
+			decoded, err := punycodeDecode(label[4:])
+			if err != nil {
+				return "", "", fmt.Sprintf("label %q: %v", label, err)
+			}
+			u := string(decoded)
+			if isASCII(u) {
+				return "", "", fmt.Sprintf("label %q is not a valid A-label", label)
+			}
+			if reason := checkULabel(u); reason != "" {
+				return "", "", fmt.Sprintf("label %q: %s", label, reason)
+			}
+			if NormalizeNFC(u) != u || "xn--"+punycodeEncode(decoded) != label {
+				return "", "", fmt.Sprintf("label %q is not a valid A-label", label)
+			}
+			unicodeLabels[i], asciiLabels[i] = u, label
+		case isASCII(label):
+			label = strings.ToLower(label)
+			if reason := checkLDHLabel(label); reason != "" {
+				return "", "", fmt.Sprintf("label %q: %s", label, reason)
+			}
+			unicodeLabels[i], asciiLabels[i] = label, label
+		default:
+			u := NormalizeNFC(strings.ToLower(NormalizeNFC(label)))
+			if reason := checkULabel(u); reason != "" {
+				return "", "", fmt.Sprintf("label %q: %s", label, reason)
+			}
+			unicodeLabels[i], asciiLabels[i] = u, "xn--"+punycodeEncode([]rune(u))
+		}
+
+		if len(asciiLabels[i]) > 63 {
+			return "", "", fmt.Sprintf("label %q longer than 63 octets", label)
+		}
+	}
+
+	asciiDomain = strings.Join(asciiLabels, ".")
+	if len(asciiDomain) > 253 {
+		return "", "", "domain longer than 253 octets"
+	}
+	return strings.Join(unicodeLabels, "."), asciiDomain, ""
+}
+
+// checkLDHLabel checks an ASCII label has only letters, digits and hyphens
+func checkLDHLabel(label string) string {
+	for _, r := range label {
+		if !(r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z') {
+			return fmt.Sprintf("character %q not allowed", r)
+		}
+	}
+	return checkHyphens(label)
+}
+
+// checkULabel checks a Unicode label against the IDNA code point rules,
+// simplified to letters, digits, combining marks and hyphens
+func checkULabel(label string) string {
+	for i, r := range label {
+		switch {
+		case unicode.Is(unicode.M, r):
+			if i == 0 {
+				return "label starts with a combining mark"
+			}
+		case r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r):
+		default:
+			return fmt.Sprintf("character %U not allowed", r)
+		}
+	}
+	return checkHyphens(label)
+}
+
+// checkHyphens rejects leading and trailing hyphens, and hyphens in the
+// third and fourth position, which are reserved for encodings such as xn--
+func checkHyphens(label string) string {
+	switch {
+	case strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-"):
+		return "label starts or ends with a hyphen"
+	case len(label) >= 4 && label[2:4] == "--":
+		return "hyphens in the third and fourth position"
+	}
+	return ""
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// transportAddress returns the form of email sent to the API: international
+// addresses get an ASCII domain and an NFC local part, ASCII addresses are
+// sent as given. addr is nil for ASCII addresses.
+func transportAddress(email string) (transport string, addr *Address, err error) {
+	if isASCII(email) {
+		return email, nil, nil
+	}
+	addr, err = ParseAddress(email)
+	if err != nil {
+		return "", nil, err
+	}
+	return addr.ASCII(), addr, nil
+}
+
+// invalidAddressResult is the result for an international address that
+// fails ParseAddress, reported without asking the API
+func invalidAddressResult(email string) *VerifyResponse {
+	return &VerifyResponse{
+		Email:  email,
+		Result: "undeliverable",
+		Reason: ReasonInvalidSyntax,
+		Source: SourceLocal,
+	}
+}
+
+// localizeResult reports a result for a transport address under the
+// address it was converted from
+func localizeResult(result *VerifyResponse, email string, addr *Address) {
+	if addr == nil || result == nil {
+		return
+	}
+	result.Email = email
+	result.Domain = addr.Domain
+	result.SMTPUTF8 = addr.SMTPUTF8
+}
+
+// transportAddresses converts the international addresses of a batch to
+// their transport form, recording each conversion in aliases. Addresses that
+// fail ParseAddress are left out, as Verify does not send them either.
+func transportAddresses(emails []string, aliases map[string]string) ([]string, map[string]string) {
+	submitted := make([]string, 0, len(emails))
+	for _, email := range emails {
+		transport, _, err := transportAddress(email)
+		switch {
+		case err != nil:
+			continue
+		case transport == email:
+			submitted = append(submitted, email)
+			continue
+		}
+		submitted = append(submitted, transport)
+
+		if aliases == nil {
+			aliases = make(map[string]string)
+		}
+		aliases[email] = transport
+		for input, kept := range aliases {
+			switch {
+			case kept == email && input == transport:
+				// A duplicate already in transport form is submitted as given
+				delete(aliases, input)
+			case kept == email:
+				aliases[input] = transport
+			}
+		}
+	}
+	return submitted, aliases
+}
+
+// NormalizeNFC returns s in Unicode normalization form C, so that canonically
+// equivalent spellings, such as "ü" as one code point or as "u" and a
+// combining diaeresis, compare equal
+func NormalizeNFC(s string) string {
+	if isASCII(s) {
+		return s
+	}
+	return string(composeNFC(decomposeNFD([]rune(s))))
+}
+
+// Hangul syllables are composed algorithmically (Unicode §3.12)
+const (
+	hangulSBase  = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulLCount = 19
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulSCount = hangulLCount * hangulNCount
+)
+
+// decomposeNFD fully decomposes runes and puts combining marks in canonical order
+func decomposeNFD(runes []rune) []rune {
+	out := make([]rune, 0, len(runes)+len(runes)/2)
+	for _, r := range runes {
+		if s := r - hangulSBase; s >= 0 && s < hangulSCount {
+			out = append(out, hangulLBase+s/hangulNCount, hangulVBase+s%hangulNCount/hangulTCount)
+			if t := s % hangulTCount; t != 0 {
+				out = append(out, hangulTBase+t)
+			}
+			continue
+		}
+		if d, ok := canonicalDecomposition[r]; ok {
+			out = append(out, []rune(d)...)
+			continue
+		}
+		out = append(out, r)
+	}
+
+	// Stable sort of each run of non-starters by combining class
+	for i := 1; i < len(out); i++ {
+		ccc := combiningClass[out[i]]
+		if ccc == 0 {
+			continue
+		}
+		for j := i; j > 0; j-- {
+			prev := combiningClass[out[j-1]]
+			if prev == 0 || prev <= ccc {
+				break
+			}
+			out[j-1], out[j] = out[j], out[j-1]
+		}
+	}
+	return out
+}
+
+// composeNFC canonically composes decomposed runes in place
+func composeNFC(runes []rune) []rune {
+	out := runes[:0]
+	starter := -1
+	var lastClass uint8
+	for _, r := range runes {
+		ccc := combiningClass[r]
+		if starter >= 0 && (len(out)-1 == starter || lastClass < ccc) {
+			if composite, ok := composePair(out[starter], r); ok {
+				out[starter] = composite
+				continue
+			}
+		}
+		if ccc == 0 {
+			starter = len(out)
+		}
+		out = append(out, r)
+		lastClass = ccc
+	}
+	return out
+}
+
+// composePair returns the primary composite of a starter and the following rune
+func composePair(a, b rune) (rune, bool) {
+	if l, v := a-hangulLBase, b-hangulVBase; l >= 0 && l < hangulLCount && v >= 0 && v < hangulVCount {
+		return hangulSBase + (l*hangulVCount+v)*hangulTCount, true
+	}
+	if s, t := a-hangulSBase, b-hangulTBase; s >= 0 && s < hangulSCount && s%hangulTCount == 0 && t > 0 && t < hangulTCount {
+		return a + t, true
+	}
+	composite, ok := canonicalComposition[[2]rune{a, b}]
+	return composite, ok
+}
+
+// Punycode parameters (RFC 3492 §5)
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+)
+
+var errPunycode = errors.New("invalid punycode")
+
+// punycodeEncode encodes a label with punycode, without the xn-- prefix
+func punycodeEncode(input []rune) string {
+	var out strings.Builder
+	for _, r := range input {
+		if r < punyInitialN {
+			out.WriteRune(r)
+		}
+	}
+	basic := out.Len()
+	handled := basic
+	if basic > 0 {
+		out.WriteByte('-')
+	}
+
+	n, delta, bias := rune(punyInitialN), 0, punyInitialBias
+	for handled < len(input) {
+		m := rune(utf8.MaxRune)
+		for _, r := range input {
+			if r >= n && r < m {
+				m = r
+			}
+		}
+		delta += int(m-n) * (handled + 1)
+		n = m
+		for _, r := range input {
+			if r < n {
+				delta++
+			}
+			if r != n {
+				continue
+			}
+			q := delta
+			for k := punyBase; ; k += punyBase {
+				t := punyThreshold(k, bias)
+				if q < t {
+					break
+				}
+				out.WriteByte(punyDigit(t + (q-t)%(punyBase-t)))
+				q = (q - t) / (punyBase - t)
+			}
+			out.WriteByte(punyDigit(q))
+			bias = punyAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+		delta++
+		n++
+	}
+	return out.String()
+}
+
+// punycodeDecode decodes a punycode label without the xn-- prefix
+func punycodeDecode(s string) ([]rune, error) {
+	var out []rune
+	pos := 0
+	if b := strings.LastIndex(s, "-"); b >= 0 {
+		for _, r := range s[:b] {
+			if r >= punyInitialN {
+				return nil, errPunycode
+			}
+			out = append(out, r)
+		}
+		pos = b + 1
+	}
+
+	n, i, bias := rune(punyInitialN), 0, punyInitialBias
+	for pos < len(s) {
+		oldi, w := i, 1
+		for k := punyBase; ; k += punyBase {
+			if pos >= len(s) {
+				return nil, errPunycode
+			}
+			digit, ok := punyDigitValue(s[pos])
+			pos++
+			if !ok || digit > (1<<30-i)/w {
+				return nil, errPunycode
+			}
+			i += digit * w
+			t := punyThreshold(k, bias)
+			if digit < t {
+				break
+			}
+			w *= punyBase - t
+		}
+		bias = punyAdapt(i-oldi, len(out)+1, oldi == 0)
+		n += rune(i / (len(out) + 1))
+		i %= len(out) + 1
+		if n > utf8.MaxRune || n >= 0xD800 && n <= 0xDFFF {
+			return nil, errPunycode
+		}
+		out = append(out, 0)
+		copy(out[i+1:], out[i:])
+		out[i] = n
+		i++
+	}
+	return out, nil
+}
+
+func punyThreshold(k, bias int) int {
+	switch {
+	case k <= bias+punyTMin:
+		return punyTMin
+	case k >= bias+punyTMax:
+		return punyTMax
+	}
+	return k - bias
+}
+
+func punyAdapt(delta, points int, first bool) int {
+	if first {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / points
+	k := 0
+	for delta > (punyBase-punyTMin)*punyTMax/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+	return k + (punyBase-punyTMin+1)*delta/(delta+punySkew)
+}
+
+func punyDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
+
+func punyDigitValue(c byte) (int, bool) {
+	switch {
+	case c >= 'a' && c <= 'z':
+		return int(c - 'a'), true
+	case c >= 'A' && c <= 'Z':
+		return int(c - 'A'), true
+	case c >= '0' && c <= '9':
+		return int(c-'0') + 26, true
+	}
+	return 0, false
+}
@@ -0,0 +1,130 @@
+package emaillistchecker
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPunycode(t *testing.T) {
+	// Vectors from RFC 3492 §7.1 and common IDNs
+	tests := []struct {
+		unicode  string
+		punycode string
+	}{
+		{"bücher", "bcher-kva"},
+		{"münchen", "mnchen-3ya"},
+		{"例子", "fsqu00a"},
+		{"测试", "0zwm56d"},
+		{"他们为什么不说中文", "ihqwcrb4cv8a8dqg056pqjye"},
+		{"3年B組金八先生", "3B-ww4c5e180e575a65lsy2b"},
+	}
+	for _, tt := range tests {
+		if got := punycodeEncode([]rune(tt.unicode)); got != tt.punycode {
+			t.Errorf("punycodeEncode(%q) = %q, want %q", tt.unicode, got, tt.punycode)
+		}
+		got, err := punycodeDecode(tt.punycode)
+		if err != nil || string(got) != tt.unicode {
+			t.Errorf("punycodeDecode(%q) = %q, %v, want %q", tt.punycode, string(got), err, tt.unicode)
+		}
+	}
+}
+
+func TestDomainToASCII(t *testing.T) {
+	tests := []struct {
+		domain string
+		ascii  string
+	}{
+		{"bücher.de", "xn--bcher-kva.de"},
+		{"Bücher.DE", "xn--bcher-kva.de"},
+		{"bu\u0308cher.de", "xn--bcher-kva.de"},
+		{"例子。广告", "xn--fsqu00a.xn--4rr70v"},
+		{"xn--bcher-kva.de", "xn--bcher-kva.de"},
+		{"example.com", "example.com"},
+	}
+	for _, tt := range tests {
+		if got, err := DomainToASCII(tt.domain); err != nil || got != tt.ascii {
+			t.Errorf("DomainToASCII(%q) = %q, %v, want %q", tt.domain, got, err, tt.ascii)
+		}
+	}
+
+	for _, domain := range []string{"bü cher.de", "-bücher.de", "xn--bcher.de", "ab--c.de", "bücher..de"} {
+		if got, err := DomainToASCII(domain); err == nil {
+			t.Errorf("DomainToASCII(%q) = %q, want an error", domain, got)
+		}
+	}
+}
+
+func TestNormalizeNFC(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"ascii", "ascii"},
+		{"u\u0308", "ü"},
+		{"ü", "ü"},
+		// Singleton decompositions are not recomposed
+		{"\u212b", "\u00c5"},
+		// Combining marks are reordered by combining class before composing
+		{"\u1e0b\u0323", "\u1e0d\u0307"},
+		{"d\u0307\u0323", "\u1e0d\u0307"},
+		// Hangul syllables are composed algorithmically
+		{"\u1100\u1161", "\uac00"},
+		{"\u1100\u1161\u11a8", "\uac01"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeNFC(tt.input); got != tt.want {
+			t.Errorf("NormalizeNFC(%+q) = %+q, want %+q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		email    string
+		unicode  string
+		ascii    string
+		smtpUTF8 bool
+	}{
+		{"jane@example.com", "jane@example.com", "jane@example.com", false},
+		{"Jörg@Bücher.de", "Jörg@bücher.de", "Jörg@xn--bcher-kva.de", true},
+		{"jo\u0308rg@bücher.de", "jörg@bücher.de", "jörg@xn--bcher-kva.de", true},
+		{"用户@例子.广告", "用户@例子.广告", "用户@xn--fsqu00a.xn--4rr70v", true},
+		{`"john doe"@example.com`, `"john doe"@example.com`, `"john doe"@example.com`, false},
+		{`"a\"b@c"@example.com`, `"a\"b@c"@example.com`, `"a\"b@c"@example.com`, false},
+		{`"jörg smith"@bücher.de`, `"jörg smith"@bücher.de`, `"jörg smith"@xn--bcher-kva.de`, true},
+	}
+	for _, tt := range tests {
+		addr, err := ParseAddress(tt.email)
+		if err != nil {
+			t.Errorf("ParseAddress(%q): %v", tt.email, err)
+			continue
+		}
+		if addr.String() != tt.unicode || addr.ASCII() != tt.ascii || addr.SMTPUTF8 != tt.smtpUTF8 {
+			t.Errorf("ParseAddress(%q) = %s, %s, SMTPUTF8 %v, want %s, %s, %v",
+				tt.email, addr, addr.ASCII(), addr.SMTPUTF8, tt.unicode, tt.ascii, tt.smtpUTF8)
+		}
+	}
+
+	for _, email := range []string{
+		"",
+		"jane",
+		"@example.com",
+		"jane@",
+		"jane@localhost",
+		".jane@example.com",
+		"ja..ne@example.com",
+		"ja ne@example.com",
+		"j\u200bane@example.com",
+		`"jane@example.com`,
+		`"ja"ne"@example.com`,
+		`"jane\"@example.com`,
+		"jane@[192.0.2.1]",
+		"jane@bü_cher.de",
+	} {
+		addr, err := ParseAddress(email)
+		var addrErr *AddressError
+		if !errors.As(err, &addrErr) {
+			t.Errorf("ParseAddress(%q) = %v, %v, want an *AddressError", email, addr, err)
+		}
+	}
+}
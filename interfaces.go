@@ -0,0 +1,53 @@
+package emaillistchecker
+
+import (
+	"context"
+	"io"
+)
+
+// Verifier verifies single email addresses
+type Verifier interface {
+	Verify(email string, timeout *int, smtpCheck bool) (*VerifyResponse, error)
+	VerifyWithOptions(email string, opts VerifyOptions) (*VerifyResponse, error)
+}
+
+// BatchVerifier submits and tracks batch verifications
+type BatchVerifier interface {
+	VerifyBatch(emails []string, name, callbackURL string, autoStart bool) (*BatchResponse, error)
+	VerifyBatchFile(filePath string, name, callbackURL *string, autoStart bool) (*BatchResponse, error)
+	GetBatchStatus(batchID int) (*BatchStatusResponse, error)
+	GetBatchResults(batchID int, format, filter string) (interface{}, error)
+	GetBatchVerifyResults(batch *BatchResponse, filter string) ([]VerifyResponse, error)
+}
+
+// Finder discovers email addresses
+type Finder interface {
+	FindEmail(firstName, lastName, domain string) (*FinderResult, error)
+	FindByDomain(domain string, limit, offset int) (map[string]interface{}, error)
+	FindByCompany(company string, limit int) (map[string]interface{}, error)
+}
+
+// AccountService reports credits and usage
+type AccountService interface {
+	GetCredits() (map[string]interface{}, error)
+	GetUsage(ctx context.Context, q UsageQuery) (*UsageReport, error)
+}
+
+// ListService manages verification lists
+type ListService interface {
+	GetLists() ([]List, error)
+	GetList(listID int) (*List, error)
+	RenameList(listID int, name string) (*List, error)
+	DownloadList(listID int, format, filter string, w io.Writer) error
+	DeleteList(listID int) error
+	DeleteLists(listIDs []int, force bool) error
+}
+
+// Compile-time checks that *Client implements every service interface
+var (
+	_ Verifier       = (*Client)(nil)
+	_ BatchVerifier  = (*Client)(nil)
+	_ Finder         = (*Client)(nil)
+	_ AccountService = (*Client)(nil)
+	_ ListService    = (*Client)(nil)
+)
@@ -0,0 +1,197 @@
+package emaillistchecker
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxConcurrentListOps caps the number of in-flight requests made by DeleteLists
+const maxConcurrentListOps = 5
+
+// List represents a verification list
+type List struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Status        string `json:"status"`
+	Progress      int    `json:"progress"`
+	TotalEmails   int    `json:"total_emails"`
+	ValidEmails   int    `json:"valid_emails"`
+	InvalidEmails int    `json:"invalid_emails"`
+	UnknownEmails int    `json:"unknown_emails"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+// IsTerminal reports whether the list has finished processing
+func (l *List) IsTerminal() bool {
+	switch l.Status {
+	case "completed", "failed", "cancelled":
+		return true
+	}
+	return false
+}
+
+// CreatedTime parses CreatedAt into a time.Time
+func (l *List) CreatedTime() (time.Time, error) {
+	return parseTimestamp(l.CreatedAt)
+}
+
+// GetList gets a single verification list
+func (c *Client) GetList(listID int) (*List, error) {
+	var result struct {
+		Data *List `json:"data"`
+	}
+
+	endpoint := fmt.Sprintf("/lists/%d", listID)
+	err := c.request("GetList", "GET", endpoint, nil, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Data == nil {
+		return nil, fmt.Errorf("list %d: empty response", listID)
+	}
+
+	return result.Data, nil
+}
+
+// RenameList changes the name of a verification list
+func (c *Client) RenameList(listID int, name string) (*List, error) {
+	req := map[string]interface{}{
+		"name": name,
+	}
+
+	var result struct {
+		Data *List `json:"data"`
+	}
+
+	endpoint := fmt.Sprintf("/lists/%d", listID)
+	err := c.request("RenameList", "PUT", endpoint, req, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Data == nil {
+		return nil, fmt.Errorf("list %d: empty response", listID)
+	}
+
+	return result.Data, nil
+}
+
+// DownloadList streams the results of a verification list to w.
+// Format is one of "csv", "json" or "xlsx"; filter is one of "all", "valid", "invalid" or "unknown".
+func (c *Client) DownloadList(listID int, format, filter string, w io.Writer) error {
+	query := url.Values{}
+	if format != "" {
+		query.Set("format", format)
+	}
+	if filter != "" {
+		query.Set("filter", filter)
+	}
+
+	endpoint := fmt.Sprintf("/lists/%d/download", listID)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	return c.download("DownloadList", endpoint, w)
+}
+
+// DeleteLists deletes several verification lists concurrently; repeated IDs
+// are deleted once. Lists that are still being processed are skipped with a
+// ListInProgressError unless force is set.
+// Failures are collected into a ListErrors keyed by list ID.
+func (c *Client) DeleteLists(listIDs []int, force bool) error {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = ListErrors{}
+		sem  = make(chan struct{}, maxConcurrentListOps)
+		seen = make(map[int]bool, len(listIDs))
+	)
+
+	for _, id := range listIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(id int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := c.deleteListChecked(id, force); err != nil {
+				mu.Lock()
+				errs[id] = err
+				mu.Unlock()
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// deleteListChecked deletes a list, refusing lists in a non-terminal state unless force is set
+func (c *Client) deleteListChecked(listID int, force bool) error {
+	if !force {
+		list, err := c.GetList(listID)
+		if err != nil {
+			return err
+		}
+		if !list.IsTerminal() {
+			return &ListInProgressError{ListID: listID, Status: list.Status}
+		}
+	}
+
+	return c.DeleteList(listID)
+}
+
+// ListsOlderThan returns the lists created before the given age, oldest first
+func ListsOlderThan(lists []List, age time.Duration, now time.Time) []List {
+	cutoff := now.Add(-age)
+
+	var old []List
+	for _, list := range lists {
+		created, err := list.CreatedTime()
+		if err != nil || !created.Before(cutoff) {
+			continue
+		}
+		old = append(old, list)
+	}
+
+	sort.SliceStable(old, func(i, j int) bool {
+		a, _ := old[i].CreatedTime()
+		b, _ := old[j].CreatedTime()
+		return a.Before(b)
+	})
+
+	return old
+}
+
+// parseTimestamp parses the timestamp formats returned by the API
+func parseTimestamp(value string) (time.Time, error) {
+	layouts := []string{
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+	}
+
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", value)
+}
@@ -0,0 +1,49 @@
+package emaillistchecker_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+func TestDeleteLists(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	done, err := client.VerifyBatch([]string{"a@example.com"}, "done", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Advance(time.Hour)
+	running, err := client.VerifyBatch([]string{"b@example.com"}, "running", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A repeated ID is deleted once instead of failing the second time
+	err = client.DeleteLists([]int{done.ID, running.ID, done.ID}, false)
+	var listErrs emaillistchecker.ListErrors
+	if !errors.As(err, &listErrs) || len(listErrs) != 1 {
+		t.Fatalf("DeleteLists = %v, want one ListErrors entry", err)
+	}
+	var inProgress *emaillistchecker.ListInProgressError
+	if !errors.As(listErrs[running.ID], &inProgress) || inProgress.Status != "processing" {
+		t.Errorf("error for the running list = %v, want *ListInProgressError", listErrs[running.ID])
+	}
+
+	lists, err := client.GetLists()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lists) != 1 || lists[0].ID != running.ID {
+		t.Errorf("lists after DeleteLists = %+v, want only the running list", lists)
+	}
+
+	if err := client.DeleteLists([]int{running.ID, running.ID}, true); err != nil {
+		t.Errorf("DeleteLists with force = %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+package emaillistchecker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxLoggedBody caps how much of a body is included in a RequestLog
+const maxLoggedBody = 4096
+
+// emailInText matches email addresses in bodies and, percent-encoded, in query
+// strings. The local part may be any run of Unicode characters other than
+// spaces, RFC 5322 specials, quotes and URL query delimiters, so international
+// (SMTPUTF8) addresses are matched too; the domain may be an IDN.
+var emailInText = regexp.MustCompile(`[^\s"'<>()\[\]{},;:\\@&=?/]+(?:@|%40)[\p{L}\p{N}\p{M}%.\-]+\.(?:[\p{L}\p{M}]{2,}|xn--[a-z0-9\-]+)`)
+
+// RequestLog describes one HTTP request made by the client
+type RequestLog struct {
+	Method string
+	// Operation is the client method that made the request, for example "Verify"
+	Operation string
+	// Endpoint is the path and query relative to the base URL, with emails masked
+	Endpoint string
+	// Status is zero when no response was received
+	Status   int
+	Duration time.Duration
+	// Attempt counts from 1 and increases when a request is retried
+	Attempt int
+	// RequestID is the X-Request-Id returned by the API, if any
+	RequestID     string
+	RequestBytes  int64
+	ResponseBytes int64
+	// Headers are the request headers with the API key redacted
+	Headers http.Header
+	// RequestBody and ResponseBody are only set with LogOptions.IncludeBodies
+	RequestBody  string
+	ResponseBody string
+	Err          error
+	// Context is the request's context, so loggers can pick up request-scoped
+	// values such as a trace
+	Context context.Context
+}
+
+// Logger receives one record per HTTP request. Implementations must be safe for concurrent use.
+type Logger interface {
+	LogRequest(entry RequestLog)
+}
+
+// LoggerFunc adapts a function to the Logger interface
+type LoggerFunc func(entry RequestLog)
+
+// LogRequest calls f(entry)
+func (f LoggerFunc) LogRequest(entry RequestLog) {
+	f(entry)
+}
+
+// LogOptions controls what request logs contain
+type LogOptions struct {
+	// IncludeBodies adds JSON request and response bodies (truncated to 4 KiB)
+	IncludeBodies bool
+	// KeepEmails disables masking of email addresses
+	KeepEmails bool
+	// MaskEmail overrides how addresses are masked. The default keeps the domain
+	// and replaces the local part with a short hash, so records can still be correlated.
+	MaskEmail func(email string) string
+}
+
+// SetLogger sends a RequestLog for every request attempt to logger. Pass nil to disable logging.
+func (c *Client) SetLogger(logger Logger, opts LogOptions) {
+	c.logger = logger
+	c.logOptions = opts
+}
+
+// NewStdLogger returns a Logger that writes one key=value line per request to l
+func NewStdLogger(l *log.Logger) Logger {
+	return LoggerFunc(func(entry RequestLog) {
+		var b strings.Builder
+		fmt.Fprintf(&b, "emaillistchecker operation=%s method=%s endpoint=%q status=%d duration=%s attempt=%d",
+			entry.Operation, entry.Method, entry.Endpoint, entry.Status, entry.Duration, entry.Attempt)
+		if entry.RequestID != "" {
+			fmt.Fprintf(&b, " request_id=%s", entry.RequestID)
+		}
+		fmt.Fprintf(&b, " request_bytes=%d response_bytes=%d", entry.RequestBytes, entry.ResponseBytes)
+		if entry.Err != nil {
+			fmt.Fprintf(&b, " error=%q", entry.Err.Error())
+		}
+		if entry.RequestBody != "" {
+			fmt.Fprintf(&b, " request_body=%q", entry.RequestBody)
+		}
+		if entry.ResponseBody != "" {
+			fmt.Fprintf(&b, " response_body=%q", entry.ResponseBody)
+		}
+		l.Print(b.String())
+	})
+}
+
+// LoggingMiddleware logs every request that passes through it to logger.
+// SetLogger installs it inside the client's retries, so each attempt is logged.
+func LoggingMiddleware(logger Logger, opts LogOptions) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			entry := RequestLog{
+				Method:       req.Method,
+				Operation:    OperationName(req.Context()),
+				Endpoint:     opts.mask(endpointFromContext(req.Context())),
+				Attempt:      attemptFromContext(req.Context()),
+				RequestBytes: req.ContentLength,
+				Headers:      redactHeaders(req.Header),
+				Context:      req.Context(),
+			}
+			if entry.Endpoint == "" {
+				entry.Endpoint = opts.mask(req.URL.String())
+			}
+			if opts.IncludeBodies && isJSON(req.Header) && req.GetBody != nil {
+				if body, err := req.GetBody(); err == nil {
+					data, _ := io.ReadAll(io.LimitReader(body, maxLoggedBody))
+					body.Close()
+					entry.RequestBody = opts.mask(string(data))
+				}
+			}
+
+			start := time.Now()
+			resp, err := next.Do(req)
+			if err != nil {
+				entry.Duration = time.Since(start)
+				entry.Err = &maskedError{err: err, msg: opts.mask(err.Error())}
+				logger.LogRequest(entry)
+				return nil, err
+			}
+
+			entry.Status = resp.StatusCode
+			entry.RequestID = resp.Header.Get("X-Request-Id")
+			resp.Body = &loggedBody{
+				ReadCloser: resp.Body,
+				logger:     logger,
+				opts:       opts,
+				entry:      entry,
+				start:      start,
+				capture:    opts.IncludeBodies && isJSON(resp.Header),
+			}
+			return resp, nil
+		})
+	}
+}
+
+// loggedBody counts the bytes read from a response and logs the request once
+// the body has been read to the end, fails or is closed, whichever comes first
+type loggedBody struct {
+	io.ReadCloser
+	logger  Logger
+	opts    LogOptions
+	entry   RequestLog
+	start   time.Time
+	capture bool
+	body    strings.Builder
+	once    sync.Once
+}
+
+func (b *loggedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.entry.ResponseBytes += int64(n)
+	if b.capture && b.body.Len() < maxLoggedBody {
+		rest := maxLoggedBody - b.body.Len()
+		if rest > n {
+			rest = n
+		}
+		b.body.Write(p[:rest])
+	}
+	if err != nil {
+		if err != io.EOF {
+			b.entry.Err = &maskedError{err: err, msg: b.opts.mask(err.Error())}
+		}
+		b.log()
+	}
+	return n, err
+}
+
+func (b *loggedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.log()
+	return err
+}
+
+// log records the request the first time it is called
+func (b *loggedBody) log() {
+	b.once.Do(func() {
+		b.entry.Duration = time.Since(b.start)
+		if b.capture {
+			b.entry.ResponseBody = b.opts.mask(b.body.String())
+		}
+		b.logger.LogRequest(b.entry)
+	})
+}
+
+// maskedError is a logged error whose message has emails masked
+type maskedError struct {
+	err error
+	msg string
+}
+
+func (e *maskedError) Error() string { return e.msg }
+
+func (e *maskedError) Unwrap() error { return e.err }
+
+// mask replaces email addresses in s unless KeepEmails is set
+func (o LogOptions) mask(s string) string {
+	if o.KeepEmails {
+		return s
+	}
+	replace := o.MaskEmail
+	if replace == nil {
+		replace = MaskEmail
+	}
+	return MaskEmailsFunc(s, replace)
+}
+
+// MaskEmails replaces every email address in s, including percent-encoded
+// ones in query strings, with MaskEmail
+func MaskEmails(s string) string {
+	return MaskEmailsFunc(s, MaskEmail)
+}
+
+// MaskEmailsFunc replaces every email address in s with replace(address), as
+// matched by MaskEmails
+func MaskEmailsFunc(s string, replace func(email string) string) string {
+	return emailInText.ReplaceAllStringFunc(s, replace)
+}
+
+// MaskEmail keeps the domain of an address and replaces the local part with a
+// short stable hash, so masked records can still be correlated
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		at = strings.LastIndex(email, "%40")
+	}
+	if at < 0 {
+		return email
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(email[:at])))
+	return "user-" + hex.EncodeToString(sum[:4]) + email[at:]
+}
+
+// redactHeaders copies headers, replacing the API key
+func redactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	if out.Get("Authorization") != "" {
+		out.Set("Authorization", "Bearer REDACTED")
+	}
+	return out
+}
+
+// isJSON reports whether headers declare a JSON body
+func isJSON(h http.Header) bool {
+	return strings.HasPrefix(h.Get("Content-Type"), "application/json")
+}
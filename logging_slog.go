@@ -0,0 +1,49 @@
+//go:build go1.21
+
+package emaillistchecker
+
+import (
+	"context"
+	"log/slog"
+)
+
+// NewSlogLogger returns a Logger that emits one structured record per request to l.
+// Successful requests are logged at Info, failures and error statuses at Warn.
+// Records carry the request's context, so handlers can read values from it.
+func NewSlogLogger(l *slog.Logger) Logger {
+	return LoggerFunc(func(entry RequestLog) {
+		level := slog.LevelInfo
+		if entry.Err != nil || entry.Status >= 400 {
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("operation", entry.Operation),
+			slog.String("method", entry.Method),
+			slog.String("endpoint", entry.Endpoint),
+			slog.Int("status", entry.Status),
+			slog.Duration("duration", entry.Duration),
+			slog.Int("attempt", entry.Attempt),
+			slog.Int64("request_bytes", entry.RequestBytes),
+			slog.Int64("response_bytes", entry.ResponseBytes),
+		}
+		if entry.RequestID != "" {
+			attrs = append(attrs, slog.String("request_id", entry.RequestID))
+		}
+		if entry.Err != nil {
+			attrs = append(attrs, slog.String("error", entry.Err.Error()))
+		}
+		if entry.RequestBody != "" {
+			attrs = append(attrs, slog.String("request_body", entry.RequestBody))
+		}
+		if entry.ResponseBody != "" {
+			attrs = append(attrs, slog.String("response_body", entry.ResponseBody))
+		}
+
+		ctx := entry.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		l.LogAttrs(ctx, level, "emaillistchecker request", attrs...)
+	})
+}
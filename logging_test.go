@@ -0,0 +1,113 @@
+package emaillistchecker_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+// logCollector is a Logger that keeps every entry
+type logCollector struct {
+	mu      sync.Mutex
+	entries []emaillistchecker.RequestLog
+}
+
+func (c *logCollector) LogRequest(entry emaillistchecker.RequestLog) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = append(c.entries, entry)
+}
+
+func (c *logCollector) logged() []emaillistchecker.RequestLog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]emaillistchecker.RequestLog(nil), c.entries...)
+}
+
+type ctxKey struct{}
+
+func TestLoggerGetsRequestContext(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	logs := &logCollector{}
+	client := srv.Client()
+	client.SetLogger(logs, emaillistchecker.LogOptions{IncludeBodies: true})
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "trace-1")
+	if _, err := client.WithContext(ctx).Verify("jane@example.com", nil, true); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	entries := logs.logged()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+	entry := entries[0]
+	if entry.Context == nil || entry.Context.Value(ctxKey{}) != "trace-1" {
+		t.Error("entry.Context does not carry the request's context values")
+	}
+	if strings.Contains(entry.RequestBody, "jane@") || strings.Contains(entry.ResponseBody, "jane@") {
+		t.Errorf("logged bodies contain the address: %q, %q", entry.RequestBody, entry.ResponseBody)
+	}
+}
+
+// failingBody returns part of a response and then an error naming an address
+type failingBody struct {
+	io.Reader
+}
+
+func (b failingBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection to jane@example.com lost")
+	}
+	return n, err
+}
+
+func (failingBody) Close() error { return nil }
+
+func TestLoggingMiddlewareLogsWithoutClose(t *testing.T) {
+	tests := []struct {
+		name string
+		body io.ReadCloser
+		err  string
+	}{
+		{"EOF", io.NopCloser(strings.NewReader(`{"success":true}`)), ""},
+		{"read error", failingBody{strings.NewReader(`{"success":`)}, "connection to user-"},
+	}
+	for _, tt := range tests {
+		logs := &logCollector{}
+		doer := emaillistchecker.LoggingMiddleware(logs, emaillistchecker.LogOptions{})(
+			emaillistchecker.DoerFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: 200, Header: http.Header{}, Body: tt.body}, nil
+			}))
+
+		req, _ := http.NewRequest("GET", "http://api.test/credits", nil)
+		resp, err := doer.Do(req)
+		if err != nil {
+			t.Fatalf("%s: Do: %v", tt.name, err)
+		}
+		io.ReadAll(resp.Body)
+
+		// Read to the end but not closed
+		entries := logs.logged()
+		if len(entries) != 1 {
+			t.Fatalf("%s: logged %d entries before Close, want 1", tt.name, len(entries))
+		}
+		if tt.err == "" && entries[0].Err != nil || tt.err != "" && (entries[0].Err == nil || !strings.HasPrefix(entries[0].Err.Error(), tt.err)) {
+			t.Errorf("%s: Err = %v, want %q", tt.name, entries[0].Err, tt.err)
+		}
+
+		resp.Body.Close()
+		if n := len(logs.logged()); n != 1 {
+			t.Errorf("%s: logged %d entries after Close, want 1", tt.name, n)
+		}
+	}
+}
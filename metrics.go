@@ -0,0 +1,134 @@
+package emaillistchecker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Metrics receives measurements from the client. Implementations must be safe
+// for concurrent use; the metrics subpackage provides one with Prometheus output.
+type Metrics interface {
+	// ObserveRequest records one HTTP attempt. statusClass is "2xx" to "5xx", or
+	// "error" when no response arrived; errorType is empty for successful requests.
+	ObserveRequest(operation, statusClass, errorType string, duration time.Duration)
+	// AddCredits records credits spent by an operation, estimated at one per verified address or finder lookup
+	AddCredits(operation string, credits int)
+	// ObserveCache records a verification cache lookup
+	ObserveCache(hit bool)
+}
+
+// Error types reported to Metrics.ObserveRequest
+const (
+	ErrorTypeAuthentication      = "authentication"
+	ErrorTypeInsufficientCredits = "insufficient_credits"
+	ErrorTypeValidation          = "validation"
+	ErrorTypeRateLimit           = "rate_limit"
+	ErrorTypeClient              = "client"
+	ErrorTypeServer              = "server"
+	ErrorTypeTimeout             = "timeout"
+	ErrorTypeNetwork             = "network"
+)
+
+// SetMetrics reports requests, estimated credit spend and cache lookups to m.
+// Pass nil to disable metrics.
+func (c *Client) SetMetrics(m Metrics) {
+	c.metrics = m
+}
+
+// MetricsMiddleware reports every request that passes through it to m.
+// SetMetrics installs it inside the client's retries, so each attempt is counted.
+func MetricsMiddleware(m Metrics) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			op := OperationName(req.Context())
+			start := time.Now()
+
+			resp, err := next.Do(req)
+			if err != nil {
+				m.ObserveRequest(op, "error", transportErrorType(err), time.Since(start))
+				return nil, err
+			}
+
+			resp.Body = &measuredBody{
+				ReadCloser: resp.Body,
+				observe: func() {
+					m.ObserveRequest(op, statusClass(resp.StatusCode), statusErrorType(resp.StatusCode), time.Since(start))
+				},
+			}
+			return resp, nil
+		})
+	}
+}
+
+// measuredBody reports a request once its response body is closed
+type measuredBody struct {
+	io.ReadCloser
+	observe func()
+	once    sync.Once
+}
+
+func (b *measuredBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.observe)
+	return err
+}
+
+// spendCredits reports credits spent by a successful operation
+func (c *Client) spendCredits(op string, credits int) {
+	if credits <= 0 {
+		return
+	}
+	if c.metrics != nil {
+		c.metrics.AddCredits(op, credits)
+	}
+	if c.attribution != nil {
+		c.attribution.add(TagFromContext(c.context()), 0, 0, credits)
+	}
+}
+
+// observeCache reports a cache lookup
+func (c *Client) observeCache(hit bool) {
+	if c.metrics != nil {
+		c.metrics.ObserveCache(hit)
+	}
+}
+
+// statusClass groups status codes as "2xx", "4xx" and so on
+func statusClass(status int) string {
+	return strconv.Itoa(status/100) + "xx"
+}
+
+// statusErrorType classifies an HTTP status, returning "" for success
+func statusErrorType(status int) string {
+	switch {
+	case status < 400:
+		return ""
+	case status == http.StatusUnauthorized:
+		return ErrorTypeAuthentication
+	case status == http.StatusPaymentRequired:
+		return ErrorTypeInsufficientCredits
+	case status == http.StatusUnprocessableEntity:
+		return ErrorTypeValidation
+	case status == http.StatusTooManyRequests:
+		return ErrorTypeRateLimit
+	case status < 500:
+		return ErrorTypeClient
+	default:
+		return ErrorTypeServer
+	}
+}
+
+// transportErrorType classifies an error returned instead of a response
+func transportErrorType(err error) string {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrorTypeTimeout
+	}
+	return ErrorTypeNetwork
+}
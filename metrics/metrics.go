@@ -0,0 +1,226 @@
+// Package metrics provides an in-process registry for EmailListChecker client
+// metrics and renders it in the Prometheus text exposition format, without
+// depending on the Prometheus client library.
+//
+//	registry := metrics.NewRegistry()
+//	client.SetMetrics(registry)
+//	http.Handle("/metrics", registry)
+//
+// The exposed series are:
+//
+//	emaillistchecker_requests_total{operation,status_class,error_type}
+//	emaillistchecker_request_duration_seconds{operation} (histogram)
+//	emaillistchecker_credits_spent_total{operation}
+//	emaillistchecker_cache_lookups_total{result}
+//	emaillistchecker_cache_hit_ratio
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+)
+
+// DefaultBuckets are the latency histogram upper bounds in seconds
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Registry accumulates client metrics. It implements emaillistchecker.Metrics
+// and serves the Prometheus text format as an http.Handler.
+type Registry struct {
+	buckets []float64
+
+	mu        sync.Mutex
+	requests  map[requestKey]uint64
+	durations map[string]*histogram
+	credits   map[string]uint64
+	hits      uint64
+	misses    uint64
+}
+
+type requestKey struct {
+	operation   string
+	statusClass string
+	errorType   string
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// Compile-time check that *Registry implements emaillistchecker.Metrics
+var _ emaillistchecker.Metrics = (*Registry)(nil)
+
+// NewRegistry creates a registry using DefaultBuckets
+func NewRegistry() *Registry {
+	return NewRegistryWithBuckets(DefaultBuckets)
+}
+
+// NewRegistryWithBuckets creates a registry with custom latency bucket bounds in seconds.
+// The +Inf bucket is always exposed, so +Inf, NaN and repeated bounds are dropped.
+func NewRegistryWithBuckets(buckets []float64) *Registry {
+	sorted := make([]float64, 0, len(buckets))
+	for _, bound := range buckets {
+		if !math.IsInf(bound, 1) && !math.IsNaN(bound) {
+			sorted = append(sorted, bound)
+		}
+	}
+	sort.Float64s(sorted)
+	unique := sorted[:0]
+	for i, bound := range sorted {
+		if i == 0 || bound != sorted[i-1] {
+			unique = append(unique, bound)
+		}
+	}
+	return &Registry{
+		buckets:   unique,
+		requests:  make(map[requestKey]uint64),
+		durations: make(map[string]*histogram),
+		credits:   make(map[string]uint64),
+	}
+}
+
+// ObserveRequest implements emaillistchecker.Metrics
+func (r *Registry) ObserveRequest(operation, statusClass, errorType string, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests[requestKey{operation, statusClass, errorType}]++
+
+	h := r.durations[operation]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(r.buckets))}
+		r.durations[operation] = h
+	}
+	seconds := duration.Seconds()
+	for i, bound := range r.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// AddCredits implements emaillistchecker.Metrics
+func (r *Registry) AddCredits(operation string, credits int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.credits[operation] += uint64(credits)
+}
+
+// ObserveCache implements emaillistchecker.Metrics
+func (r *Registry) ObserveCache(hit bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if hit {
+		r.hits++
+	} else {
+		r.misses++
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteText(w)
+}
+
+// WriteText writes the metrics in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b := bufio.NewWriter(w)
+
+	header(b, "emaillistchecker_requests_total", "counter", "HTTP requests to the EmailListChecker API.")
+	requestKeys := make([]requestKey, 0, len(r.requests))
+	for k := range r.requests {
+		requestKeys = append(requestKeys, k)
+	}
+	sort.Slice(requestKeys, func(i, j int) bool {
+		x, y := requestKeys[i], requestKeys[j]
+		if x.operation != y.operation {
+			return x.operation < y.operation
+		}
+		if x.statusClass != y.statusClass {
+			return x.statusClass < y.statusClass
+		}
+		return x.errorType < y.errorType
+	})
+	for _, k := range requestKeys {
+		fmt.Fprintf(b, "emaillistchecker_requests_total{operation=%s,status_class=%s,error_type=%s} %d\n",
+			quote(k.operation), quote(k.statusClass), quote(k.errorType), r.requests[k])
+	}
+
+	header(b, "emaillistchecker_request_duration_seconds", "histogram", "Latency of EmailListChecker API requests.")
+	for _, op := range sortedKeys(r.durations) {
+		h := r.durations[op]
+		var cumulative uint64
+		for i, bound := range r.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(b, "emaillistchecker_request_duration_seconds_bucket{operation=%s,le=%s} %d\n",
+				quote(op), quote(formatFloat(bound)), cumulative)
+		}
+		fmt.Fprintf(b, "emaillistchecker_request_duration_seconds_bucket{operation=%s,le=\"+Inf\"} %d\n", quote(op), h.count)
+		fmt.Fprintf(b, "emaillistchecker_request_duration_seconds_sum{operation=%s} %s\n", quote(op), formatFloat(h.sum))
+		fmt.Fprintf(b, "emaillistchecker_request_duration_seconds_count{operation=%s} %d\n", quote(op), h.count)
+	}
+
+	header(b, "emaillistchecker_credits_spent_total", "counter", "Estimated credits spent, one per verified address or finder lookup.")
+	for _, op := range sortedKeys(r.credits) {
+		fmt.Fprintf(b, "emaillistchecker_credits_spent_total{operation=%s} %d\n", quote(op), r.credits[op])
+	}
+
+	header(b, "emaillistchecker_cache_lookups_total", "counter", "Verification cache lookups.")
+	fmt.Fprintf(b, "emaillistchecker_cache_lookups_total{result=\"hit\"} %d\n", r.hits)
+	fmt.Fprintf(b, "emaillistchecker_cache_lookups_total{result=\"miss\"} %d\n", r.misses)
+
+	header(b, "emaillistchecker_cache_hit_ratio", "gauge", "Share of verification cache lookups that were hits.")
+	ratio := 0.0
+	if total := r.hits + r.misses; total > 0 {
+		ratio = float64(r.hits) / float64(total)
+	}
+	fmt.Fprintf(b, "emaillistchecker_cache_hit_ratio %s\n", formatFloat(ratio))
+
+	return b.Flush()
+}
+
+func header(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// quote escapes a label value
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
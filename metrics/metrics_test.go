@@ -0,0 +1,80 @@
+package metrics_test
+
+import (
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Emaillistchecker-io/emaillistchecker-go/metrics"
+)
+
+func TestWriteText(t *testing.T) {
+	registry := metrics.NewRegistryWithBuckets([]float64{1, 0.1, math.Inf(1), 1})
+	registry.ObserveRequest("Verify", "2xx", "", 50*time.Millisecond)
+	registry.ObserveRequest("Verify", "2xx", "", 500*time.Millisecond)
+	registry.ObserveRequest("Verify", "", "timeout", 2*time.Second)
+	registry.ObserveRequest("GetCredits", "5xx", "server", 250*time.Millisecond)
+	registry.AddCredits("Verify", 2)
+	registry.AddCredits("VerifyBatch", 100)
+	registry.ObserveCache(true)
+	registry.ObserveCache(false)
+	registry.ObserveCache(false)
+	registry.ObserveCache(false)
+
+	want := `# HELP emaillistchecker_requests_total HTTP requests to the EmailListChecker API.
+# TYPE emaillistchecker_requests_total counter
+emaillistchecker_requests_total{operation="GetCredits",status_class="5xx",error_type="server"} 1
+emaillistchecker_requests_total{operation="Verify",status_class="",error_type="timeout"} 1
+emaillistchecker_requests_total{operation="Verify",status_class="2xx",error_type=""} 2
+# HELP emaillistchecker_request_duration_seconds Latency of EmailListChecker API requests.
+# TYPE emaillistchecker_request_duration_seconds histogram
+emaillistchecker_request_duration_seconds_bucket{operation="GetCredits",le="0.1"} 0
+emaillistchecker_request_duration_seconds_bucket{operation="GetCredits",le="1"} 1
+emaillistchecker_request_duration_seconds_bucket{operation="GetCredits",le="+Inf"} 1
+emaillistchecker_request_duration_seconds_sum{operation="GetCredits"} 0.25
+emaillistchecker_request_duration_seconds_count{operation="GetCredits"} 1
+emaillistchecker_request_duration_seconds_bucket{operation="Verify",le="0.1"} 1
+emaillistchecker_request_duration_seconds_bucket{operation="Verify",le="1"} 2
+emaillistchecker_request_duration_seconds_bucket{operation="Verify",le="+Inf"} 3
+emaillistchecker_request_duration_seconds_sum{operation="Verify"} 2.55
+emaillistchecker_request_duration_seconds_count{operation="Verify"} 3
+# HELP emaillistchecker_credits_spent_total Estimated credits spent, one per verified address or finder lookup.
+# TYPE emaillistchecker_credits_spent_total counter
+emaillistchecker_credits_spent_total{operation="Verify"} 2
+emaillistchecker_credits_spent_total{operation="VerifyBatch"} 100
+# HELP emaillistchecker_cache_lookups_total Verification cache lookups.
+# TYPE emaillistchecker_cache_lookups_total counter
+emaillistchecker_cache_lookups_total{result="hit"} 1
+emaillistchecker_cache_lookups_total{result="miss"} 3
+# HELP emaillistchecker_cache_hit_ratio Share of verification cache lookups that were hits.
+# TYPE emaillistchecker_cache_hit_ratio gauge
+emaillistchecker_cache_hit_ratio 0.25
+`
+
+	var b strings.Builder
+	if err := registry.WriteText(&b); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+	if b.String() != want {
+		t.Errorf("WriteText:\n%s\nwant:\n%s", b.String(), want)
+	}
+
+	rec := httptest.NewRecorder()
+	registry.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Body.String() != want || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("ServeHTTP = %q with Content-Type %q", rec.Body.String(), rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestEscapesLabels(t *testing.T) {
+	registry := metrics.NewRegistry()
+	registry.ObserveRequest("a\"b\\c\nd", "2xx", "", time.Millisecond)
+
+	var b strings.Builder
+	registry.WriteText(&b)
+	if line := `emaillistchecker_requests_total{operation="a\"b\\c\nd",status_class="2xx",error_type=""} 1`; !strings.Contains(b.String(), line) {
+		t.Errorf("output does not contain %s:\n%s", line, b.String())
+	}
+}
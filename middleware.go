@@ -0,0 +1,132 @@
+package emaillistchecker
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// Doer sends an HTTP request. *http.Client satisfies it.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts a function to the Doer interface
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req)
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps every HTTP request the client makes, including file uploads
+// and downloads. Use OperationName to find which client method issued a request.
+type Middleware func(next Doer) Doer
+
+// Use adds middlewares around every request. The first middleware added is the
+// outermost and sees each call once, before the client's retries.
+func (c *Client) Use(middlewares ...Middleware) {
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+// requestInfoKey is the context key holding the requestInfo of a request
+type requestInfoKey struct{}
+
+// requestInfo describes the client call behind an HTTP request
+type requestInfo struct {
+	operation string
+	endpoint  string
+}
+
+// OperationName returns the client method that issued the request with this
+// context, for example "Verify" or "GetBatchStatus", or "" for other contexts
+func OperationName(ctx context.Context) string {
+	info, _ := ctx.Value(requestInfoKey{}).(requestInfo)
+	return info.operation
+}
+
+// tagKey is the context key holding the caller tag
+type tagKey struct{}
+
+// WithTag labels the calls made with ctx, for example by job or tenant, so
+// budgets can limit them separately:
+//
+//	client.WithContext(emaillistchecker.WithTag(ctx, "nightly-import")).VerifyBatch(...)
+func WithTag(ctx context.Context, tag string) context.Context {
+	return context.WithValue(ctx, tagKey{}, tag)
+}
+
+// TagFromContext returns the tag set with WithTag, or ""
+func TagFromContext(ctx context.Context) string {
+	tag, _ := ctx.Value(tagKey{}).(string)
+	return tag
+}
+
+// endpointFromContext returns the API path and query of a request, without the base URL
+func endpointFromContext(ctx context.Context) string {
+	info, _ := ctx.Value(requestInfoKey{}).(requestInfo)
+	return info.endpoint
+}
+
+// WithContext returns a shallow copy of the client whose requests use ctx, for
+// cancellation, deadlines and trace propagation:
+//
+//	result, err := client.WithContext(ctx).Verify(email, nil, false)
+func (c *Client) WithContext(ctx context.Context) *Client {
+	copied := *c
+	copied.ctx = ctx
+	return &copied
+}
+
+// context returns the context set with WithContext, or context.Background()
+func (c *Client) context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+// newRequest creates an authenticated API request for the operation op
+func (c *Client) newRequest(op, method, endpoint string, body io.Reader) (*http.Request, error) {
+	ctx := context.WithValue(c.context(), requestInfoKey{}, requestInfo{
+		operation: op,
+		endpoint:  endpoint,
+	})
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("User-Agent", "EmailListChecker-Go/1.0.0")
+	return req, nil
+}
+
+// do sends req through the middleware chain: the operation span, user
+// middlewares, retries, then metrics, logging and a span for each attempt
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	var next Doer = c.httpClient
+	if c.tracer != nil {
+		next = attemptTracing(c.tracer)(next)
+	}
+	if c.logger != nil {
+		next = LoggingMiddleware(c.logger, c.logOptions)(next)
+	}
+	if c.metrics != nil {
+		next = MetricsMiddleware(c.metrics)(next)
+	}
+	if c.attribution != nil {
+		next = attributionMiddleware(c.attribution)(next)
+	}
+	if c.retry != nil {
+		next = RetryMiddleware(*c.retry)(next)
+	}
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		next = c.middlewares[i](next)
+	}
+	if c.tracer != nil {
+		next = operationTracing(c.tracer)(next)
+	}
+	return next.Do(req)
+}
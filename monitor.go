@@ -0,0 +1,337 @@
+package emaillistchecker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Clock abstracts time so a Monitor can be driven by a fake clock in tests.
+// emaillistcheckertest.Clock is one such fake.
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks like time.Ticker
+type Ticker interface {
+	Chan() <-chan time.Time
+	Stop()
+}
+
+// realClock is the system clock
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTicker(d time.Duration) Ticker { return realTicker{time.NewTicker(d)} }
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) Chan() <-chan time.Time { return t.C }
+
+// AlertKind identifies what a monitor alert is about
+type AlertKind string
+
+const (
+	// AlertLowBalance fires when the balance drops below MonitorOptions.LowBalance
+	AlertLowBalance AlertKind = "low_balance"
+	// AlertExhaustion fires when the burn rate projects the balance running out
+	// within MonitorOptions.ExhaustionDays
+	AlertExhaustion AlertKind = "exhaustion"
+	// AlertFailureSpike fires when the share of failed requests since the previous
+	// poll exceeds MonitorOptions.FailureRatio
+	AlertFailureSpike AlertKind = "failure_spike"
+)
+
+// Alert is raised by a Monitor. Each kind fires once when its condition starts
+// to hold and again only after it has cleared.
+type Alert struct {
+	Kind    AlertKind
+	At      time.Time
+	Balance int
+	// BurnPerDay and DaysLeft are set for AlertExhaustion
+	BurnPerDay float64
+	DaysLeft   float64
+	// FailureRatio and Requests cover the requests since the previous poll, for AlertFailureSpike
+	FailureRatio float64
+	Requests     int
+	Message      string
+}
+
+// MonitorOptions configures a Monitor. Zero thresholds disable their alert.
+type MonitorOptions struct {
+	// Interval between polls of GetCredits and GetUsage (default 5 minutes)
+	Interval time.Duration
+	// LowBalance alerts when the balance is below this many credits
+	LowBalance int
+	// ExhaustionDays alerts when the balance is projected to run out within this many days
+	ExhaustionDays float64
+	// BurnWindow is how much balance history the burn rate is computed over (default 24 hours)
+	BurnWindow time.Duration
+	// FailureRatio alerts when failed requests exceed this share of the requests since the previous poll
+	FailureRatio float64
+	// MinRequests is the fewest requests between polls for FailureRatio to apply (default 20)
+	MinRequests int
+	// OnAlert receives alerts and OnError polling failures; both are called from
+	// the monitor goroutine and may call Stop
+	OnAlert func(Alert)
+	OnError func(error)
+	// Clock defaults to the system clock
+	Clock Clock
+}
+
+// Monitor polls the account balance and usage in the background and raises alerts
+type Monitor struct {
+	client *Client
+	opts   MonitorOptions
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// checkMu serializes polls so samples are evaluated in order
+	checkMu sync.Mutex
+
+	mu         sync.Mutex
+	started    bool
+	notifying  bool
+	history    []balanceSample
+	lastTotal  int
+	lastFailed int
+	haveUsage  bool
+	firing     map[AlertKind]bool
+}
+
+type balanceSample struct {
+	at      time.Time
+	balance int
+}
+
+// NewMonitor creates a monitor. Call Start to poll in the background, or
+// Check to poll on demand.
+func (c *Client) NewMonitor(opts MonitorOptions) *Monitor {
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Minute
+	}
+	if opts.BurnWindow <= 0 {
+		opts.BurnWindow = 24 * time.Hour
+	}
+	if opts.MinRequests <= 0 {
+		opts.MinRequests = 20
+	}
+	if opts.Clock == nil {
+		opts.Clock = realClock{}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Monitor{
+		client: c,
+		opts:   opts,
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		firing: make(map[AlertKind]bool),
+	}
+}
+
+// Start polls once immediately and then every Interval until Stop is called.
+// Calling Start again, or after Stop, does nothing.
+func (m *Monitor) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.started || m.ctx.Err() != nil {
+		return
+	}
+	m.started = true
+
+	ticker := m.opts.Clock.NewTicker(m.opts.Interval)
+	go func() {
+		defer close(m.done)
+		defer ticker.Stop()
+
+		m.poll()
+		for {
+			select {
+			case <-ticker.Chan():
+				m.poll()
+			case <-m.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops polling, cancelling any request in flight, and waits for the
+// monitor goroutine to exit. It is safe to call more than once. While the
+// goroutine is running OnAlert or OnError, Stop does not wait for it, so the
+// callbacks can call Stop themselves; no further callbacks are made.
+func (m *Monitor) Stop() {
+	m.cancel()
+
+	m.mu.Lock()
+	wait := m.started && !m.notifying
+	m.mu.Unlock()
+	if wait {
+		<-m.done
+	}
+}
+
+// Check polls once and returns the alerts raised, also passing them to OnAlert
+func (m *Monitor) Check() ([]Alert, error) {
+	return m.check(func(f func()) { f() })
+}
+
+// check polls once, running the OnAlert calls through notify
+func (m *Monitor) check(notify func(func())) ([]Alert, error) {
+	m.checkMu.Lock()
+	defer m.checkMu.Unlock()
+
+	client := m.client.WithContext(m.ctx)
+	now := m.opts.Clock.Now()
+
+	credits, err := client.GetCredits()
+	if err != nil {
+		return nil, err
+	}
+	balance, ok := creditBalance(credits)
+	if !ok {
+		return nil, errors.New("credits response has no balance")
+	}
+
+	usage, err := client.GetUsage(m.ctx, UsageQuery{})
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	alerts := m.evaluate(now, balance, usage)
+	m.mu.Unlock()
+
+	if m.opts.OnAlert != nil {
+		for _, alert := range alerts {
+			alert := alert
+			notify(func() { m.opts.OnAlert(alert) })
+		}
+	}
+	return alerts, nil
+}
+
+// poll runs Check from the monitor goroutine. Callbacks are marked as running
+// so Stop called from one does not wait for the goroutine, and are skipped once
+// the monitor is stopped.
+func (m *Monitor) poll() {
+	notify := func(f func()) {
+		m.mu.Lock()
+		if m.ctx.Err() != nil {
+			m.mu.Unlock()
+			return
+		}
+		m.notifying = true
+		m.mu.Unlock()
+
+		defer func() {
+			m.mu.Lock()
+			m.notifying = false
+			m.mu.Unlock()
+		}()
+		f()
+	}
+
+	if _, err := m.check(notify); err != nil && m.opts.OnError != nil {
+		notify(func() { m.opts.OnError(err) })
+	}
+}
+
+// evaluate updates the history with a poll and returns newly raised alerts
+func (m *Monitor) evaluate(now time.Time, balance int, usage *UsageReport) []Alert {
+	var alerts []Alert
+
+	low := m.opts.LowBalance > 0 && balance < m.opts.LowBalance
+	if m.transition(AlertLowBalance, low) {
+		alerts = append(alerts, Alert{
+			Kind:    AlertLowBalance,
+			At:      now,
+			Balance: balance,
+			Message: fmt.Sprintf("balance %d is below %d credits", balance, m.opts.LowBalance),
+		})
+	}
+
+	// A top-up restarts the burn rate history
+	if n := len(m.history); n > 0 && balance > m.history[n-1].balance {
+		m.history = nil
+	}
+	m.history = append(m.history, balanceSample{at: now, balance: balance})
+	cutoff := now.Add(-m.opts.BurnWindow)
+	for len(m.history) > 2 && m.history[1].at.Before(cutoff) {
+		m.history = m.history[1:]
+	}
+
+	var burnPerDay, daysLeft float64
+	exhausting := false
+	if oldest := m.history[0]; m.opts.ExhaustionDays > 0 && now.After(oldest.at) {
+		days := now.Sub(oldest.at).Hours() / 24
+		burnPerDay = float64(oldest.balance-balance) / days
+		if burnPerDay > 0 {
+			daysLeft = float64(balance) / burnPerDay
+			exhausting = daysLeft < m.opts.ExhaustionDays
+		}
+	}
+	if m.transition(AlertExhaustion, exhausting) {
+		alerts = append(alerts, Alert{
+			Kind:       AlertExhaustion,
+			At:         now,
+			Balance:    balance,
+			BurnPerDay: burnPerDay,
+			DaysLeft:   daysLeft,
+			Message:    fmt.Sprintf("at %.0f credits a day the balance of %d runs out in %.1f days", burnPerDay, balance, daysLeft),
+		})
+	}
+
+	total, failed := usage.TotalRequests, usage.FailedRequests
+	spiking := false
+	var ratio float64
+	var requests int
+	if m.haveUsage && total >= m.lastTotal && failed >= m.lastFailed {
+		requests = total - m.lastTotal
+		if requests > 0 {
+			ratio = float64(failed-m.lastFailed) / float64(requests)
+		}
+		spiking = m.opts.FailureRatio > 0 && requests >= m.opts.MinRequests && ratio > m.opts.FailureRatio
+	}
+	m.lastTotal, m.lastFailed, m.haveUsage = total, failed, true
+	if m.transition(AlertFailureSpike, spiking) {
+		alerts = append(alerts, Alert{
+			Kind:         AlertFailureSpike,
+			At:           now,
+			Balance:      balance,
+			FailureRatio: ratio,
+			Requests:     requests,
+			Message:      fmt.Sprintf("%.0f%% of %d requests failed since the previous poll", ratio*100, requests),
+		})
+	}
+
+	return alerts
+}
+
+// transition records whether a condition holds and reports whether it just started
+func (m *Monitor) transition(kind AlertKind, holds bool) bool {
+	started := holds && !m.firing[kind]
+	m.firing[kind] = holds
+	return started
+}
+
+// numberField reads an integer from an untyped API response
+func numberField(data map[string]interface{}, key string) (int, bool) {
+	switch v := data[key].(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	}
+	return 0, false
+}
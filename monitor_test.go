@@ -0,0 +1,50 @@
+package emaillistchecker_test
+
+import (
+	"testing"
+	"time"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+func TestMonitorStopFromCallback(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault bool
+	}{
+		{"OnAlert", false},
+		{"OnError", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := emaillistcheckertest.NewServer()
+			defer srv.Close()
+			if tt.fault {
+				srv.Fail("/credits", emaillistcheckertest.Fault{Status: 400})
+			}
+
+			stopped := make(chan struct{})
+			var monitor *emaillistchecker.Monitor
+			stop := func() {
+				monitor.Stop()
+				close(stopped)
+			}
+			monitor = srv.Client().NewMonitor(emaillistchecker.MonitorOptions{
+				LowBalance: emaillistcheckertest.DefaultCredits + 1,
+				OnAlert:    func(emaillistchecker.Alert) { stop() },
+				OnError:    func(error) { stop() },
+				Clock:      emaillistcheckertest.NewClock(time.Now()),
+			})
+			monitor.Start()
+
+			select {
+			case <-stopped:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Stop called from a callback did not return")
+			}
+			// The goroutine has exited, so Stop from outside returns too
+			monitor.Stop()
+		})
+	}
+}
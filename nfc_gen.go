@@ -0,0 +1,192 @@
+//go:build ignore
+
+// nfc_gen generates nfc_tables.go from the Unicode Character Database.
+//
+//	go run nfc_gen.go [-version 14.0.0] [-dir DIR]
+//
+// UnicodeData.txt and CompositionExclusions.txt are read from DIR if given,
+// otherwise downloaded from unicode.org.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	version = flag.String("version", "14.0.0", "Unicode version")
+	dir     = flag.String("dir", "", "directory with the UCD files (default: download them)")
+	output  = flag.String("output", "nfc_tables.go", "output file")
+)
+
+func main() {
+	flag.Parse()
+
+	ccc := make(map[rune]uint8)
+	decomposition := make(map[rune][]rune)
+	readLines("UnicodeData.txt", func(fields []string) {
+		r := parseRune(fields[0])
+		if class, _ := strconv.Atoi(fields[3]); class != 0 {
+			ccc[r] = uint8(class)
+		}
+		// Compatibility decompositions start with a <tag> and are not used by NFC
+		if fields[5] != "" && !strings.HasPrefix(fields[5], "<") {
+			for _, code := range strings.Fields(fields[5]) {
+				decomposition[r] = append(decomposition[r], parseRune(code))
+			}
+		}
+	})
+
+	excluded := make(map[rune]bool)
+	readLines("CompositionExclusions.txt", func(fields []string) {
+		excluded[parseRune(fields[0])] = true
+	})
+
+	// Full canonical decompositions, applied recursively
+	var expand func(r rune) []rune
+	expand = func(r rune) []rune {
+		d, ok := decomposition[r]
+		if !ok {
+			return []rune{r}
+		}
+		var full []rune
+		for _, c := range d {
+			full = append(full, expand(c)...)
+		}
+		return full
+	}
+
+	// Primary composites: pairs whose composition is not excluded, singleton
+	// or a non-starter decomposition
+	composition := make(map[[2]rune]rune)
+	for r, d := range decomposition {
+		if len(d) != 2 || excluded[r] || ccc[r] != 0 || ccc[d[0]] != 0 {
+			continue
+		}
+		composition[[2]rune{d[0], d[1]}] = r
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by nfc_gen.go from Unicode %s; DO NOT EDIT.\n\n", *version)
+	fmt.Fprintf(&b, "package emaillistchecker\n\n")
+	fmt.Fprintf(&b, "// nfcUnicodeVersion is the Unicode version of the normalization tables\n")
+	fmt.Fprintf(&b, "const nfcUnicodeVersion = %q\n\n", *version)
+
+	fmt.Fprintf(&b, "// combiningClass holds the non-zero canonical combining classes\n")
+	fmt.Fprintf(&b, "var combiningClass = map[rune]uint8{\n")
+	writeSorted(&b, keys(ccc), func(r rune) string { return fmt.Sprintf("%#x: %d", r, ccc[r]) })
+	fmt.Fprintf(&b, "}\n\n")
+
+	fmt.Fprintf(&b, "// canonicalDecomposition holds full canonical decompositions\n")
+	fmt.Fprintf(&b, "var canonicalDecomposition = map[rune]string{\n")
+	writeSorted(&b, keys(decomposition), func(r rune) string {
+		return fmt.Sprintf("%#x: %s", r, strconv.QuoteToASCII(string(expand(r))))
+	})
+	fmt.Fprintf(&b, "}\n\n")
+
+	fmt.Fprintf(&b, "// canonicalComposition maps a starter and the following character to their primary composite\n")
+	fmt.Fprintf(&b, "var canonicalComposition = map[[2]rune]rune{\n")
+	composites := make([]rune, 0, len(composition))
+	pairs := make(map[rune][2]rune, len(composition))
+	for pair, r := range composition {
+		composites = append(composites, r)
+		pairs[r] = pair
+	}
+	writeSorted(&b, composites, func(r rune) string {
+		return fmt.Sprintf("{%#x, %#x}: %#x", pairs[r][0], pairs[r][1], r)
+	})
+	fmt.Fprintf(&b, "}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readLines calls fn with the semicolon-separated fields of each data line of a UCD file
+func readLines(name string, fn func(fields []string)) {
+	var r io.Reader
+	if *dir != "" {
+		f, err := os.Open(filepath.Join(*dir, name))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		r = f
+	} else {
+		resp, err := http.Get("https://www.unicode.org/Public/" + *version + "/ucd/" + name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			log.Fatalf("%s: %s", name, resp.Status)
+		}
+		r = resp.Body
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		fn(fields)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func parseRune(code string) rune {
+	n, err := strconv.ParseUint(code, 16, 32)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return rune(n)
+}
+
+func keys[V any](m map[rune]V) []rune {
+	runes := make([]rune, 0, len(m))
+	for r := range m {
+		runes = append(runes, r)
+	}
+	return runes
+}
+
+// writeSorted writes one entry per rune in code point order, four to a line
+func writeSorted(b *bytes.Buffer, runes []rune, entry func(rune) string) {
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	for i, r := range runes {
+		if i%4 == 0 {
+			b.WriteString("\t")
+		}
+		b.WriteString(entry(r) + ",")
+		if i%4 == 3 || i == len(runes)-1 {
+			b.WriteString("\n")
+		} else {
+			b.WriteString(" ")
+		}
+	}
+}
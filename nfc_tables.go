@@ -0,0 +1,998 @@
+// Code generated by nfc_gen.go from Unicode 14.0.0; DO NOT EDIT.
+
+package emaillistchecker
+
+// nfcUnicodeVersion is the Unicode version of the normalization tables
+const nfcUnicodeVersion = "14.0.0"
+
+// combiningClass holds the non-zero canonical combining classes
+var combiningClass = map[rune]uint8{
+	0x300: 230, 0x301: 230, 0x302: 230, 0x303: 230,
+	0x304: 230, 0x305: 230, 0x306: 230, 0x307: 230,
+	0x308: 230, 0x309: 230, 0x30a: 230, 0x30b: 230,
+	0x30c: 230, 0x30d: 230, 0x30e: 230, 0x30f: 230,
+	0x310: 230, 0x311: 230, 0x312: 230, 0x313: 230,
+	0x314: 230, 0x315: 232, 0x316: 220, 0x317: 220,
+	0x318: 220, 0x319: 220, 0x31a: 232, 0x31b: 216,
+	0x31c: 220, 0x31d: 220, 0x31e: 220, 0x31f: 220,
+	0x320: 220, 0x321: 202, 0x322: 202, 0x323: 220,
+	0x324: 220, 0x325: 220, 0x326: 220, 0x327: 202,
+	0x328: 202, 0x329: 220, 0x32a: 220, 0x32b: 220,
+	0x32c: 220, 0x32d: 220, 0x32e: 220, 0x32f: 220,
+	0x330: 220, 0x331: 220, 0x332: 220, 0x333: 220,
+	0x334: 1, 0x335: 1, 0x336: 1, 0x337: 1,
+	0x338: 1, 0x339: 220, 0x33a: 220, 0x33b: 220,
+	0x33c: 220, 0x33d: 230, 0x33e: 230, 0x33f: 230,
+	0x340: 230, 0x341: 230, 0x342: 230, 0x343: 230,
+	0x344: 230, 0x345: 240, 0x346: 230, 0x347: 220,
+	0x348: 220, 0x349: 220, 0x34a: 230, 0x34b: 230,
+	0x34c: 230, 0x34d: 220, 0x34e: 220, 0x350: 230,
+	0x351: 230, 0x352: 230, 0x353: 220, 0x354: 220,
+	0x355: 220, 0x356: 220, 0x357: 230, 0x358: 232,
+	0x359: 220, 0x35a: 220, 0x35b: 230, 0x35c: 233,
+	0x35d: 234, 0x35e: 234, 0x35f: 233, 0x360: 234,
+	0x361: 234, 0x362: 233, 0x363: 230, 0x364: 230,
+	0x365: 230, 0x366: 230, 0x367: 230, 0x368: 230,
+	0x369: 230, 0x36a: 230, 0x36b: 230, 0x36c: 230,
+	0x36d: 230, 0x36e: 230, 0x36f: 230, 0x483: 230,
+	0x484: 230, 0x485: 230, 0x486: 230, 0x487: 230,
+	0x591: 220, 0x592: 230, 0x593: 230, 0x594: 230,
+	0x595: 230, 0x596: 220, 0x597: 230, 0x598: 230,
+	0x599: 230, 0x59a: 222, 0x59b: 220, 0x59c: 230,
+	0x59d: 230, 0x59e: 230, 0x59f: 230, 0x5a0: 230,
+	0x5a1: 230, 0x5a2: 220, 0x5a3: 220, 0x5a4: 220,
+	0x5a5: 220, 0x5a6: 220, 0x5a7: 220, 0x5a8: 230,
+	0x5a9: 230, 0x5aa: 220, 0x5ab: 230, 0x5ac: 230,
+	0x5ad: 222, 0x5ae: 228, 0x5af: 230, 0x5b0: 10,
+	0x5b1: 11, 0x5b2: 12, 0x5b3: 13, 0x5b4: 14,
+	0x5b5: 15, 0x5b6: 16, 0x5b7: 17, 0x5b8: 18,
+	0x5b9: 19, 0x5ba: 19, 0x5bb: 20, 0x5bc: 21,
+	0x5bd: 22, 0x5bf: 23, 0x5c1: 24, 0x5c2: 25,
+	0x5c4: 230, 0x5c5: 220, 0x5c7: 18, 0x610: 230,
+	0x611: 230, 0x612: 230, 0x613: 230, 0x614: 230,
+	0x615: 230, 0x616: 230, 0x617: 230, 0x618: 30,
+	0x619: 31, 0x61a: 32, 0x64b: 27, 0x64c: 28,
+	0x64d: 29, 0x64e: 30, 0x64f: 31, 0x650: 32,
+	0x651: 33, 0x652: 34, 0x653: 230, 0x654: 230,
+	0x655: 220, 0x656: 220, 0x657: 230, 0x658: 230,
+	0x659: 230, 0x65a: 230, 0x65b: 230, 0x65c: 220,
+	0x65d: 230, 0x65e: 230, 0x65f: 220, 0x670: 35,
+	0x6d6: 230, 0x6d7: 230, 0x6d8: 230, 0x6d9: 230,
+	0x6da: 230, 0x6db: 230, 0x6dc: 230, 0x6df: 230,
+	0x6e0: 230, 0x6e1: 230, 0x6e2: 230, 0x6e3: 220,
+	0x6e4: 230, 0x6e7: 230, 0x6e8: 230, 0x6ea: 220,
+	0x6eb: 230, 0x6ec: 230, 0x6ed: 220, 0x711: 36,
+	0x730: 230, 0x731: 220, 0x732: 230, 0x733: 230,
+	0x734: 220, 0x735: 230, 0x736: 230, 0x737: 220,
+	0x738: 220, 0x739: 220, 0x73a: 230, 0x73b: 220,
+	0x73c: 220, 0x73d: 230, 0x73e: 220, 0x73f: 230,
+	0x740: 230, 0x741: 230, 0x742: 220, 0x743: 230,
+	0x744: 220, 0x745: 230, 0x746: 220, 0x747: 230,
+	0x748: 220, 0x749: 230, 0x74a: 230, 0x7eb: 230,
+	0x7ec: 230, 0x7ed: 230, 0x7ee: 230, 0x7ef: 230,
+	0x7f0: 230, 0x7f1: 230, 0x7f2: 220, 0x7f3: 230,
+	0x7fd: 220, 0x816: 230, 0x817: 230, 0x818: 230,
+	0x819: 230, 0x81b: 230, 0x81c: 230, 0x81d: 230,
+	0x81e: 230, 0x81f: 230, 0x820: 230, 0x821: 230,
+	0x822: 230, 0x823: 230, 0x825: 230, 0x826: 230,
+	0x827: 230, 0x829: 230, 0x82a: 230, 0x82b: 230,
+	0x82c: 230, 0x82d: 230, 0x859: 220, 0x85a: 220,
+	0x85b: 220, 0x898: 230, 0x899: 220, 0x89a: 220,
+	0x89b: 220, 0x89c: 230, 0x89d: 230, 0x89e: 230,
+	0x89f: 230, 0x8ca: 230, 0x8cb: 230, 0x8cc: 230,
+	0x8cd: 230, 0x8ce: 230, 0x8cf: 220, 0x8d0: 220,
+	0x8d1: 220, 0x8d2: 220, 0x8d3: 220, 0x8d4: 230,
+	0x8d5: 230, 0x8d6: 230, 0x8d7: 230, 0x8d8: 230,
+	0x8d9: 230, 0x8da: 230, 0x8db: 230, 0x8dc: 230,
+	0x8dd: 230, 0x8de: 230, 0x8df: 230, 0x8e0: 230,
+	0x8e1: 230, 0x8e3: 220, 0x8e4: 230, 0x8e5: 230,
+	0x8e6: 220, 0x8e7: 230, 0x8e8: 230, 0x8e9: 220,
+	0x8ea: 230, 0x8eb: 230, 0x8ec: 230, 0x8ed: 220,
+	0x8ee: 220, 0x8ef: 220, 0x8f0: 27, 0x8f1: 28,
+	0x8f2: 29, 0x8f3: 230, 0x8f4: 230, 0x8f5: 230,
+	0x8f6: 220, 0x8f7: 230, 0x8f8: 230, 0x8f9: 220,
+	0x8fa: 220, 0x8fb: 230, 0x8fc: 230, 0x8fd: 230,
+	0x8fe: 230, 0x8ff: 230, 0x93c: 7, 0x94d: 9,
+	0x951: 230, 0x952: 220, 0x953: 230, 0x954: 230,
+	0x9bc: 7, 0x9cd: 9, 0x9fe: 230, 0xa3c: 7,
+	0xa4d: 9, 0xabc: 7, 0xacd: 9, 0xb3c: 7,
+	0xb4d: 9, 0xbcd: 9, 0xc3c: 7, 0xc4d: 9,
+	0xc55: 84, 0xc56: 91, 0xcbc: 7, 0xccd: 9,
+	0xd3b: 9, 0xd3c: 9, 0xd4d: 9, 0xdca: 9,
+	0xe38: 103, 0xe39: 103, 0xe3a: 9, 0xe48: 107,
+	0xe49: 107, 0xe4a: 107, 0xe4b: 107, 0xeb8: 118,
+	0xeb9: 118, 0xeba: 9, 0xec8: 122, 0xec9: 122,
+	0xeca: 122, 0xecb: 122, 0xf18: 220, 0xf19: 220,
+	0xf35: 220, 0xf37: 220, 0xf39: 216, 0xf71: 129,
+	0xf72: 130, 0xf74: 132, 0xf7a: 130, 0xf7b: 130,
+	0xf7c: 130, 0xf7d: 130, 0xf80: 130, 0xf82: 230,
+	0xf83: 230, 0xf84: 9, 0xf86: 230, 0xf87: 230,
+	0xfc6: 220, 0x1037: 7, 0x1039: 9, 0x103a: 9,
+	0x108d: 220, 0x135d: 230, 0x135e: 230, 0x135f: 230,
+	0x1714: 9, 0x1715: 9, 0x1734: 9, 0x17d2: 9,
+	0x17dd: 230, 0x18a9: 228, 0x1939: 222, 0x193a: 230,
+	0x193b: 220, 0x1a17: 230, 0x1a18: 220, 0x1a60: 9,
+	0x1a75: 230, 0x1a76: 230, 0x1a77: 230, 0x1a78: 230,
+	0x1a79: 230, 0x1a7a: 230, 0x1a7b: 230, 0x1a7c: 230,
+	0x1a7f: 220, 0x1ab0: 230, 0x1ab1: 230, 0x1ab2: 230,
+	0x1ab3: 230, 0x1ab4: 230, 0x1ab5: 220, 0x1ab6: 220,
+	0x1ab7: 220, 0x1ab8: 220, 0x1ab9: 220, 0x1aba: 220,
+	0x1abb: 230, 0x1abc: 230, 0x1abd: 220, 0x1abf: 220,
+	0x1ac0: 220, 0x1ac1: 230, 0x1ac2: 230, 0x1ac3: 220,
+	0x1ac4: 220, 0x1ac5: 230, 0x1ac6: 230, 0x1ac7: 230,
+	0x1ac8: 230, 0x1ac9: 230, 0x1aca: 220, 0x1acb: 230,
+	0x1acc: 230, 0x1acd: 230, 0x1ace: 230, 0x1b34: 7,
+	0x1b44: 9, 0x1b6b: 230, 0x1b6c: 220, 0x1b6d: 230,
+	0x1b6e: 230, 0x1b6f: 230, 0x1b70: 230, 0x1b71: 230,
+	0x1b72: 230, 0x1b73: 230, 0x1baa: 9, 0x1bab: 9,
+	0x1be6: 7, 0x1bf2: 9, 0x1bf3: 9, 0x1c37: 7,
+	0x1cd0: 230, 0x1cd1: 230, 0x1cd2: 230, 0x1cd4: 1,
+	0x1cd5: 220, 0x1cd6: 220, 0x1cd7: 220, 0x1cd8: 220,
+	0x1cd9: 220, 0x1cda: 230, 0x1cdb: 230, 0x1cdc: 220,
+	0x1cdd: 220, 0x1cde: 220, 0x1cdf: 220, 0x1ce0: 230,
+	0x1ce2: 1, 0x1ce3: 1, 0x1ce4: 1, 0x1ce5: 1,
+	0x1ce6: 1, 0x1ce7: 1, 0x1ce8: 1, 0x1ced: 220,
+	0x1cf4: 230, 0x1cf8: 230, 0x1cf9: 230, 0x1dc0: 230,
+	0x1dc1: 230, 0x1dc2: 220, 0x1dc3: 230, 0x1dc4: 230,
+	0x1dc5: 230, 0x1dc6: 230, 0x1dc7: 230, 0x1dc8: 230,
+	0x1dc9: 230, 0x1dca: 220, 0x1dcb: 230, 0x1dcc: 230,
+	0x1dcd: 234, 0x1dce: 214, 0x1dcf: 220, 0x1dd0: 202,
+	0x1dd1: 230, 0x1dd2: 230, 0x1dd3: 230, 0x1dd4: 230,
+	0x1dd5: 230, 0x1dd6: 230, 0x1dd7: 230, 0x1dd8: 230,
+	0x1dd9: 230, 0x1dda: 230, 0x1ddb: 230, 0x1ddc: 230,
+	0x1ddd: 230, 0x1dde: 230, 0x1ddf: 230, 0x1de0: 230,
+	0x1de1: 230, 0x1de2: 230, 0x1de3: 230, 0x1de4: 230,
+	0x1de5: 230, 0x1de6: 230, 0x1de7: 230, 0x1de8: 230,
+	0x1de9: 230, 0x1dea: 230, 0x1deb: 230, 0x1dec: 230,
+	0x1ded: 230, 0x1dee: 230, 0x1def: 230, 0x1df0: 230,
+	0x1df1: 230, 0x1df2: 230, 0x1df3: 230, 0x1df4: 230,
+	0x1df5: 230, 0x1df6: 232, 0x1df7: 228, 0x1df8: 228,
+	0x1df9: 220, 0x1dfa: 218, 0x1dfb: 230, 0x1dfc: 233,
+	0x1dfd: 220, 0x1dfe: 230, 0x1dff: 220, 0x20d0: 230,
+	0x20d1: 230, 0x20d2: 1, 0x20d3: 1, 0x20d4: 230,
+	0x20d5: 230, 0x20d6: 230, 0x20d7: 230, 0x20d8: 1,
+	0x20d9: 1, 0x20da: 1, 0x20db: 230, 0x20dc: 230,
+	0x20e1: 230, 0x20e5: 1, 0x20e6: 1, 0x20e7: 230,
+	0x20e8: 220, 0x20e9: 230, 0x20ea: 1, 0x20eb: 1,
+	0x20ec: 220, 0x20ed: 220, 0x20ee: 220, 0x20ef: 220,
+	0x20f0: 230, 0x2cef: 230, 0x2cf0: 230, 0x2cf1: 230,
+	0x2d7f: 9, 0x2de0: 230, 0x2de1: 230, 0x2de2: 230,
+	0x2de3: 230, 0x2de4: 230, 0x2de5: 230, 0x2de6: 230,
+	0x2de7: 230, 0x2de8: 230, 0x2de9: 230, 0x2dea: 230,
+	0x2deb: 230, 0x2dec: 230, 0x2ded: 230, 0x2dee: 230,
+	0x2def: 230, 0x2df0: 230, 0x2df1: 230, 0x2df2: 230,
+	0x2df3: 230, 0x2df4: 230, 0x2df5: 230, 0x2df6: 230,
+	0x2df7: 230, 0x2df8: 230, 0x2df9: 230, 0x2dfa: 230,
+	0x2dfb: 230, 0x2dfc: 230, 0x2dfd: 230, 0x2dfe: 230,
+	0x2dff: 230, 0x302a: 218, 0x302b: 228, 0x302c: 232,
+	0x302d: 222, 0x302e: 224, 0x302f: 224, 0x3099: 8,
+	0x309a: 8, 0xa66f: 230, 0xa674: 230, 0xa675: 230,
+	0xa676: 230, 0xa677: 230, 0xa678: 230, 0xa679: 230,
+	0xa67a: 230, 0xa67b: 230, 0xa67c: 230, 0xa67d: 230,
+	0xa69e: 230, 0xa69f: 230, 0xa6f0: 230, 0xa6f1: 230,
+	0xa806: 9, 0xa82c: 9, 0xa8c4: 9, 0xa8e0: 230,
+	0xa8e1: 230, 0xa8e2: 230, 0xa8e3: 230, 0xa8e4: 230,
+	0xa8e5: 230, 0xa8e6: 230, 0xa8e7: 230, 0xa8e8: 230,
+	0xa8e9: 230, 0xa8ea: 230, 0xa8eb: 230, 0xa8ec: 230,
+	0xa8ed: 230, 0xa8ee: 230, 0xa8ef: 230, 0xa8f0: 230,
+	0xa8f1: 230, 0xa92b: 220, 0xa92c: 220, 0xa92d: 220,
+	0xa953: 9, 0xa9b3: 7, 0xa9c0: 9, 0xaab0: 230,
+	0xaab2: 230, 0xaab3: 230, 0xaab4: 220, 0xaab7: 230,
+	0xaab8: 230, 0xaabe: 230, 0xaabf: 230, 0xaac1: 230,
+	0xaaf6: 9, 0xabed: 9, 0xfb1e: 26, 0xfe20: 230,
+	0xfe21: 230, 0xfe22: 230, 0xfe23: 230, 0xfe24: 230,
+	0xfe25: 230, 0xfe26: 230, 0xfe27: 220, 0xfe28: 220,
+	0xfe29: 220, 0xfe2a: 220, 0xfe2b: 220, 0xfe2c: 220,
+	0xfe2d: 220, 0xfe2e: 230, 0xfe2f: 230, 0x101fd: 220,
+	0x102e0: 220, 0x10376: 230, 0x10377: 230, 0x10378: 230,
+	0x10379: 230, 0x1037a: 230, 0x10a0d: 220, 0x10a0f: 230,
+	0x10a38: 230, 0x10a39: 1, 0x10a3a: 220, 0x10a3f: 9,
+	0x10ae5: 230, 0x10ae6: 220, 0x10d24: 230, 0x10d25: 230,
+	0x10d26: 230, 0x10d27: 230, 0x10eab: 230, 0x10eac: 230,
+	0x10f46: 220, 0x10f47: 220, 0x10f48: 230, 0x10f49: 230,
+	0x10f4a: 230, 0x10f4b: 220, 0x10f4c: 230, 0x10f4d: 220,
+	0x10f4e: 220, 0x10f4f: 220, 0x10f50: 220, 0x10f82: 230,
+	0x10f83: 220, 0x10f84: 230, 0x10f85: 220, 0x11046: 9,
+	0x11070: 9, 0x1107f: 9, 0x110b9: 9, 0x110ba: 7,
+	0x11100: 230, 0x11101: 230, 0x11102: 230, 0x11133: 9,
+	0x11134: 9, 0x11173: 7, 0x111c0: 9, 0x111ca: 7,
+	0x11235: 9, 0x11236: 7, 0x112e9: 7, 0x112ea: 9,
+	0x1133b: 7, 0x1133c: 7, 0x1134d: 9, 0x11366: 230,
+	0x11367: 230, 0x11368: 230, 0x11369: 230, 0x1136a: 230,
+	0x1136b: 230, 0x1136c: 230, 0x11370: 230, 0x11371: 230,
+	0x11372: 230, 0x11373: 230, 0x11374: 230, 0x11442: 9,
+	0x11446: 7, 0x1145e: 230, 0x114c2: 9, 0x114c3: 7,
+	0x115bf: 9, 0x115c0: 7, 0x1163f: 9, 0x116b6: 9,
+	0x116b7: 7, 0x1172b: 9, 0x11839: 9, 0x1183a: 7,
+	0x1193d: 9, 0x1193e: 9, 0x11943: 7, 0x119e0: 9,
+	0x11a34: 9, 0x11a47: 9, 0x11a99: 9, 0x11c3f: 9,
+	0x11d42: 7, 0x11d44: 9, 0x11d45: 9, 0x11d97: 9,
+	0x16af0: 1, 0x16af1: 1, 0x16af2: 1, 0x16af3: 1,
+	0x16af4: 1, 0x16b30: 230, 0x16b31: 230, 0x16b32: 230,
+	0x16b33: 230, 0x16b34: 230, 0x16b35: 230, 0x16b36: 230,
+	0x16ff0: 6, 0x16ff1: 6, 0x1bc9e: 1, 0x1d165: 216,
+	0x1d166: 216, 0x1d167: 1, 0x1d168: 1, 0x1d169: 1,
+	0x1d16d: 226, 0x1d16e: 216, 0x1d16f: 216, 0x1d170: 216,
+	0x1d171: 216, 0x1d172: 216, 0x1d17b: 220, 0x1d17c: 220,
+	0x1d17d: 220, 0x1d17e: 220, 0x1d17f: 220, 0x1d180: 220,
+	0x1d181: 220, 0x1d182: 220, 0x1d185: 230, 0x1d186: 230,
+	0x1d187: 230, 0x1d188: 230, 0x1d189: 230, 0x1d18a: 220,
+	0x1d18b: 220, 0x1d1aa: 230, 0x1d1ab: 230, 0x1d1ac: 230,
+	0x1d1ad: 230, 0x1d242: 230, 0x1d243: 230, 0x1d244: 230,
+	0x1e000: 230, 0x1e001: 230, 0x1e002: 230, 0x1e003: 230,
+	0x1e004: 230, 0x1e005: 230, 0x1e006: 230, 0x1e008: 230,
+	0x1e009: 230, 0x1e00a: 230, 0x1e00b: 230, 0x1e00c: 230,
+	0x1e00d: 230, 0x1e00e: 230, 0x1e00f: 230, 0x1e010: 230,
+	0x1e011: 230, 0x1e012: 230, 0x1e013: 230, 0x1e014: 230,
+	0x1e015: 230, 0x1e016: 230, 0x1e017: 230, 0x1e018: 230,
+	0x1e01b: 230, 0x1e01c: 230, 0x1e01d: 230, 0x1e01e: 230,
+	0x1e01f: 230, 0x1e020: 230, 0x1e021: 230, 0x1e023: 230,
+	0x1e024: 230, 0x1e026: 230, 0x1e027: 230, 0x1e028: 230,
+	0x1e029: 230, 0x1e02a: 230, 0x1e130: 230, 0x1e131: 230,
+	0x1e132: 230, 0x1e133: 230, 0x1e134: 230, 0x1e135: 230,
+	0x1e136: 230, 0x1e2ae: 230, 0x1e2ec: 230, 0x1e2ed: 230,
+	0x1e2ee: 230, 0x1e2ef: 230, 0x1e8d0: 220, 0x1e8d1: 220,
+	0x1e8d2: 220, 0x1e8d3: 220, 0x1e8d4: 220, 0x1e8d5: 220,
+	0x1e8d6: 220, 0x1e944: 230, 0x1e945: 230, 0x1e946: 230,
+	0x1e947: 230, 0x1e948: 230, 0x1e949: 230, 0x1e94a: 7,
+}
+
+// canonicalDecomposition holds full canonical decompositions
+var canonicalDecomposition = map[rune]string{
+	0xc0: "A\u0300", 0xc1: "A\u0301", 0xc2: "A\u0302", 0xc3: "A\u0303",
+	0xc4: "A\u0308", 0xc5: "A\u030a", 0xc7: "C\u0327", 0xc8: "E\u0300",
+	0xc9: "E\u0301", 0xca: "E\u0302", 0xcb: "E\u0308", 0xcc: "I\u0300",
+	0xcd: "I\u0301", 0xce: "I\u0302", 0xcf: "I\u0308", 0xd1: "N\u0303",
+	0xd2: "O\u0300", 0xd3: "O\u0301", 0xd4: "O\u0302", 0xd5: "O\u0303",
+	0xd6: "O\u0308", 0xd9: "U\u0300", 0xda: "U\u0301", 0xdb: "U\u0302",
+	0xdc: "U\u0308", 0xdd: "Y\u0301", 0xe0: "a\u0300", 0xe1: "a\u0301",
+	0xe2: "a\u0302", 0xe3: "a\u0303", 0xe4: "a\u0308", 0xe5: "a\u030a",
+	0xe7: "c\u0327", 0xe8: "e\u0300", 0xe9: "e\u0301", 0xea: "e\u0302",
+	0xeb: "e\u0308", 0xec: "i\u0300", 0xed: "i\u0301", 0xee: "i\u0302",
+	0xef: "i\u0308", 0xf1: "n\u0303", 0xf2: "o\u0300", 0xf3: "o\u0301",
+	0xf4: "o\u0302", 0xf5: "o\u0303", 0xf6: "o\u0308", 0xf9: "u\u0300",
+	0xfa: "u\u0301", 0xfb: "u\u0302", 0xfc: "u\u0308", 0xfd: "y\u0301",
+	0xff: "y\u0308", 0x100: "A\u0304", 0x101: "a\u0304", 0x102: "A\u0306",
+	0x103: "a\u0306", 0x104: "A\u0328", 0x105: "a\u0328", 0x106: "C\u0301",
+	0x107: "c\u0301", 0x108: "C\u0302", 0x109: "c\u0302", 0x10a: "C\u0307",
+	0x10b: "c\u0307", 0x10c: "C\u030c", 0x10d: "c\u030c", 0x10e: "D\u030c",
+	0x10f: "d\u030c", 0x112: "E\u0304", 0x113: "e\u0304", 0x114: "E\u0306",
+	0x115: "e\u0306", 0x116: "E\u0307", 0x117: "e\u0307", 0x118: "E\u0328",
+	0x119: "e\u0328", 0x11a: "E\u030c", 0x11b: "e\u030c", 0x11c: "G\u0302",
+	0x11d: "g\u0302", 0x11e: "G\u0306", 0x11f: "g\u0306", 0x120: "G\u0307",
+	0x121: "g\u0307", 0x122: "G\u0327", 0x123: "g\u0327", 0x124: "H\u0302",
+	0x125: "h\u0302", 0x128: "I\u0303", 0x129: "i\u0303", 0x12a: "I\u0304",
+	0x12b: "i\u0304", 0x12c: "I\u0306", 0x12d: "i\u0306", 0x12e: "I\u0328",
+	0x12f: "i\u0328", 0x130: "I\u0307", 0x134: "J\u0302", 0x135: "j\u0302",
+	0x136: "K\u0327", 0x137: "k\u0327", 0x139: "L\u0301", 0x13a: "l\u0301",
+	0x13b: "L\u0327", 0x13c: "l\u0327", 0x13d: "L\u030c", 0x13e: "l\u030c",
+	0x143: "N\u0301", 0x144: "n\u0301", 0x145: "N\u0327", 0x146: "n\u0327",
+	0x147: "N\u030c", 0x148: "n\u030c", 0x14c: "O\u0304", 0x14d: "o\u0304",
+	0x14e: "O\u0306", 0x14f: "o\u0306", 0x150: "O\u030b", 0x151: "o\u030b",
+	0x154: "R\u0301", 0x155: "r\u0301", 0x156: "R\u0327", 0x157: "r\u0327",
+	0x158: "R\u030c", 0x159: "r\u030c", 0x15a: "S\u0301", 0x15b: "s\u0301",
+	0x15c: "S\u0302", 0x15d: "s\u0302", 0x15e: "S\u0327", 0x15f: "s\u0327",
+	0x160: "S\u030c", 0x161: "s\u030c", 0x162: "T\u0327", 0x163: "t\u0327",
+	0x164: "T\u030c", 0x165: "t\u030c", 0x168: "U\u0303", 0x169: "u\u0303",
+	0x16a: "U\u0304", 0x16b: "u\u0304", 0x16c: "U\u0306", 0x16d: "u\u0306",
+	0x16e: "U\u030a", 0x16f: "u\u030a", 0x170: "U\u030b", 0x171: "u\u030b",
+	0x172: "U\u0328", 0x173: "u\u0328", 0x174: "W\u0302", 0x175: "w\u0302",
+	0x176: "Y\u0302", 0x177: "y\u0302", 0x178: "Y\u0308", 0x179: "Z\u0301",
+	0x17a: "z\u0301", 0x17b: "Z\u0307", 0x17c: "z\u0307", 0x17d: "Z\u030c",
+	0x17e: "z\u030c", 0x1a0: "O\u031b", 0x1a1: "o\u031b", 0x1af: "U\u031b",
+	0x1b0: "u\u031b", 0x1cd: "A\u030c", 0x1ce: "a\u030c", 0x1cf: "I\u030c",
+	0x1d0: "i\u030c", 0x1d1: "O\u030c", 0x1d2: "o\u030c", 0x1d3: "U\u030c",
+	0x1d4: "u\u030c", 0x1d5: "U\u0308\u0304", 0x1d6: "u\u0308\u0304", 0x1d7: "U\u0308\u0301",
+	0x1d8: "u\u0308\u0301", 0x1d9: "U\u0308\u030c", 0x1da: "u\u0308\u030c", 0x1db: "U\u0308\u0300",
+	0x1dc: "u\u0308\u0300", 0x1de: "A\u0308\u0304", 0x1df: "a\u0308\u0304", 0x1e0: "A\u0307\u0304",
+	0x1e1: "a\u0307\u0304", 0x1e2: "\u00c6\u0304", 0x1e3: "\u00e6\u0304", 0x1e6: "G\u030c",
+	0x1e7: "g\u030c", 0x1e8: "K\u030c", 0x1e9: "k\u030c", 0x1ea: "O\u0328",
+	0x1eb: "o\u0328", 0x1ec: "O\u0328\u0304", 0x1ed: "o\u0328\u0304", 0x1ee: "\u01b7\u030c",
+	0x1ef: "\u0292\u030c", 0x1f0: "j\u030c", 0x1f4: "G\u0301", 0x1f5: "g\u0301",
+	0x1f8: "N\u0300", 0x1f9: "n\u0300", 0x1fa: "A\u030a\u0301", 0x1fb: "a\u030a\u0301",
+	0x1fc: "\u00c6\u0301", 0x1fd: "\u00e6\u0301", 0x1fe: "\u00d8\u0301", 0x1ff: "\u00f8\u0301",
+	0x200: "A\u030f", 0x201: "a\u030f", 0x202: "A\u0311", 0x203: "a\u0311",
+	0x204: "E\u030f", 0x205: "e\u030f", 0x206: "E\u0311", 0x207: "e\u0311",
+	0x208: "I\u030f", 0x209: "i\u030f", 0x20a: "I\u0311", 0x20b: "i\u0311",
+	0x20c: "O\u030f", 0x20d: "o\u030f", 0x20e: "O\u0311", 0x20f: "o\u0311",
+	0x210: "R\u030f", 0x211: "r\u030f", 0x212: "R\u0311", 0x213: "r\u0311",
+	0x214: "U\u030f", 0x215: "u\u030f", 0x216: "U\u0311", 0x217: "u\u0311",
+	0x218: "S\u0326", 0x219: "s\u0326", 0x21a: "T\u0326", 0x21b: "t\u0326",
+	0x21e: "H\u030c", 0x21f: "h\u030c", 0x226: "A\u0307", 0x227: "a\u0307",
+	0x228: "E\u0327", 0x229: "e\u0327", 0x22a: "O\u0308\u0304", 0x22b: "o\u0308\u0304",
+	0x22c: "O\u0303\u0304", 0x22d: "o\u0303\u0304", 0x22e: "O\u0307", 0x22f: "o\u0307",
+	0x230: "O\u0307\u0304", 0x231: "o\u0307\u0304", 0x232: "Y\u0304", 0x233: "y\u0304",
+	0x340: "\u0300", 0x341: "\u0301", 0x343: "\u0313", 0x344: "\u0308\u0301",
+	0x374: "\u02b9", 0x37e: ";", 0x385: "\u00a8\u0301", 0x386: "\u0391\u0301",
+	0x387: "\u00b7", 0x388: "\u0395\u0301", 0x389: "\u0397\u0301", 0x38a: "\u0399\u0301",
+	0x38c: "\u039f\u0301", 0x38e: "\u03a5\u0301", 0x38f: "\u03a9\u0301", 0x390: "\u03b9\u0308\u0301",
+	0x3aa: "\u0399\u0308", 0x3ab: "\u03a5\u0308", 0x3ac: "\u03b1\u0301", 0x3ad: "\u03b5\u0301",
+	0x3ae: "\u03b7\u0301", 0x3af: "\u03b9\u0301", 0x3b0: "\u03c5\u0308\u0301", 0x3ca: "\u03b9\u0308",
+	0x3cb: "\u03c5\u0308", 0x3cc: "\u03bf\u0301", 0x3cd: "\u03c5\u0301", 0x3ce: "\u03c9\u0301",
+	0x3d3: "\u03d2\u0301", 0x3d4: "\u03d2\u0308", 0x400: "\u0415\u0300", 0x401: "\u0415\u0308",
+	0x403: "\u0413\u0301", 0x407: "\u0406\u0308", 0x40c: "\u041a\u0301", 0x40d: "\u0418\u0300",
+	0x40e: "\u0423\u0306", 0x419: "\u0418\u0306", 0x439: "\u0438\u0306", 0x450: "\u0435\u0300",
+	0x451: "\u0435\u0308", 0x453: "\u0433\u0301", 0x457: "\u0456\u0308", 0x45c: "\u043a\u0301",
+	0x45d: "\u0438\u0300", 0x45e: "\u0443\u0306", 0x476: "\u0474\u030f", 0x477: "\u0475\u030f",
+	0x4c1: "\u0416\u0306", 0x4c2: "\u0436\u0306", 0x4d0: "\u0410\u0306", 0x4d1: "\u0430\u0306",
+	0x4d2: "\u0410\u0308", 0x4d3: "\u0430\u0308", 0x4d6: "\u0415\u0306", 0x4d7: "\u0435\u0306",
+	0x4da: "\u04d8\u0308", 0x4db: "\u04d9\u0308", 0x4dc: "\u0416\u0308", 0x4dd: "\u0436\u0308",
+	0x4de: "\u0417\u0308", 0x4df: "\u0437\u0308", 0x4e2: "\u0418\u0304", 0x4e3: "\u0438\u0304",
+	0x4e4: "\u0418\u0308", 0x4e5: "\u0438\u0308", 0x4e6: "\u041e\u0308", 0x4e7: "\u043e\u0308",
+	0x4ea: "\u04e8\u0308", 0x4eb: "\u04e9\u0308", 0x4ec: "\u042d\u0308", 0x4ed: "\u044d\u0308",
+	0x4ee: "\u0423\u0304", 0x4ef: "\u0443\u0304", 0x4f0: "\u0423\u0308", 0x4f1: "\u0443\u0308",
+	0x4f2: "\u0423\u030b", 0x4f3: "\u0443\u030b", 0x4f4: "\u0427\u0308", 0x4f5: "\u0447\u0308",
+	0x4f8: "\u042b\u0308", 0x4f9: "\u044b\u0308", 0x622: "\u0627\u0653", 0x623: "\u0627\u0654",
+	0x624: "\u0648\u0654", 0x625: "\u0627\u0655", 0x626: "\u064a\u0654", 0x6c0: "\u06d5\u0654",
+	0x6c2: "\u06c1\u0654", 0x6d3: "\u06d2\u0654", 0x929: "\u0928\u093c", 0x931: "\u0930\u093c",
+	0x934: "\u0933\u093c", 0x958: "\u0915\u093c", 0x959: "\u0916\u093c", 0x95a: "\u0917\u093c",
+	0x95b: "\u091c\u093c", 0x95c: "\u0921\u093c", 0x95d: "\u0922\u093c", 0x95e: "\u092b\u093c",
+	0x95f: "\u092f\u093c", 0x9cb: "\u09c7\u09be", 0x9cc: "\u09c7\u09d7", 0x9dc: "\u09a1\u09bc",
+	0x9dd: "\u09a2\u09bc", 0x9df: "\u09af\u09bc", 0xa33: "\u0a32\u0a3c", 0xa36: "\u0a38\u0a3c",
+	0xa59: "\u0a16\u0a3c", 0xa5a: "\u0a17\u0a3c", 0xa5b: "\u0a1c\u0a3c", 0xa5e: "\u0a2b\u0a3c",
+	0xb48: "\u0b47\u0b56", 0xb4b: "\u0b47\u0b3e", 0xb4c: "\u0b47\u0b57", 0xb5c: "\u0b21\u0b3c",
+	0xb5d: "\u0b22\u0b3c", 0xb94: "\u0b92\u0bd7", 0xbca: "\u0bc6\u0bbe", 0xbcb: "\u0bc7\u0bbe",
+	0xbcc: "\u0bc6\u0bd7", 0xc48: "\u0c46\u0c56", 0xcc0: "\u0cbf\u0cd5", 0xcc7: "\u0cc6\u0cd5",
+	0xcc8: "\u0cc6\u0cd6", 0xcca: "\u0cc6\u0cc2", 0xccb: "\u0cc6\u0cc2\u0cd5", 0xd4a: "\u0d46\u0d3e",
+	0xd4b: "\u0d47\u0d3e", 0xd4c: "\u0d46\u0d57", 0xdda: "\u0dd9\u0dca", 0xddc: "\u0dd9\u0dcf",
+	0xddd: "\u0dd9\u0dcf\u0dca", 0xdde: "\u0dd9\u0ddf", 0xf43: "\u0f42\u0fb7", 0xf4d: "\u0f4c\u0fb7",
+	0xf52: "\u0f51\u0fb7", 0xf57: "\u0f56\u0fb7", 0xf5c: "\u0f5b\u0fb7", 0xf69: "\u0f40\u0fb5",
+	0xf73: "\u0f71\u0f72", 0xf75: "\u0f71\u0f74", 0xf76: "\u0fb2\u0f80", 0xf78: "\u0fb3\u0f80",
+	0xf81: "\u0f71\u0f80", 0xf93: "\u0f92\u0fb7", 0xf9d: "\u0f9c\u0fb7", 0xfa2: "\u0fa1\u0fb7",
+	0xfa7: "\u0fa6\u0fb7", 0xfac: "\u0fab\u0fb7", 0xfb9: "\u0f90\u0fb5", 0x1026: "\u1025\u102e",
+	0x1b06: "\u1b05\u1b35", 0x1b08: "\u1b07\u1b35", 0x1b0a: "\u1b09\u1b35", 0x1b0c: "\u1b0b\u1b35",
+	0x1b0e: "\u1b0d\u1b35", 0x1b12: "\u1b11\u1b35", 0x1b3b: "\u1b3a\u1b35", 0x1b3d: "\u1b3c\u1b35",
+	0x1b40: "\u1b3e\u1b35", 0x1b41: "\u1b3f\u1b35", 0x1b43: "\u1b42\u1b35", 0x1e00: "A\u0325",
+	0x1e01: "a\u0325", 0x1e02: "B\u0307", 0x1e03: "b\u0307", 0x1e04: "B\u0323",
+	0x1e05: "b\u0323", 0x1e06: "B\u0331", 0x1e07: "b\u0331", 0x1e08: "C\u0327\u0301",
+	0x1e09: "c\u0327\u0301", 0x1e0a: "D\u0307", 0x1e0b: "d\u0307", 0x1e0c: "D\u0323",
+	0x1e0d: "d\u0323", 0x1e0e: "D\u0331", 0x1e0f: "d\u0331", 0x1e10: "D\u0327",
+	0x1e11: "d\u0327", 0x1e12: "D\u032d", 0x1e13: "d\u032d", 0x1e14: "E\u0304\u0300",
+	0x1e15: "e\u0304\u0300", 0x1e16: "E\u0304\u0301", 0x1e17: "e\u0304\u0301", 0x1e18: "E\u032d",
+	0x1e19: "e\u032d", 0x1e1a: "E\u0330", 0x1e1b: "e\u0330", 0x1e1c: "E\u0327\u0306",
+	0x1e1d: "e\u0327\u0306", 0x1e1e: "F\u0307", 0x1e1f: "f\u0307", 0x1e20: "G\u0304",
+	0x1e21: "g\u0304", 0x1e22: "H\u0307", 0x1e23: "h\u0307", 0x1e24: "H\u0323",
+	0x1e25: "h\u0323", 0x1e26: "H\u0308", 0x1e27: "h\u0308", 0x1e28: "H\u0327",
+	0x1e29: "h\u0327", 0x1e2a: "H\u032e", 0x1e2b: "h\u032e", 0x1e2c: "I\u0330",
+	0x1e2d: "i\u0330", 0x1e2e: "I\u0308\u0301", 0x1e2f: "i\u0308\u0301", 0x1e30: "K\u0301",
+	0x1e31: "k\u0301", 0x1e32: "K\u0323", 0x1e33: "k\u0323", 0x1e34: "K\u0331",
+	0x1e35: "k\u0331", 0x1e36: "L\u0323", 0x1e37: "l\u0323", 0x1e38: "L\u0323\u0304",
+	0x1e39: "l\u0323\u0304", 0x1e3a: "L\u0331", 0x1e3b: "l\u0331", 0x1e3c: "L\u032d",
+	0x1e3d: "l\u032d", 0x1e3e: "M\u0301", 0x1e3f: "m\u0301", 0x1e40: "M\u0307",
+	0x1e41: "m\u0307", 0x1e42: "M\u0323", 0x1e43: "m\u0323", 0x1e44: "N\u0307",
+	0x1e45: "n\u0307", 0x1e46: "N\u0323", 0x1e47: "n\u0323", 0x1e48: "N\u0331",
+	0x1e49: "n\u0331", 0x1e4a: "N\u032d", 0x1e4b: "n\u032d", 0x1e4c: "O\u0303\u0301",
+	0x1e4d: "o\u0303\u0301", 0x1e4e: "O\u0303\u0308", 0x1e4f: "o\u0303\u0308", 0x1e50: "O\u0304\u0300",
+	0x1e51: "o\u0304\u0300", 0x1e52: "O\u0304\u0301", 0x1e53: "o\u0304\u0301", 0x1e54: "P\u0301",
+	0x1e55: "p\u0301", 0x1e56: "P\u0307", 0x1e57: "p\u0307", 0x1e58: "R\u0307",
+	0x1e59: "r\u0307", 0x1e5a: "R\u0323", 0x1e5b: "r\u0323", 0x1e5c: "R\u0323\u0304",
+	0x1e5d: "r\u0323\u0304", 0x1e5e: "R\u0331", 0x1e5f: "r\u0331", 0x1e60: "S\u0307",
+	0x1e61: "s\u0307", 0x1e62: "S\u0323", 0x1e63: "s\u0323", 0x1e64: "S\u0301\u0307",
+	0x1e65: "s\u0301\u0307", 0x1e66: "S\u030c\u0307", 0x1e67: "s\u030c\u0307", 0x1e68: "S\u0323\u0307",
+	0x1e69: "s\u0323\u0307", 0x1e6a: "T\u0307", 0x1e6b: "t\u0307", 0x1e6c: "T\u0323",
+	0x1e6d: "t\u0323", 0x1e6e: "T\u0331", 0x1e6f: "t\u0331", 0x1e70: "T\u032d",
+	0x1e71: "t\u032d", 0x1e72: "U\u0324", 0x1e73: "u\u0324", 0x1e74: "U\u0330",
+	0x1e75: "u\u0330", 0x1e76: "U\u032d", 0x1e77: "u\u032d", 0x1e78: "U\u0303\u0301",
+	0x1e79: "u\u0303\u0301", 0x1e7a: "U\u0304\u0308", 0x1e7b: "u\u0304\u0308", 0x1e7c: "V\u0303",
+	0x1e7d: "v\u0303", 0x1e7e: "V\u0323", 0x1e7f: "v\u0323", 0x1e80: "W\u0300",
+	0x1e81: "w\u0300", 0x1e82: "W\u0301", 0x1e83: "w\u0301", 0x1e84: "W\u0308",
+	0x1e85: "w\u0308", 0x1e86: "W\u0307", 0x1e87: "w\u0307", 0x1e88: "W\u0323",
+	0x1e89: "w\u0323", 0x1e8a: "X\u0307", 0x1e8b: "x\u0307", 0x1e8c: "X\u0308",
+	0x1e8d: "x\u0308", 0x1e8e: "Y\u0307", 0x1e8f: "y\u0307", 0x1e90: "Z\u0302",
+	0x1e91: "z\u0302", 0x1e92: "Z\u0323", 0x1e93: "z\u0323", 0x1e94: "Z\u0331",
+	0x1e95: "z\u0331", 0x1e96: "h\u0331", 0x1e97: "t\u0308", 0x1e98: "w\u030a",
+	0x1e99: "y\u030a", 0x1e9b: "\u017f\u0307", 0x1ea0: "A\u0323", 0x1ea1: "a\u0323",
+	0x1ea2: "A\u0309", 0x1ea3: "a\u0309", 0x1ea4: "A\u0302\u0301", 0x1ea5: "a\u0302\u0301",
+	0x1ea6: "A\u0302\u0300", 0x1ea7: "a\u0302\u0300", 0x1ea8: "A\u0302\u0309", 0x1ea9: "a\u0302\u0309",
+	0x1eaa: "A\u0302\u0303", 0x1eab: "a\u0302\u0303", 0x1eac: "A\u0323\u0302", 0x1ead: "a\u0323\u0302",
+	0x1eae: "A\u0306\u0301", 0x1eaf: "a\u0306\u0301", 0x1eb0: "A\u0306\u0300", 0x1eb1: "a\u0306\u0300",
+	0x1eb2: "A\u0306\u0309", 0x1eb3: "a\u0306\u0309", 0x1eb4: "A\u0306\u0303", 0x1eb5: "a\u0306\u0303",
+	0x1eb6: "A\u0323\u0306", 0x1eb7: "a\u0323\u0306", 0x1eb8: "E\u0323", 0x1eb9: "e\u0323",
+	0x1eba: "E\u0309", 0x1ebb: "e\u0309", 0x1ebc: "E\u0303", 0x1ebd: "e\u0303",
+	0x1ebe: "E\u0302\u0301", 0x1ebf: "e\u0302\u0301", 0x1ec0: "E\u0302\u0300", 0x1ec1: "e\u0302\u0300",
+	0x1ec2: "E\u0302\u0309", 0x1ec3: "e\u0302\u0309", 0x1ec4: "E\u0302\u0303", 0x1ec5: "e\u0302\u0303",
+	0x1ec6: "E\u0323\u0302", 0x1ec7: "e\u0323\u0302", 0x1ec8: "I\u0309", 0x1ec9: "i\u0309",
+	0x1eca: "I\u0323", 0x1ecb: "i\u0323", 0x1ecc: "O\u0323", 0x1ecd: "o\u0323",
+	0x1ece: "O\u0309", 0x1ecf: "o\u0309", 0x1ed0: "O\u0302\u0301", 0x1ed1: "o\u0302\u0301",
+	0x1ed2: "O\u0302\u0300", 0x1ed3: "o\u0302\u0300", 0x1ed4: "O\u0302\u0309", 0x1ed5: "o\u0302\u0309",
+	0x1ed6: "O\u0302\u0303", 0x1ed7: "o\u0302\u0303", 0x1ed8: "O\u0323\u0302", 0x1ed9: "o\u0323\u0302",
+	0x1eda: "O\u031b\u0301", 0x1edb: "o\u031b\u0301", 0x1edc: "O\u031b\u0300", 0x1edd: "o\u031b\u0300",
+	0x1ede: "O\u031b\u0309", 0x1edf: "o\u031b\u0309", 0x1ee0: "O\u031b\u0303", 0x1ee1: "o\u031b\u0303",
+	0x1ee2: "O\u031b\u0323", 0x1ee3: "o\u031b\u0323", 0x1ee4: "U\u0323", 0x1ee5: "u\u0323",
+	0x1ee6: "U\u0309", 0x1ee7: "u\u0309", 0x1ee8: "U\u031b\u0301", 0x1ee9: "u\u031b\u0301",
+	0x1eea: "U\u031b\u0300", 0x1eeb: "u\u031b\u0300", 0x1eec: "U\u031b\u0309", 0x1eed: "u\u031b\u0309",
+	0x1eee: "U\u031b\u0303", 0x1eef: "u\u031b\u0303", 0x1ef0: "U\u031b\u0323", 0x1ef1: "u\u031b\u0323",
+	0x1ef2: "Y\u0300", 0x1ef3: "y\u0300", 0x1ef4: "Y\u0323", 0x1ef5: "y\u0323",
+	0x1ef6: "Y\u0309", 0x1ef7: "y\u0309", 0x1ef8: "Y\u0303", 0x1ef9: "y\u0303",
+	0x1f00: "\u03b1\u0313", 0x1f01: "\u03b1\u0314", 0x1f02: "\u03b1\u0313\u0300", 0x1f03: "\u03b1\u0314\u0300",
+	0x1f04: "\u03b1\u0313\u0301", 0x1f05: "\u03b1\u0314\u0301", 0x1f06: "\u03b1\u0313\u0342", 0x1f07: "\u03b1\u0314\u0342",
+	0x1f08: "\u0391\u0313", 0x1f09: "\u0391\u0314", 0x1f0a: "\u0391\u0313\u0300", 0x1f0b: "\u0391\u0314\u0300",
+	0x1f0c: "\u0391\u0313\u0301", 0x1f0d: "\u0391\u0314\u0301", 0x1f0e: "\u0391\u0313\u0342", 0x1f0f: "\u0391\u0314\u0342",
+	0x1f10: "\u03b5\u0313", 0x1f11: "\u03b5\u0314", 0x1f12: "\u03b5\u0313\u0300", 0x1f13: "\u03b5\u0314\u0300",
+	0x1f14: "\u03b5\u0313\u0301", 0x1f15: "\u03b5\u0314\u0301", 0x1f18: "\u0395\u0313", 0x1f19: "\u0395\u0314",
+	0x1f1a: "\u0395\u0313\u0300", 0x1f1b: "\u0395\u0314\u0300", 0x1f1c: "\u0395\u0313\u0301", 0x1f1d: "\u0395\u0314\u0301",
+	0x1f20: "\u03b7\u0313", 0x1f21: "\u03b7\u0314", 0x1f22: "\u03b7\u0313\u0300", 0x1f23: "\u03b7\u0314\u0300",
+	0x1f24: "\u03b7\u0313\u0301", 0x1f25: "\u03b7\u0314\u0301", 0x1f26: "\u03b7\u0313\u0342", 0x1f27: "\u03b7\u0314\u0342",
+	0x1f28: "\u0397\u0313", 0x1f29: "\u0397\u0314", 0x1f2a: "\u0397\u0313\u0300", 0x1f2b: "\u0397\u0314\u0300",
+	0x1f2c: "\u0397\u0313\u0301", 0x1f2d: "\u0397\u0314\u0301", 0x1f2e: "\u0397\u0313\u0342", 0x1f2f: "\u0397\u0314\u0342",
+	0x1f30: "\u03b9\u0313", 0x1f31: "\u03b9\u0314", 0x1f32: "\u03b9\u0313\u0300", 0x1f33: "\u03b9\u0314\u0300",
+	0x1f34: "\u03b9\u0313\u0301", 0x1f35: "\u03b9\u0314\u0301", 0x1f36: "\u03b9\u0313\u0342", 0x1f37: "\u03b9\u0314\u0342",
+	0x1f38: "\u0399\u0313", 0x1f39: "\u0399\u0314", 0x1f3a: "\u0399\u0313\u0300", 0x1f3b: "\u0399\u0314\u0300",
+	0x1f3c: "\u0399\u0313\u0301", 0x1f3d: "\u0399\u0314\u0301", 0x1f3e: "\u0399\u0313\u0342", 0x1f3f: "\u0399\u0314\u0342",
+	0x1f40: "\u03bf\u0313", 0x1f41: "\u03bf\u0314", 0x1f42: "\u03bf\u0313\u0300", 0x1f43: "\u03bf\u0314\u0300",
+	0x1f44: "\u03bf\u0313\u0301", 0x1f45: "\u03bf\u0314\u0301", 0x1f48: "\u039f\u0313", 0x1f49: "\u039f\u0314",
+	0x1f4a: "\u039f\u0313\u0300", 0x1f4b: "\u039f\u0314\u0300", 0x1f4c: "\u039f\u0313\u0301", 0x1f4d: "\u039f\u0314\u0301",
+	0x1f50: "\u03c5\u0313", 0x1f51: "\u03c5\u0314", 0x1f52: "\u03c5\u0313\u0300", 0x1f53: "\u03c5\u0314\u0300",
+	0x1f54: "\u03c5\u0313\u0301", 0x1f55: "\u03c5\u0314\u0301", 0x1f56: "\u03c5\u0313\u0342", 0x1f57: "\u03c5\u0314\u0342",
+	0x1f59: "\u03a5\u0314", 0x1f5b: "\u03a5\u0314\u0300", 0x1f5d: "\u03a5\u0314\u0301", 0x1f5f: "\u03a5\u0314\u0342",
+	0x1f60: "\u03c9\u0313", 0x1f61: "\u03c9\u0314", 0x1f62: "\u03c9\u0313\u0300", 0x1f63: "\u03c9\u0314\u0300",
+	0x1f64: "\u03c9\u0313\u0301", 0x1f65: "\u03c9\u0314\u0301", 0x1f66: "\u03c9\u0313\u0342", 0x1f67: "\u03c9\u0314\u0342",
+	0x1f68: "\u03a9\u0313", 0x1f69: "\u03a9\u0314", 0x1f6a: "\u03a9\u0313\u0300", 0x1f6b: "\u03a9\u0314\u0300",
+	0x1f6c: "\u03a9\u0313\u0301", 0x1f6d: "\u03a9\u0314\u0301", 0x1f6e: "\u03a9\u0313\u0342", 0x1f6f: "\u03a9\u0314\u0342",
+	0x1f70: "\u03b1\u0300", 0x1f71: "\u03b1\u0301", 0x1f72: "\u03b5\u0300", 0x1f73: "\u03b5\u0301",
+	0x1f74: "\u03b7\u0300", 0x1f75: "\u03b7\u0301", 0x1f76: "\u03b9\u0300", 0x1f77: "\u03b9\u0301",
+	0x1f78: "\u03bf\u0300", 0x1f79: "\u03bf\u0301", 0x1f7a: "\u03c5\u0300", 0x1f7b: "\u03c5\u0301",
+	0x1f7c: "\u03c9\u0300", 0x1f7d: "\u03c9\u0301", 0x1f80: "\u03b1\u0313\u0345", 0x1f81: "\u03b1\u0314\u0345",
+	0x1f82: "\u03b1\u0313\u0300\u0345", 0x1f83: "\u03b1\u0314\u0300\u0345", 0x1f84: "\u03b1\u0313\u0301\u0345", 0x1f85: "\u03b1\u0314\u0301\u0345",
+	0x1f86: "\u03b1\u0313\u0342\u0345", 0x1f87: "\u03b1\u0314\u0342\u0345", 0x1f88: "\u0391\u0313\u0345", 0x1f89: "\u0391\u0314\u0345",
+	0x1f8a: "\u0391\u0313\u0300\u0345", 0x1f8b: "\u0391\u0314\u0300\u0345", 0x1f8c: "\u0391\u0313\u0301\u0345", 0x1f8d: "\u0391\u0314\u0301\u0345",
+	0x1f8e: "\u0391\u0313\u0342\u0345", 0x1f8f: "\u0391\u0314\u0342\u0345", 0x1f90: "\u03b7\u0313\u0345", 0x1f91: "\u03b7\u0314\u0345",
+	0x1f92: "\u03b7\u0313\u0300\u0345", 0x1f93: "\u03b7\u0314\u0300\u0345", 0x1f94: "\u03b7\u0313\u0301\u0345", 0x1f95: "\u03b7\u0314\u0301\u0345",
+	0x1f96: "\u03b7\u0313\u0342\u0345", 0x1f97: "\u03b7\u0314\u0342\u0345", 0x1f98: "\u0397\u0313\u0345", 0x1f99: "\u0397\u0314\u0345",
+	0x1f9a: "\u0397\u0313\u0300\u0345", 0x1f9b: "\u0397\u0314\u0300\u0345", 0x1f9c: "\u0397\u0313\u0301\u0345", 0x1f9d: "\u0397\u0314\u0301\u0345",
+	0x1f9e: "\u0397\u0313\u0342\u0345", 0x1f9f: "\u0397\u0314\u0342\u0345", 0x1fa0: "\u03c9\u0313\u0345", 0x1fa1: "\u03c9\u0314\u0345",
+	0x1fa2: "\u03c9\u0313\u0300\u0345", 0x1fa3: "\u03c9\u0314\u0300\u0345", 0x1fa4: "\u03c9\u0313\u0301\u0345", 0x1fa5: "\u03c9\u0314\u0301\u0345",
+	0x1fa6: "\u03c9\u0313\u0342\u0345", 0x1fa7: "\u03c9\u0314\u0342\u0345", 0x1fa8: "\u03a9\u0313\u0345", 0x1fa9: "\u03a9\u0314\u0345",
+	0x1faa: "\u03a9\u0313\u0300\u0345", 0x1fab: "\u03a9\u0314\u0300\u0345", 0x1fac: "\u03a9\u0313\u0301\u0345", 0x1fad: "\u03a9\u0314\u0301\u0345",
+	0x1fae: "\u03a9\u0313\u0342\u0345", 0x1faf: "\u03a9\u0314\u0342\u0345", 0x1fb0: "\u03b1\u0306", 0x1fb1: "\u03b1\u0304",
+	0x1fb2: "\u03b1\u0300\u0345", 0x1fb3: "\u03b1\u0345", 0x1fb4: "\u03b1\u0301\u0345", 0x1fb6: "\u03b1\u0342",
+	0x1fb7: "\u03b1\u0342\u0345", 0x1fb8: "\u0391\u0306", 0x1fb9: "\u0391\u0304", 0x1fba: "\u0391\u0300",
+	0x1fbb: "\u0391\u0301", 0x1fbc: "\u0391\u0345", 0x1fbe: "\u03b9", 0x1fc1: "\u00a8\u0342",
+	0x1fc2: "\u03b7\u0300\u0345", 0x1fc3: "\u03b7\u0345", 0x1fc4: "\u03b7\u0301\u0345", 0x1fc6: "\u03b7\u0342",
+	0x1fc7: "\u03b7\u0342\u0345", 0x1fc8: "\u0395\u0300", 0x1fc9: "\u0395\u0301", 0x1fca: "\u0397\u0300",
+	0x1fcb: "\u0397\u0301", 0x1fcc: "\u0397\u0345", 0x1fcd: "\u1fbf\u0300", 0x1fce: "\u1fbf\u0301",
+	0x1fcf: "\u1fbf\u0342", 0x1fd0: "\u03b9\u0306", 0x1fd1: "\u03b9\u0304", 0x1fd2: "\u03b9\u0308\u0300",
+	0x1fd3: "\u03b9\u0308\u0301", 0x1fd6: "\u03b9\u0342", 0x1fd7: "\u03b9\u0308\u0342", 0x1fd8: "\u0399\u0306",
+	0x1fd9: "\u0399\u0304", 0x1fda: "\u0399\u0300", 0x1fdb: "\u0399\u0301", 0x1fdd: "\u1ffe\u0300",
+	0x1fde: "\u1ffe\u0301", 0x1fdf: "\u1ffe\u0342", 0x1fe0: "\u03c5\u0306", 0x1fe1: "\u03c5\u0304",
+	0x1fe2: "\u03c5\u0308\u0300", 0x1fe3: "\u03c5\u0308\u0301", 0x1fe4: "\u03c1\u0313", 0x1fe5: "\u03c1\u0314",
+	0x1fe6: "\u03c5\u0342", 0x1fe7: "\u03c5\u0308\u0342", 0x1fe8: "\u03a5\u0306", 0x1fe9: "\u03a5\u0304",
+	0x1fea: "\u03a5\u0300", 0x1feb: "\u03a5\u0301", 0x1fec: "\u03a1\u0314", 0x1fed: "\u00a8\u0300",
+	0x1fee: "\u00a8\u0301", 0x1fef: "`", 0x1ff2: "\u03c9\u0300\u0345", 0x1ff3: "\u03c9\u0345",
+	0x1ff4: "\u03c9\u0301\u0345", 0x1ff6: "\u03c9\u0342", 0x1ff7: "\u03c9\u0342\u0345", 0x1ff8: "\u039f\u0300",
+	0x1ff9: "\u039f\u0301", 0x1ffa: "\u03a9\u0300", 0x1ffb: "\u03a9\u0301", 0x1ffc: "\u03a9\u0345",
+	0x1ffd: "\u00b4", 0x2000: "\u2002", 0x2001: "\u2003", 0x2126: "\u03a9",
+	0x212a: "K", 0x212b: "A\u030a", 0x219a: "\u2190\u0338", 0x219b: "\u2192\u0338",
+	0x21ae: "\u2194\u0338", 0x21cd: "\u21d0\u0338", 0x21ce: "\u21d4\u0338", 0x21cf: "\u21d2\u0338",
+	0x2204: "\u2203\u0338", 0x2209: "\u2208\u0338", 0x220c: "\u220b\u0338", 0x2224: "\u2223\u0338",
+	0x2226: "\u2225\u0338", 0x2241: "\u223c\u0338", 0x2244: "\u2243\u0338", 0x2247: "\u2245\u0338",
+	0x2249: "\u2248\u0338", 0x2260: "=\u0338", 0x2262: "\u2261\u0338", 0x226d: "\u224d\u0338",
+	0x226e: "<\u0338", 0x226f: ">\u0338", 0x2270: "\u2264\u0338", 0x2271: "\u2265\u0338",
+	0x2274: "\u2272\u0338", 0x2275: "\u2273\u0338", 0x2278: "\u2276\u0338", 0x2279: "\u2277\u0338",
+	0x2280: "\u227a\u0338", 0x2281: "\u227b\u0338", 0x2284: "\u2282\u0338", 0x2285: "\u2283\u0338",
+	0x2288: "\u2286\u0338", 0x2289: "\u2287\u0338", 0x22ac: "\u22a2\u0338", 0x22ad: "\u22a8\u0338",
+	0x22ae: "\u22a9\u0338", 0x22af: "\u22ab\u0338", 0x22e0: "\u227c\u0338", 0x22e1: "\u227d\u0338",
+	0x22e2: "\u2291\u0338", 0x22e3: "\u2292\u0338", 0x22ea: "\u22b2\u0338", 0x22eb: "\u22b3\u0338",
+	0x22ec: "\u22b4\u0338", 0x22ed: "\u22b5\u0338", 0x2329: "\u3008", 0x232a: "\u3009",
+	0x2adc: "\u2add\u0338", 0x304c: "\u304b\u3099", 0x304e: "\u304d\u3099", 0x3050: "\u304f\u3099",
+	0x3052: "\u3051\u3099", 0x3054: "\u3053\u3099", 0x3056: "\u3055\u3099", 0x3058: "\u3057\u3099",
+	0x305a: "\u3059\u3099", 0x305c: "\u305b\u3099", 0x305e: "\u305d\u3099", 0x3060: "\u305f\u3099",
+	0x3062: "\u3061\u3099", 0x3065: "\u3064\u3099", 0x3067: "\u3066\u3099", 0x3069: "\u3068\u3099",
+	0x3070: "\u306f\u3099", 0x3071: "\u306f\u309a", 0x3073: "\u3072\u3099", 0x3074: "\u3072\u309a",
+	0x3076: "\u3075\u3099", 0x3077: "\u3075\u309a", 0x3079: "\u3078\u3099", 0x307a: "\u3078\u309a",
+	0x307c: "\u307b\u3099", 0x307d: "\u307b\u309a", 0x3094: "\u3046\u3099", 0x309e: "\u309d\u3099",
+	0x30ac: "\u30ab\u3099", 0x30ae: "\u30ad\u3099", 0x30b0: "\u30af\u3099", 0x30b2: "\u30b1\u3099",
+	0x30b4: "\u30b3\u3099", 0x30b6: "\u30b5\u3099", 0x30b8: "\u30b7\u3099", 0x30ba: "\u30b9\u3099",
+	0x30bc: "\u30bb\u3099", 0x30be: "\u30bd\u3099", 0x30c0: "\u30bf\u3099", 0x30c2: "\u30c1\u3099",
+	0x30c5: "\u30c4\u3099", 0x30c7: "\u30c6\u3099", 0x30c9: "\u30c8\u3099", 0x30d0: "\u30cf\u3099",
+	0x30d1: "\u30cf\u309a", 0x30d3: "\u30d2\u3099", 0x30d4: "\u30d2\u309a", 0x30d6: "\u30d5\u3099",
+	0x30d7: "\u30d5\u309a", 0x30d9: "\u30d8\u3099", 0x30da: "\u30d8\u309a", 0x30dc: "\u30db\u3099",
+	0x30dd: "\u30db\u309a", 0x30f4: "\u30a6\u3099", 0x30f7: "\u30ef\u3099", 0x30f8: "\u30f0\u3099",
+	0x30f9: "\u30f1\u3099", 0x30fa: "\u30f2\u3099", 0x30fe: "\u30fd\u3099", 0xf900: "\u8c48",
+	0xf901: "\u66f4", 0xf902: "\u8eca", 0xf903: "\u8cc8", 0xf904: "\u6ed1",
+	0xf905: "\u4e32", 0xf906: "\u53e5", 0xf907: "\u9f9c", 0xf908: "\u9f9c",
+	0xf909: "\u5951", 0xf90a: "\u91d1", 0xf90b: "\u5587", 0xf90c: "\u5948",
+	0xf90d: "\u61f6", 0xf90e: "\u7669", 0xf90f: "\u7f85", 0xf910: "\u863f",
+	0xf911: "\u87ba", 0xf912: "\u88f8", 0xf913: "\u908f", 0xf914: "\u6a02",
+	0xf915: "\u6d1b", 0xf916: "\u70d9", 0xf917: "\u73de", 0xf918: "\u843d",
+	0xf919: "\u916a", 0xf91a: "\u99f1", 0xf91b: "\u4e82", 0xf91c: "\u5375",
+	0xf91d: "\u6b04", 0xf91e: "\u721b", 0xf91f: "\u862d", 0xf920: "\u9e1e",
+	0xf921: "\u5d50", 0xf922: "\u6feb", 0xf923: "\u85cd", 0xf924: "\u8964",
+	0xf925: "\u62c9", 0xf926: "\u81d8", 0xf927: "\u881f", 0xf928: "\u5eca",
+	0xf929: "\u6717", 0xf92a: "\u6d6a", 0xf92b: "\u72fc", 0xf92c: "\u90ce",
+	0xf92d: "\u4f86", 0xf92e: "\u51b7", 0xf92f: "\u52de", 0xf930: "\u64c4",
+	0xf931: "\u6ad3", 0xf932: "\u7210", 0xf933: "\u76e7", 0xf934: "\u8001",
+	0xf935: "\u8606", 0xf936: "\u865c", 0xf937: "\u8def", 0xf938: "\u9732",
+	0xf939: "\u9b6f", 0xf93a: "\u9dfa", 0xf93b: "\u788c", 0xf93c: "\u797f",
+	0xf93d: "\u7da0", 0xf93e: "\u83c9", 0xf93f: "\u9304", 0xf940: "\u9e7f",
+	0xf941: "\u8ad6", 0xf942: "\u58df", 0xf943: "\u5f04", 0xf944: "\u7c60",
+	0xf945: "\u807e", 0xf946: "\u7262", 0xf947: "\u78ca", 0xf948: "\u8cc2",
+	0xf949: "\u96f7", 0xf94a: "\u58d8", 0xf94b: "\u5c62", 0xf94c: "\u6a13",
+	0xf94d: "\u6dda", 0xf94e: "\u6f0f", 0xf94f: "\u7d2f", 0xf950: "\u7e37",
+	0xf951: "\u964b", 0xf952: "\u52d2", 0xf953: "\u808b", 0xf954: "\u51dc",
+	0xf955: "\u51cc", 0xf956: "\u7a1c", 0xf957: "\u7dbe", 0xf958: "\u83f1",
+	0xf959: "\u9675", 0xf95a: "\u8b80", 0xf95b: "\u62cf", 0xf95c: "\u6a02",
+	0xf95d: "\u8afe", 0xf95e: "\u4e39", 0xf95f: "\u5be7", 0xf960: "\u6012",
+	0xf961: "\u7387", 0xf962: "\u7570", 0xf963: "\u5317", 0xf964: "\u78fb",
+	0xf965: "\u4fbf", 0xf966: "\u5fa9", 0xf967: "\u4e0d", 0xf968: "\u6ccc",
+	0xf969: "\u6578", 0xf96a: "\u7d22", 0xf96b: "\u53c3", 0xf96c: "\u585e",
+	0xf96d: "\u7701", 0xf96e: "\u8449", 0xf96f: "\u8aaa", 0xf970: "\u6bba",
+	0xf971: "\u8fb0", 0xf972: "\u6c88", 0xf973: "\u62fe", 0xf974: "\u82e5",
+	0xf975: "\u63a0", 0xf976: "\u7565", 0xf977: "\u4eae", 0xf978: "\u5169",
+	0xf979: "\u51c9", 0xf97a: "\u6881", 0xf97b: "\u7ce7", 0xf97c: "\u826f",
+	0xf97d: "\u8ad2", 0xf97e: "\u91cf", 0xf97f: "\u52f5", 0xf980: "\u5442",
+	0xf981: "\u5973", 0xf982: "\u5eec", 0xf983: "\u65c5", 0xf984: "\u6ffe",
+	0xf985: "\u792a", 0xf986: "\u95ad", 0xf987: "\u9a6a", 0xf988: "\u9e97",
+	0xf989: "\u9ece", 0xf98a: "\u529b", 0xf98b: "\u66c6", 0xf98c: "\u6b77",
+	0xf98d: "\u8f62", 0xf98e: "\u5e74", 0xf98f: "\u6190", 0xf990: "\u6200",
+	0xf991: "\u649a", 0xf992: "\u6f23", 0xf993: "\u7149", 0xf994: "\u7489",
+	0xf995: "\u79ca", 0xf996: "\u7df4", 0xf997: "\u806f", 0xf998: "\u8f26",
+	0xf999: "\u84ee", 0xf99a: "\u9023", 0xf99b: "\u934a", 0xf99c: "\u5217",
+	0xf99d: "\u52a3", 0xf99e: "\u54bd", 0xf99f: "\u70c8", 0xf9a0: "\u88c2",
+	0xf9a1: "\u8aaa", 0xf9a2: "\u5ec9", 0xf9a3: "\u5ff5", 0xf9a4: "\u637b",
+	0xf9a5: "\u6bae", 0xf9a6: "\u7c3e", 0xf9a7: "\u7375", 0xf9a8: "\u4ee4",
+	0xf9a9: "\u56f9", 0xf9aa: "\u5be7", 0xf9ab: "\u5dba", 0xf9ac: "\u601c",
+	0xf9ad: "\u73b2", 0xf9ae: "\u7469", 0xf9af: "\u7f9a", 0xf9b0: "\u8046",
+	0xf9b1: "\u9234", 0xf9b2: "\u96f6", 0xf9b3: "\u9748", 0xf9b4: "\u9818",
+	0xf9b5: "\u4f8b", 0xf9b6: "\u79ae", 0xf9b7: "\u91b4", 0xf9b8: "\u96b8",
+	0xf9b9: "\u60e1", 0xf9ba: "\u4e86", 0xf9bb: "\u50da", 0xf9bc: "\u5bee",
+	0xf9bd: "\u5c3f", 0xf9be: "\u6599", 0xf9bf: "\u6a02", 0xf9c0: "\u71ce",
+	0xf9c1: "\u7642", 0xf9c2: "\u84fc", 0xf9c3: "\u907c", 0xf9c4: "\u9f8d",
+	0xf9c5: "\u6688", 0xf9c6: "\u962e", 0xf9c7: "\u5289", 0xf9c8: "\u677b",
+	0xf9c9: "\u67f3", 0xf9ca: "\u6d41", 0xf9cb: "\u6e9c", 0xf9cc: "\u7409",
+	0xf9cd: "\u7559", 0xf9ce: "\u786b", 0xf9cf: "\u7d10", 0xf9d0: "\u985e",
+	0xf9d1: "\u516d", 0xf9d2: "\u622e", 0xf9d3: "\u9678", 0xf9d4: "\u502b",
+	0xf9d5: "\u5d19", 0xf9d6: "\u6dea", 0xf9d7: "\u8f2a", 0xf9d8: "\u5f8b",
+	0xf9d9: "\u6144", 0xf9da: "\u6817", 0xf9db: "\u7387", 0xf9dc: "\u9686",
+	0xf9dd: "\u5229", 0xf9de: "\u540f", 0xf9df: "\u5c65", 0xf9e0: "\u6613",
+	0xf9e1: "\u674e", 0xf9e2: "\u68a8", 0xf9e3: "\u6ce5", 0xf9e4: "\u7406",
+	0xf9e5: "\u75e2", 0xf9e6: "\u7f79", 0xf9e7: "\u88cf", 0xf9e8: "\u88e1",
+	0xf9e9: "\u91cc", 0xf9ea: "\u96e2", 0xf9eb: "\u533f", 0xf9ec: "\u6eba",
+	0xf9ed: "\u541d", 0xf9ee: "\u71d0", 0xf9ef: "\u7498", 0xf9f0: "\u85fa",
+	0xf9f1: "\u96a3", 0xf9f2: "\u9c57", 0xf9f3: "\u9e9f", 0xf9f4: "\u6797",
+	0xf9f5: "\u6dcb", 0xf9f6: "\u81e8", 0xf9f7: "\u7acb", 0xf9f8: "\u7b20",
+	0xf9f9: "\u7c92", 0xf9fa: "\u72c0", 0xf9fb: "\u7099", 0xf9fc: "\u8b58",
+	0xf9fd: "\u4ec0", 0xf9fe: "\u8336", 0xf9ff: "\u523a", 0xfa00: "\u5207",
+	0xfa01: "\u5ea6", 0xfa02: "\u62d3", 0xfa03: "\u7cd6", 0xfa04: "\u5b85",
+	0xfa05: "\u6d1e", 0xfa06: "\u66b4", 0xfa07: "\u8f3b", 0xfa08: "\u884c",
+	0xfa09: "\u964d", 0xfa0a: "\u898b", 0xfa0b: "\u5ed3", 0xfa0c: "\u5140",
+	0xfa0d: "\u55c0", 0xfa10: "\u585a", 0xfa12: "\u6674", 0xfa15: "\u51de",
+	0xfa16: "\u732a", 0xfa17: "\u76ca", 0xfa18: "\u793c", 0xfa19: "\u795e",
+	0xfa1a: "\u7965", 0xfa1b: "\u798f", 0xfa1c: "\u9756", 0xfa1d: "\u7cbe",
+	0xfa1e: "\u7fbd", 0xfa20: "\u8612", 0xfa22: "\u8af8", 0xfa25: "\u9038",
+	0xfa26: "\u90fd", 0xfa2a: "\u98ef", 0xfa2b: "\u98fc", 0xfa2c: "\u9928",
+	0xfa2d: "\u9db4", 0xfa2e: "\u90de", 0xfa2f: "\u96b7", 0xfa30: "\u4fae",
+	0xfa31: "\u50e7", 0xfa32: "\u514d", 0xfa33: "\u52c9", 0xfa34: "\u52e4",
+	0xfa35: "\u5351", 0xfa36: "\u559d", 0xfa37: "\u5606", 0xfa38: "\u5668",
+	0xfa39: "\u5840", 0xfa3a: "\u58a8", 0xfa3b: "\u5c64", 0xfa3c: "\u5c6e",
+	0xfa3d: "\u6094", 0xfa3e: "\u6168", 0xfa3f: "\u618e", 0xfa40: "\u61f2",
+	0xfa41: "\u654f", 0xfa42: "\u65e2", 0xfa43: "\u6691", 0xfa44: "\u6885",
+	0xfa45: "\u6d77", 0xfa46: "\u6e1a", 0xfa47: "\u6f22", 0xfa48: "\u716e",
+	0xfa49: "\u722b", 0xfa4a: "\u7422", 0xfa4b: "\u7891", 0xfa4c: "\u793e",
+	0xfa4d: "\u7949", 0xfa4e: "\u7948", 0xfa4f: "\u7950", 0xfa50: "\u7956",
+	0xfa51: "\u795d", 0xfa52: "\u798d", 0xfa53: "\u798e", 0xfa54: "\u7a40",
+	0xfa55: "\u7a81", 0xfa56: "\u7bc0", 0xfa57: "\u7df4", 0xfa58: "\u7e09",
+	0xfa59: "\u7e41", 0xfa5a: "\u7f72", 0xfa5b: "\u8005", 0xfa5c: "\u81ed",
+	0xfa5d: "\u8279", 0xfa5e: "\u8279", 0xfa5f: "\u8457", 0xfa60: "\u8910",
+	0xfa61: "\u8996", 0xfa62: "\u8b01", 0xfa63: "\u8b39", 0xfa64: "\u8cd3",
+	0xfa65: "\u8d08", 0xfa66: "\u8fb6", 0xfa67: "\u9038", 0xfa68: "\u96e3",
+	0xfa69: "\u97ff", 0xfa6a: "\u983b", 0xfa6b: "\u6075", 0xfa6c: "\U000242ee",
+	0xfa6d: "\u8218", 0xfa70: "\u4e26", 0xfa71: "\u51b5", 0xfa72: "\u5168",
+	0xfa73: "\u4f80", 0xfa74: "\u5145", 0xfa75: "\u5180", 0xfa76: "\u52c7",
+	0xfa77: "\u52fa", 0xfa78: "\u559d", 0xfa79: "\u5555", 0xfa7a: "\u5599",
+	0xfa7b: "\u55e2", 0xfa7c: "\u585a", 0xfa7d: "\u58b3", 0xfa7e: "\u5944",
+	0xfa7f: "\u5954", 0xfa80: "\u5a62", 0xfa81: "\u5b28", 0xfa82: "\u5ed2",
+	0xfa83: "\u5ed9", 0xfa84: "\u5f69", 0xfa85: "\u5fad", 0xfa86: "\u60d8",
+	0xfa87: "\u614e", 0xfa88: "\u6108", 0xfa89: "\u618e", 0xfa8a: "\u6160",
+	0xfa8b: "\u61f2", 0xfa8c: "\u6234", 0xfa8d: "\u63c4", 0xfa8e: "\u641c",
+	0xfa8f: "\u6452", 0xfa90: "\u6556", 0xfa91: "\u6674", 0xfa92: "\u6717",
+	0xfa93: "\u671b", 0xfa94: "\u6756", 0xfa95: "\u6b79", 0xfa96: "\u6bba",
+	0xfa97: "\u6d41", 0xfa98: "\u6edb", 0xfa99: "\u6ecb", 0xfa9a: "\u6f22",
+	0xfa9b: "\u701e", 0xfa9c: "\u716e", 0xfa9d: "\u77a7", 0xfa9e: "\u7235",
+	0xfa9f: "\u72af", 0xfaa0: "\u732a", 0xfaa1: "\u7471", 0xfaa2: "\u7506",
+	0xfaa3: "\u753b", 0xfaa4: "\u761d", 0xfaa5: "\u761f", 0xfaa6: "\u76ca",
+	0xfaa7: "\u76db", 0xfaa8: "\u76f4", 0xfaa9: "\u774a", 0xfaaa: "\u7740",
+	0xfaab: "\u78cc", 0xfaac: "\u7ab1", 0xfaad: "\u7bc0", 0xfaae: "\u7c7b",
+	0xfaaf: "\u7d5b", 0xfab0: "\u7df4", 0xfab1: "\u7f3e", 0xfab2: "\u8005",
+	0xfab3: "\u8352", 0xfab4: "\u83ef", 0xfab5: "\u8779", 0xfab6: "\u8941",
+	0xfab7: "\u8986", 0xfab8: "\u8996", 0xfab9: "\u8abf", 0xfaba: "\u8af8",
+	0xfabb: "\u8acb", 0xfabc: "\u8b01", 0xfabd: "\u8afe", 0xfabe: "\u8aed",
+	0xfabf: "\u8b39", 0xfac0: "\u8b8a", 0xfac1: "\u8d08", 0xfac2: "\u8f38",
+	0xfac3: "\u9072", 0xfac4: "\u9199", 0xfac5: "\u9276", 0xfac6: "\u967c",
+	0xfac7: "\u96e3", 0xfac8: "\u9756", 0xfac9: "\u97db", 0xfaca: "\u97ff",
+	0xfacb: "\u980b", 0xfacc: "\u983b", 0xfacd: "\u9b12", 0xface: "\u9f9c",
+	0xfacf: "\U0002284a", 0xfad0: "\U00022844", 0xfad1: "\U000233d5", 0xfad2: "\u3b9d",
+	0xfad3: "\u4018", 0xfad4: "\u4039", 0xfad5: "\U00025249", 0xfad6: "\U00025cd0",
+	0xfad7: "\U00027ed3", 0xfad8: "\u9f43", 0xfad9: "\u9f8e", 0xfb1d: "\u05d9\u05b4",
+	0xfb1f: "\u05f2\u05b7", 0xfb2a: "\u05e9\u05c1", 0xfb2b: "\u05e9\u05c2", 0xfb2c: "\u05e9\u05bc\u05c1",
+	0xfb2d: "\u05e9\u05bc\u05c2", 0xfb2e: "\u05d0\u05b7", 0xfb2f: "\u05d0\u05b8", 0xfb30: "\u05d0\u05bc",
+	0xfb31: "\u05d1\u05bc", 0xfb32: "\u05d2\u05bc", 0xfb33: "\u05d3\u05bc", 0xfb34: "\u05d4\u05bc",
+	0xfb35: "\u05d5\u05bc", 0xfb36: "\u05d6\u05bc", 0xfb38: "\u05d8\u05bc", 0xfb39: "\u05d9\u05bc",
+	0xfb3a: "\u05da\u05bc", 0xfb3b: "\u05db\u05bc", 0xfb3c: "\u05dc\u05bc", 0xfb3e: "\u05de\u05bc",
+	0xfb40: "\u05e0\u05bc", 0xfb41: "\u05e1\u05bc", 0xfb43: "\u05e3\u05bc", 0xfb44: "\u05e4\u05bc",
+	0xfb46: "\u05e6\u05bc", 0xfb47: "\u05e7\u05bc", 0xfb48: "\u05e8\u05bc", 0xfb49: "\u05e9\u05bc",
+	0xfb4a: "\u05ea\u05bc", 0xfb4b: "\u05d5\u05b9", 0xfb4c: "\u05d1\u05bf", 0xfb4d: "\u05db\u05bf",
+	0xfb4e: "\u05e4\u05bf", 0x1109a: "\U00011099\U000110ba", 0x1109c: "\U0001109b\U000110ba", 0x110ab: "\U000110a5\U000110ba",
+	0x1112e: "\U00011131\U00011127", 0x1112f: "\U00011132\U00011127", 0x1134b: "\U00011347\U0001133e", 0x1134c: "\U00011347\U00011357",
+	0x114bb: "\U000114b9\U000114ba", 0x114bc: "\U000114b9\U000114b0", 0x114be: "\U000114b9\U000114bd", 0x115ba: "\U000115b8\U000115af",
+	0x115bb: "\U000115b9\U000115af", 0x11938: "\U00011935\U00011930", 0x1d15e: "\U0001d157\U0001d165", 0x1d15f: "\U0001d158\U0001d165",
+	0x1d160: "\U0001d158\U0001d165\U0001d16e", 0x1d161: "\U0001d158\U0001d165\U0001d16f", 0x1d162: "\U0001d158\U0001d165\U0001d170", 0x1d163: "\U0001d158\U0001d165\U0001d171",
+	0x1d164: "\U0001d158\U0001d165\U0001d172", 0x1d1bb: "\U0001d1b9\U0001d165", 0x1d1bc: "\U0001d1ba\U0001d165", 0x1d1bd: "\U0001d1b9\U0001d165\U0001d16e",
+	0x1d1be: "\U0001d1ba\U0001d165\U0001d16e", 0x1d1bf: "\U0001d1b9\U0001d165\U0001d16f", 0x1d1c0: "\U0001d1ba\U0001d165\U0001d16f", 0x2f800: "\u4e3d",
+	0x2f801: "\u4e38", 0x2f802: "\u4e41", 0x2f803: "\U00020122", 0x2f804: "\u4f60",
+	0x2f805: "\u4fae", 0x2f806: "\u4fbb", 0x2f807: "\u5002", 0x2f808: "\u507a",
+	0x2f809: "\u5099", 0x2f80a: "\u50e7", 0x2f80b: "\u50cf", 0x2f80c: "\u349e",
+	0x2f80d: "\U0002063a", 0x2f80e: "\u514d", 0x2f80f: "\u5154", 0x2f810: "\u5164",
+	0x2f811: "\u5177", 0x2f812: "\U0002051c", 0x2f813: "\u34b9", 0x2f814: "\u5167",
+	0x2f815: "\u518d", 0x2f816: "\U0002054b", 0x2f817: "\u5197", 0x2f818: "\u51a4",
+	0x2f819: "\u4ecc", 0x2f81a: "\u51ac", 0x2f81b: "\u51b5", 0x2f81c: "\U000291df",
+	0x2f81d: "\u51f5", 0x2f81e: "\u5203", 0x2f81f: "\u34df", 0x2f820: "\u523b",
+	0x2f821: "\u5246", 0x2f822: "\u5272", 0x2f823: "\u5277", 0x2f824: "\u3515",
+	0x2f825: "\u52c7", 0x2f826: "\u52c9", 0x2f827: "\u52e4", 0x2f828: "\u52fa",
+	0x2f829: "\u5305", 0x2f82a: "\u5306", 0x2f82b: "\u5317", 0x2f82c: "\u5349",
+	0x2f82d: "\u5351", 0x2f82e: "\u535a", 0x2f82f: "\u5373", 0x2f830: "\u537d",
+	0x2f831: "\u537f", 0x2f832: "\u537f", 0x2f833: "\u537f", 0x2f834: "\U00020a2c",
+	0x2f835: "\u7070", 0x2f836: "\u53ca", 0x2f837: "\u53df", 0x2f838: "\U00020b63",
+	0x2f839: "\u53eb", 0x2f83a: "\u53f1", 0x2f83b: "\u5406", 0x2f83c: "\u549e",
+	0x2f83d: "\u5438", 0x2f83e: "\u5448", 0x2f83f: "\u5468", 0x2f840: "\u54a2",
+	0x2f841: "\u54f6", 0x2f842: "\u5510", 0x2f843: "\u5553", 0x2f844: "\u5563",
+	0x2f845: "\u5584", 0x2f846: "\u5584", 0x2f847: "\u5599", 0x2f848: "\u55ab",
+	0x2f849: "\u55b3", 0x2f84a: "\u55c2", 0x2f84b: "\u5716", 0x2f84c: "\u5606",
+	0x2f84d: "\u5717", 0x2f84e: "\u5651", 0x2f84f: "\u5674", 0x2f850: "\u5207",
+	0x2f851: "\u58ee", 0x2f852: "\u57ce", 0x2f853: "\u57f4", 0x2f854: "\u580d",
+	0x2f855: "\u578b", 0x2f856: "\u5832", 0x2f857: "\u5831", 0x2f858: "\u58ac",
+	0x2f859: "\U000214e4", 0x2f85a: "\u58f2", 0x2f85b: "\u58f7", 0x2f85c: "\u5906",
+	0x2f85d: "\u591a", 0x2f85e: "\u5922", 0x2f85f: "\u5962", 0x2f860: "\U000216a8",
+	0x2f861: "\U000216ea", 0x2f862: "\u59ec", 0x2f863: "\u5a1b", 0x2f864: "\u5a27",
+	0x2f865: "\u59d8", 0x2f866: "\u5a66", 0x2f867: "\u36ee", 0x2f868: "\u36fc",
+	0x2f869: "\u5b08", 0x2f86a: "\u5b3e", 0x2f86b: "\u5b3e", 0x2f86c: "\U000219c8",
+	0x2f86d: "\u5bc3", 0x2f86e: "\u5bd8", 0x2f86f: "\u5be7", 0x2f870: "\u5bf3",
+	0x2f871: "\U00021b18", 0x2f872: "\u5bff", 0x2f873: "\u5c06", 0x2f874: "\u5f53",
+	0x2f875: "\u5c22", 0x2f876: "\u3781", 0x2f877: "\u5c60", 0x2f878: "\u5c6e",
+	0x2f879: "\u5cc0", 0x2f87a: "\u5c8d", 0x2f87b: "\U00021de4", 0x2f87c: "\u5d43",
+	0x2f87d: "\U00021de6", 0x2f87e: "\u5d6e", 0x2f87f: "\u5d6b", 0x2f880: "\u5d7c",
+	0x2f881: "\u5de1", 0x2f882: "\u5de2", 0x2f883: "\u382f", 0x2f884: "\u5dfd",
+	0x2f885: "\u5e28", 0x2f886: "\u5e3d", 0x2f887: "\u5e69", 0x2f888: "\u3862",
+	0x2f889: "\U00022183", 0x2f88a: "\u387c", 0x2f88b: "\u5eb0", 0x2f88c: "\u5eb3",
+	0x2f88d: "\u5eb6", 0x2f88e: "\u5eca", 0x2f88f: "\U0002a392", 0x2f890: "\u5efe",
+	0x2f891: "\U00022331", 0x2f892: "\U00022331", 0x2f893: "\u8201", 0x2f894: "\u5f22",
+	0x2f895: "\u5f22", 0x2f896: "\u38c7", 0x2f897: "\U000232b8", 0x2f898: "\U000261da",
+	0x2f899: "\u5f62", 0x2f89a: "\u5f6b", 0x2f89b: "\u38e3", 0x2f89c: "\u5f9a",
+	0x2f89d: "\u5fcd", 0x2f89e: "\u5fd7", 0x2f89f: "\u5ff9", 0x2f8a0: "\u6081",
+	0x2f8a1: "\u393a", 0x2f8a2: "\u391c", 0x2f8a3: "\u6094", 0x2f8a4: "\U000226d4",
+	0x2f8a5: "\u60c7", 0x2f8a6: "\u6148", 0x2f8a7: "\u614c", 0x2f8a8: "\u614e",
+	0x2f8a9: "\u614c", 0x2f8aa: "\u617a", 0x2f8ab: "\u618e", 0x2f8ac: "\u61b2",
+	0x2f8ad: "\u61a4", 0x2f8ae: "\u61af", 0x2f8af: "\u61de", 0x2f8b0: "\u61f2",
+	0x2f8b1: "\u61f6", 0x2f8b2: "\u6210", 0x2f8b3: "\u621b", 0x2f8b4: "\u625d",
+	0x2f8b5: "\u62b1", 0x2f8b6: "\u62d4", 0x2f8b7: "\u6350", 0x2f8b8: "\U00022b0c",
+	0x2f8b9: "\u633d", 0x2f8ba: "\u62fc", 0x2f8bb: "\u6368", 0x2f8bc: "\u6383",
+	0x2f8bd: "\u63e4", 0x2f8be: "\U00022bf1", 0x2f8bf: "\u6422", 0x2f8c0: "\u63c5",
+	0x2f8c1: "\u63a9", 0x2f8c2: "\u3a2e", 0x2f8c3: "\u6469", 0x2f8c4: "\u647e",
+	0x2f8c5: "\u649d", 0x2f8c6: "\u6477", 0x2f8c7: "\u3a6c", 0x2f8c8: "\u654f",
+	0x2f8c9: "\u656c", 0x2f8ca: "\U0002300a", 0x2f8cb: "\u65e3", 0x2f8cc: "\u66f8",
+	0x2f8cd: "\u6649", 0x2f8ce: "\u3b19", 0x2f8cf: "\u6691", 0x2f8d0: "\u3b08",
+	0x2f8d1: "\u3ae4", 0x2f8d2: "\u5192", 0x2f8d3: "\u5195", 0x2f8d4: "\u6700",
+	0x2f8d5: "\u669c", 0x2f8d6: "\u80ad", 0x2f8d7: "\u43d9", 0x2f8d8: "\u6717",
+	0x2f8d9: "\u671b", 0x2f8da: "\u6721", 0x2f8db: "\u675e", 0x2f8dc: "\u6753",
+	0x2f8dd: "\U000233c3", 0x2f8de: "\u3b49", 0x2f8df: "\u67fa", 0x2f8e0: "\u6785",
+	0x2f8e1: "\u6852", 0x2f8e2: "\u6885", 0x2f8e3: "\U0002346d", 0x2f8e4: "\u688e",
+	0x2f8e5: "\u681f", 0x2f8e6: "\u6914", 0x2f8e7: "\u3b9d", 0x2f8e8: "\u6942",
+	0x2f8e9: "\u69a3", 0x2f8ea: "\u69ea", 0x2f8eb: "\u6aa8", 0x2f8ec: "\U000236a3",
+	0x2f8ed: "\u6adb", 0x2f8ee: "\u3c18", 0x2f8ef: "\u6b21", 0x2f8f0: "\U000238a7",
+	0x2f8f1: "\u6b54", 0x2f8f2: "\u3c4e", 0x2f8f3: "\u6b72", 0x2f8f4: "\u6b9f",
+	0x2f8f5: "\u6bba", 0x2f8f6: "\u6bbb", 0x2f8f7: "\U00023a8d", 0x2f8f8: "\U00021d0b",
+	0x2f8f9: "\U00023afa", 0x2f8fa: "\u6c4e", 0x2f8fb: "\U00023cbc", 0x2f8fc: "\u6cbf",
+	0x2f8fd: "\u6ccd", 0x2f8fe: "\u6c67", 0x2f8ff: "\u6d16", 0x2f900: "\u6d3e",
+	0x2f901: "\u6d77", 0x2f902: "\u6d41", 0x2f903: "\u6d69", 0x2f904: "\u6d78",
+	0x2f905: "\u6d85", 0x2f906: "\U00023d1e", 0x2f907: "\u6d34", 0x2f908: "\u6e2f",
+	0x2f909: "\u6e6e", 0x2f90a: "\u3d33", 0x2f90b: "\u6ecb", 0x2f90c: "\u6ec7",
+	0x2f90d: "\U00023ed1", 0x2f90e: "\u6df9", 0x2f90f: "\u6f6e", 0x2f910: "\U00023f5e",
+	0x2f911: "\U00023f8e", 0x2f912: "\u6fc6", 0x2f913: "\u7039", 0x2f914: "\u701e",
+	0x2f915: "\u701b", 0x2f916: "\u3d96", 0x2f917: "\u704a", 0x2f918: "\u707d",
+	0x2f919: "\u7077", 0x2f91a: "\u70ad", 0x2f91b: "\U00020525", 0x2f91c: "\u7145",
+	0x2f91d: "\U00024263", 0x2f91e: "\u719c", 0x2f91f: "\U000243ab", 0x2f920: "\u7228",
+	0x2f921: "\u7235", 0x2f922: "\u7250", 0x2f923: "\U00024608", 0x2f924: "\u7280",
+	0x2f925: "\u7295", 0x2f926: "\U00024735", 0x2f927: "\U00024814", 0x2f928: "\u737a",
+	0x2f929: "\u738b", 0x2f92a: "\u3eac", 0x2f92b: "\u73a5", 0x2f92c: "\u3eb8",
+	0x2f92d: "\u3eb8", 0x2f92e: "\u7447", 0x2f92f: "\u745c", 0x2f930: "\u7471",
+	0x2f931: "\u7485", 0x2f932: "\u74ca", 0x2f933: "\u3f1b", 0x2f934: "\u7524",
+	0x2f935: "\U00024c36", 0x2f936: "\u753e", 0x2f937: "\U00024c92", 0x2f938: "\u7570",
+	0x2f939: "\U0002219f", 0x2f93a: "\u7610", 0x2f93b: "\U00024fa1", 0x2f93c: "\U00024fb8",
+	0x2f93d: "\U00025044", 0x2f93e: "\u3ffc", 0x2f93f: "\u4008", 0x2f940: "\u76f4",
+	0x2f941: "\U000250f3", 0x2f942: "\U000250f2", 0x2f943: "\U00025119", 0x2f944: "\U00025133",
+	0x2f945: "\u771e", 0x2f946: "\u771f", 0x2f947: "\u771f", 0x2f948: "\u774a",
+	0x2f949: "\u4039", 0x2f94a: "\u778b", 0x2f94b: "\u4046", 0x2f94c: "\u4096",
+	0x2f94d: "\U0002541d", 0x2f94e: "\u784e", 0x2f94f: "\u788c", 0x2f950: "\u78cc",
+	0x2f951: "\u40e3", 0x2f952: "\U00025626", 0x2f953: "\u7956", 0x2f954: "\U0002569a",
+	0x2f955: "\U000256c5", 0x2f956: "\u798f", 0x2f957: "\u79eb", 0x2f958: "\u412f",
+	0x2f959: "\u7a40", 0x2f95a: "\u7a4a", 0x2f95b: "\u7a4f", 0x2f95c: "\U0002597c",
+	0x2f95d: "\U00025aa7", 0x2f95e: "\U00025aa7", 0x2f95f: "\u7aee", 0x2f960: "\u4202",
+	0x2f961: "\U00025bab", 0x2f962: "\u7bc6", 0x2f963: "\u7bc9", 0x2f964: "\u4227",
+	0x2f965: "\U00025c80", 0x2f966: "\u7cd2", 0x2f967: "\u42a0", 0x2f968: "\u7ce8",
+	0x2f969: "\u7ce3", 0x2f96a: "\u7d00", 0x2f96b: "\U00025f86", 0x2f96c: "\u7d63",
+	0x2f96d: "\u4301", 0x2f96e: "\u7dc7", 0x2f96f: "\u7e02", 0x2f970: "\u7e45",
+	0x2f971: "\u4334", 0x2f972: "\U00026228", 0x2f973: "\U00026247", 0x2f974: "\u4359",
+	0x2f975: "\U000262d9", 0x2f976: "\u7f7a", 0x2f977: "\U0002633e", 0x2f978: "\u7f95",
+	0x2f979: "\u7ffa", 0x2f97a: "\u8005", 0x2f97b: "\U000264da", 0x2f97c: "\U00026523",
+	0x2f97d: "\u8060", 0x2f97e: "\U000265a8", 0x2f97f: "\u8070", 0x2f980: "\U0002335f",
+	0x2f981: "\u43d5", 0x2f982: "\u80b2", 0x2f983: "\u8103", 0x2f984: "\u440b",
+	0x2f985: "\u813e", 0x2f986: "\u5ab5", 0x2f987: "\U000267a7", 0x2f988: "\U000267b5",
+	0x2f989: "\U00023393", 0x2f98a: "\U0002339c", 0x2f98b: "\u8201", 0x2f98c: "\u8204",
+	0x2f98d: "\u8f9e", 0x2f98e: "\u446b", 0x2f98f: "\u8291", 0x2f990: "\u828b",
+	0x2f991: "\u829d", 0x2f992: "\u52b3", 0x2f993: "\u82b1", 0x2f994: "\u82b3",
+	0x2f995: "\u82bd", 0x2f996: "\u82e6", 0x2f997: "\U00026b3c", 0x2f998: "\u82e5",
+	0x2f999: "\u831d", 0x2f99a: "\u8363", 0x2f99b: "\u83ad", 0x2f99c: "\u8323",
+	0x2f99d: "\u83bd", 0x2f99e: "\u83e7", 0x2f99f: "\u8457", 0x2f9a0: "\u8353",
+	0x2f9a1: "\u83ca", 0x2f9a2: "\u83cc", 0x2f9a3: "\u83dc", 0x2f9a4: "\U00026c36",
+	0x2f9a5: "\U00026d6b", 0x2f9a6: "\U00026cd5", 0x2f9a7: "\u452b", 0x2f9a8: "\u84f1",
+	0x2f9a9: "\u84f3", 0x2f9aa: "\u8516", 0x2f9ab: "\U000273ca", 0x2f9ac: "\u8564",
+	0x2f9ad: "\U00026f2c", 0x2f9ae: "\u455d", 0x2f9af: "\u4561", 0x2f9b0: "\U00026fb1",
+	0x2f9b1: "\U000270d2", 0x2f9b2: "\u456b", 0x2f9b3: "\u8650", 0x2f9b4: "\u865c",
+	0x2f9b5: "\u8667", 0x2f9b6: "\u8669", 0x2f9b7: "\u86a9", 0x2f9b8: "\u8688",
+	0x2f9b9: "\u870e", 0x2f9ba: "\u86e2", 0x2f9bb: "\u8779", 0x2f9bc: "\u8728",
+	0x2f9bd: "\u876b", 0x2f9be: "\u8786", 0x2f9bf: "\u45d7", 0x2f9c0: "\u87e1",
+	0x2f9c1: "\u8801", 0x2f9c2: "\u45f9", 0x2f9c3: "\u8860", 0x2f9c4: "\u8863",
+	0x2f9c5: "\U00027667", 0x2f9c6: "\u88d7", 0x2f9c7: "\u88de", 0x2f9c8: "\u4635",
+	0x2f9c9: "\u88fa", 0x2f9ca: "\u34bb", 0x2f9cb: "\U000278ae", 0x2f9cc: "\U00027966",
+	0x2f9cd: "\u46be", 0x2f9ce: "\u46c7", 0x2f9cf: "\u8aa0", 0x2f9d0: "\u8aed",
+	0x2f9d1: "\u8b8a", 0x2f9d2: "\u8c55", 0x2f9d3: "\U00027ca8", 0x2f9d4: "\u8cab",
+	0x2f9d5: "\u8cc1", 0x2f9d6: "\u8d1b", 0x2f9d7: "\u8d77", 0x2f9d8: "\U00027f2f",
+	0x2f9d9: "\U00020804", 0x2f9da: "\u8dcb", 0x2f9db: "\u8dbc", 0x2f9dc: "\u8df0",
+	0x2f9dd: "\U000208de", 0x2f9de: "\u8ed4", 0x2f9df: "\u8f38", 0x2f9e0: "\U000285d2",
+	0x2f9e1: "\U000285ed", 0x2f9e2: "\u9094", 0x2f9e3: "\u90f1", 0x2f9e4: "\u9111",
+	0x2f9e5: "\U0002872e", 0x2f9e6: "\u911b", 0x2f9e7: "\u9238", 0x2f9e8: "\u92d7",
+	0x2f9e9: "\u92d8", 0x2f9ea: "\u927c", 0x2f9eb: "\u93f9", 0x2f9ec: "\u9415",
+	0x2f9ed: "\U00028bfa", 0x2f9ee: "\u958b", 0x2f9ef: "\u4995", 0x2f9f0: "\u95b7",
+	0x2f9f1: "\U00028d77", 0x2f9f2: "\u49e6", 0x2f9f3: "\u96c3", 0x2f9f4: "\u5db2",
+	0x2f9f5: "\u9723", 0x2f9f6: "\U00029145", 0x2f9f7: "\U0002921a", 0x2f9f8: "\u4a6e",
+	0x2f9f9: "\u4a76", 0x2f9fa: "\u97e0", 0x2f9fb: "\U0002940a", 0x2f9fc: "\u4ab2",
+	0x2f9fd: "\U00029496", 0x2f9fe: "\u980b", 0x2f9ff: "\u980b", 0x2fa00: "\u9829",
+	0x2fa01: "\U000295b6", 0x2fa02: "\u98e2", 0x2fa03: "\u4b33", 0x2fa04: "\u9929",
+	0x2fa05: "\u99a7", 0x2fa06: "\u99c2", 0x2fa07: "\u99fe", 0x2fa08: "\u4bce",
+	0x2fa09: "\U00029b30", 0x2fa0a: "\u9b12", 0x2fa0b: "\u9c40", 0x2fa0c: "\u9cfd",
+	0x2fa0d: "\u4cce", 0x2fa0e: "\u4ced", 0x2fa0f: "\u9d67", 0x2fa10: "\U0002a0ce",
+	0x2fa11: "\u4cf8", 0x2fa12: "\U0002a105", 0x2fa13: "\U0002a20e", 0x2fa14: "\U0002a291",
+	0x2fa15: "\u9ebb", 0x2fa16: "\u4d56", 0x2fa17: "\u9ef9", 0x2fa18: "\u9efe",
+	0x2fa19: "\u9f05", 0x2fa1a: "\u9f0f", 0x2fa1b: "\u9f16", 0x2fa1c: "\u9f3b",
+	0x2fa1d: "\U0002a600",
+}
+
+// canonicalComposition maps a starter and the following character to their primary composite
+var canonicalComposition = map[[2]rune]rune{
+	{0x41, 0x300}: 0xc0, {0x41, 0x301}: 0xc1, {0x41, 0x302}: 0xc2, {0x41, 0x303}: 0xc3,
+	{0x41, 0x308}: 0xc4, {0x41, 0x30a}: 0xc5, {0x43, 0x327}: 0xc7, {0x45, 0x300}: 0xc8,
+	{0x45, 0x301}: 0xc9, {0x45, 0x302}: 0xca, {0x45, 0x308}: 0xcb, {0x49, 0x300}: 0xcc,
+	{0x49, 0x301}: 0xcd, {0x49, 0x302}: 0xce, {0x49, 0x308}: 0xcf, {0x4e, 0x303}: 0xd1,
+	{0x4f, 0x300}: 0xd2, {0x4f, 0x301}: 0xd3, {0x4f, 0x302}: 0xd4, {0x4f, 0x303}: 0xd5,
+	{0x4f, 0x308}: 0xd6, {0x55, 0x300}: 0xd9, {0x55, 0x301}: 0xda, {0x55, 0x302}: 0xdb,
+	{0x55, 0x308}: 0xdc, {0x59, 0x301}: 0xdd, {0x61, 0x300}: 0xe0, {0x61, 0x301}: 0xe1,
+	{0x61, 0x302}: 0xe2, {0x61, 0x303}: 0xe3, {0x61, 0x308}: 0xe4, {0x61, 0x30a}: 0xe5,
+	{0x63, 0x327}: 0xe7, {0x65, 0x300}: 0xe8, {0x65, 0x301}: 0xe9, {0x65, 0x302}: 0xea,
+	{0x65, 0x308}: 0xeb, {0x69, 0x300}: 0xec, {0x69, 0x301}: 0xed, {0x69, 0x302}: 0xee,
+	{0x69, 0x308}: 0xef, {0x6e, 0x303}: 0xf1, {0x6f, 0x300}: 0xf2, {0x6f, 0x301}: 0xf3,
+	{0x6f, 0x302}: 0xf4, {0x6f, 0x303}: 0xf5, {0x6f, 0x308}: 0xf6, {0x75, 0x300}: 0xf9,
+	{0x75, 0x301}: 0xfa, {0x75, 0x302}: 0xfb, {0x75, 0x308}: 0xfc, {0x79, 0x301}: 0xfd,
+	{0x79, 0x308}: 0xff, {0x41, 0x304}: 0x100, {0x61, 0x304}: 0x101, {0x41, 0x306}: 0x102,
+	{0x61, 0x306}: 0x103, {0x41, 0x328}: 0x104, {0x61, 0x328}: 0x105, {0x43, 0x301}: 0x106,
+	{0x63, 0x301}: 0x107, {0x43, 0x302}: 0x108, {0x63, 0x302}: 0x109, {0x43, 0x307}: 0x10a,
+	{0x63, 0x307}: 0x10b, {0x43, 0x30c}: 0x10c, {0x63, 0x30c}: 0x10d, {0x44, 0x30c}: 0x10e,
+	{0x64, 0x30c}: 0x10f, {0x45, 0x304}: 0x112, {0x65, 0x304}: 0x113, {0x45, 0x306}: 0x114,
+	{0x65, 0x306}: 0x115, {0x45, 0x307}: 0x116, {0x65, 0x307}: 0x117, {0x45, 0x328}: 0x118,
+	{0x65, 0x328}: 0x119, {0x45, 0x30c}: 0x11a, {0x65, 0x30c}: 0x11b, {0x47, 0x302}: 0x11c,
+	{0x67, 0x302}: 0x11d, {0x47, 0x306}: 0x11e, {0x67, 0x306}: 0x11f, {0x47, 0x307}: 0x120,
+	{0x67, 0x307}: 0x121, {0x47, 0x327}: 0x122, {0x67, 0x327}: 0x123, {0x48, 0x302}: 0x124,
+	{0x68, 0x302}: 0x125, {0x49, 0x303}: 0x128, {0x69, 0x303}: 0x129, {0x49, 0x304}: 0x12a,
+	{0x69, 0x304}: 0x12b, {0x49, 0x306}: 0x12c, {0x69, 0x306}: 0x12d, {0x49, 0x328}: 0x12e,
+	{0x69, 0x328}: 0x12f, {0x49, 0x307}: 0x130, {0x4a, 0x302}: 0x134, {0x6a, 0x302}: 0x135,
+	{0x4b, 0x327}: 0x136, {0x6b, 0x327}: 0x137, {0x4c, 0x301}: 0x139, {0x6c, 0x301}: 0x13a,
+	{0x4c, 0x327}: 0x13b, {0x6c, 0x327}: 0x13c, {0x4c, 0x30c}: 0x13d, {0x6c, 0x30c}: 0x13e,
+	{0x4e, 0x301}: 0x143, {0x6e, 0x301}: 0x144, {0x4e, 0x327}: 0x145, {0x6e, 0x327}: 0x146,
+	{0x4e, 0x30c}: 0x147, {0x6e, 0x30c}: 0x148, {0x4f, 0x304}: 0x14c, {0x6f, 0x304}: 0x14d,
+	{0x4f, 0x306}: 0x14e, {0x6f, 0x306}: 0x14f, {0x4f, 0x30b}: 0x150, {0x6f, 0x30b}: 0x151,
+	{0x52, 0x301}: 0x154, {0x72, 0x301}: 0x155, {0x52, 0x327}: 0x156, {0x72, 0x327}: 0x157,
+	{0x52, 0x30c}: 0x158, {0x72, 0x30c}: 0x159, {0x53, 0x301}: 0x15a, {0x73, 0x301}: 0x15b,
+	{0x53, 0x302}: 0x15c, {0x73, 0x302}: 0x15d, {0x53, 0x327}: 0x15e, {0x73, 0x327}: 0x15f,
+	{0x53, 0x30c}: 0x160, {0x73, 0x30c}: 0x161, {0x54, 0x327}: 0x162, {0x74, 0x327}: 0x163,
+	{0x54, 0x30c}: 0x164, {0x74, 0x30c}: 0x165, {0x55, 0x303}: 0x168, {0x75, 0x303}: 0x169,
+	{0x55, 0x304}: 0x16a, {0x75, 0x304}: 0x16b, {0x55, 0x306}: 0x16c, {0x75, 0x306}: 0x16d,
+	{0x55, 0x30a}: 0x16e, {0x75, 0x30a}: 0x16f, {0x55, 0x30b}: 0x170, {0x75, 0x30b}: 0x171,
+	{0x55, 0x328}: 0x172, {0x75, 0x328}: 0x173, {0x57, 0x302}: 0x174, {0x77, 0x302}: 0x175,
+	{0x59, 0x302}: 0x176, {0x79, 0x302}: 0x177, {0x59, 0x308}: 0x178, {0x5a, 0x301}: 0x179,
+	{0x7a, 0x301}: 0x17a, {0x5a, 0x307}: 0x17b, {0x7a, 0x307}: 0x17c, {0x5a, 0x30c}: 0x17d,
+	{0x7a, 0x30c}: 0x17e, {0x4f, 0x31b}: 0x1a0, {0x6f, 0x31b}: 0x1a1, {0x55, 0x31b}: 0x1af,
+	{0x75, 0x31b}: 0x1b0, {0x41, 0x30c}: 0x1cd, {0x61, 0x30c}: 0x1ce, {0x49, 0x30c}: 0x1cf,
+	{0x69, 0x30c}: 0x1d0, {0x4f, 0x30c}: 0x1d1, {0x6f, 0x30c}: 0x1d2, {0x55, 0x30c}: 0x1d3,
+	{0x75, 0x30c}: 0x1d4, {0xdc, 0x304}: 0x1d5, {0xfc, 0x304}: 0x1d6, {0xdc, 0x301}: 0x1d7,
+	{0xfc, 0x301}: 0x1d8, {0xdc, 0x30c}: 0x1d9, {0xfc, 0x30c}: 0x1da, {0xdc, 0x300}: 0x1db,
+	{0xfc, 0x300}: 0x1dc, {0xc4, 0x304}: 0x1de, {0xe4, 0x304}: 0x1df, {0x226, 0x304}: 0x1e0,
+	{0x227, 0x304}: 0x1e1, {0xc6, 0x304}: 0x1e2, {0xe6, 0x304}: 0x1e3, {0x47, 0x30c}: 0x1e6,
+	{0x67, 0x30c}: 0x1e7, {0x4b, 0x30c}: 0x1e8, {0x6b, 0x30c}: 0x1e9, {0x4f, 0x328}: 0x1ea,
+	{0x6f, 0x328}: 0x1eb, {0x1ea, 0x304}: 0x1ec, {0x1eb, 0x304}: 0x1ed, {0x1b7, 0x30c}: 0x1ee,
+	{0x292, 0x30c}: 0x1ef, {0x6a, 0x30c}: 0x1f0, {0x47, 0x301}: 0x1f4, {0x67, 0x301}: 0x1f5,
+	{0x4e, 0x300}: 0x1f8, {0x6e, 0x300}: 0x1f9, {0xc5, 0x301}: 0x1fa, {0xe5, 0x301}: 0x1fb,
+	{0xc6, 0x301}: 0x1fc, {0xe6, 0x301}: 0x1fd, {0xd8, 0x301}: 0x1fe, {0xf8, 0x301}: 0x1ff,
+	{0x41, 0x30f}: 0x200, {0x61, 0x30f}: 0x201, {0x41, 0x311}: 0x202, {0x61, 0x311}: 0x203,
+	{0x45, 0x30f}: 0x204, {0x65, 0x30f}: 0x205, {0x45, 0x311}: 0x206, {0x65, 0x311}: 0x207,
+	{0x49, 0x30f}: 0x208, {0x69, 0x30f}: 0x209, {0x49, 0x311}: 0x20a, {0x69, 0x311}: 0x20b,
+	{0x4f, 0x30f}: 0x20c, {0x6f, 0x30f}: 0x20d, {0x4f, 0x311}: 0x20e, {0x6f, 0x311}: 0x20f,
+	{0x52, 0x30f}: 0x210, {0x72, 0x30f}: 0x211, {0x52, 0x311}: 0x212, {0x72, 0x311}: 0x213,
+	{0x55, 0x30f}: 0x214, {0x75, 0x30f}: 0x215, {0x55, 0x311}: 0x216, {0x75, 0x311}: 0x217,
+	{0x53, 0x326}: 0x218, {0x73, 0x326}: 0x219, {0x54, 0x326}: 0x21a, {0x74, 0x326}: 0x21b,
+	{0x48, 0x30c}: 0x21e, {0x68, 0x30c}: 0x21f, {0x41, 0x307}: 0x226, {0x61, 0x307}: 0x227,
+	{0x45, 0x327}: 0x228, {0x65, 0x327}: 0x229, {0xd6, 0x304}: 0x22a, {0xf6, 0x304}: 0x22b,
+	{0xd5, 0x304}: 0x22c, {0xf5, 0x304}: 0x22d, {0x4f, 0x307}: 0x22e, {0x6f, 0x307}: 0x22f,
+	{0x22e, 0x304}: 0x230, {0x22f, 0x304}: 0x231, {0x59, 0x304}: 0x232, {0x79, 0x304}: 0x233,
+	{0xa8, 0x301}: 0x385, {0x391, 0x301}: 0x386, {0x395, 0x301}: 0x388, {0x397, 0x301}: 0x389,
+	{0x399, 0x301}: 0x38a, {0x39f, 0x301}: 0x38c, {0x3a5, 0x301}: 0x38e, {0x3a9, 0x301}: 0x38f,
+	{0x3ca, 0x301}: 0x390, {0x399, 0x308}: 0x3aa, {0x3a5, 0x308}: 0x3ab, {0x3b1, 0x301}: 0x3ac,
+	{0x3b5, 0x301}: 0x3ad, {0x3b7, 0x301}: 0x3ae, {0x3b9, 0x301}: 0x3af, {0x3cb, 0x301}: 0x3b0,
+	{0x3b9, 0x308}: 0x3ca, {0x3c5, 0x308}: 0x3cb, {0x3bf, 0x301}: 0x3cc, {0x3c5, 0x301}: 0x3cd,
+	{0x3c9, 0x301}: 0x3ce, {0x3d2, 0x301}: 0x3d3, {0x3d2, 0x308}: 0x3d4, {0x415, 0x300}: 0x400,
+	{0x415, 0x308}: 0x401, {0x413, 0x301}: 0x403, {0x406, 0x308}: 0x407, {0x41a, 0x301}: 0x40c,
+	{0x418, 0x300}: 0x40d, {0x423, 0x306}: 0x40e, {0x418, 0x306}: 0x419, {0x438, 0x306}: 0x439,
+	{0x435, 0x300}: 0x450, {0x435, 0x308}: 0x451, {0x433, 0x301}: 0x453, {0x456, 0x308}: 0x457,
+	{0x43a, 0x301}: 0x45c, {0x438, 0x300}: 0x45d, {0x443, 0x306}: 0x45e, {0x474, 0x30f}: 0x476,
+	{0x475, 0x30f}: 0x477, {0x416, 0x306}: 0x4c1, {0x436, 0x306}: 0x4c2, {0x410, 0x306}: 0x4d0,
+	{0x430, 0x306}: 0x4d1, {0x410, 0x308}: 0x4d2, {0x430, 0x308}: 0x4d3, {0x415, 0x306}: 0x4d6,
+	{0x435, 0x306}: 0x4d7, {0x4d8, 0x308}: 0x4da, {0x4d9, 0x308}: 0x4db, {0x416, 0x308}: 0x4dc,
+	{0x436, 0x308}: 0x4dd, {0x417, 0x308}: 0x4de, {0x437, 0x308}: 0x4df, {0x418, 0x304}: 0x4e2,
+	{0x438, 0x304}: 0x4e3, {0x418, 0x308}: 0x4e4, {0x438, 0x308}: 0x4e5, {0x41e, 0x308}: 0x4e6,
+	{0x43e, 0x308}: 0x4e7, {0x4e8, 0x308}: 0x4ea, {0x4e9, 0x308}: 0x4eb, {0x42d, 0x308}: 0x4ec,
+	{0x44d, 0x308}: 0x4ed, {0x423, 0x304}: 0x4ee, {0x443, 0x304}: 0x4ef, {0x423, 0x308}: 0x4f0,
+	{0x443, 0x308}: 0x4f1, {0x423, 0x30b}: 0x4f2, {0x443, 0x30b}: 0x4f3, {0x427, 0x308}: 0x4f4,
+	{0x447, 0x308}: 0x4f5, {0x42b, 0x308}: 0x4f8, {0x44b, 0x308}: 0x4f9, {0x627, 0x653}: 0x622,
+	{0x627, 0x654}: 0x623, {0x648, 0x654}: 0x624, {0x627, 0x655}: 0x625, {0x64a, 0x654}: 0x626,
+	{0x6d5, 0x654}: 0x6c0, {0x6c1, 0x654}: 0x6c2, {0x6d2, 0x654}: 0x6d3, {0x928, 0x93c}: 0x929,
+	{0x930, 0x93c}: 0x931, {0x933, 0x93c}: 0x934, {0x9c7, 0x9be}: 0x9cb, {0x9c7, 0x9d7}: 0x9cc,
+	{0xb47, 0xb56}: 0xb48, {0xb47, 0xb3e}: 0xb4b, {0xb47, 0xb57}: 0xb4c, {0xb92, 0xbd7}: 0xb94,
+	{0xbc6, 0xbbe}: 0xbca, {0xbc7, 0xbbe}: 0xbcb, {0xbc6, 0xbd7}: 0xbcc, {0xc46, 0xc56}: 0xc48,
+	{0xcbf, 0xcd5}: 0xcc0, {0xcc6, 0xcd5}: 0xcc7, {0xcc6, 0xcd6}: 0xcc8, {0xcc6, 0xcc2}: 0xcca,
+	{0xcca, 0xcd5}: 0xccb, {0xd46, 0xd3e}: 0xd4a, {0xd47, 0xd3e}: 0xd4b, {0xd46, 0xd57}: 0xd4c,
+	{0xdd9, 0xdca}: 0xdda, {0xdd9, 0xdcf}: 0xddc, {0xddc, 0xdca}: 0xddd, {0xdd9, 0xddf}: 0xdde,
+	{0x1025, 0x102e}: 0x1026, {0x1b05, 0x1b35}: 0x1b06, {0x1b07, 0x1b35}: 0x1b08, {0x1b09, 0x1b35}: 0x1b0a,
+	{0x1b0b, 0x1b35}: 0x1b0c, {0x1b0d, 0x1b35}: 0x1b0e, {0x1b11, 0x1b35}: 0x1b12, {0x1b3a, 0x1b35}: 0x1b3b,
+	{0x1b3c, 0x1b35}: 0x1b3d, {0x1b3e, 0x1b35}: 0x1b40, {0x1b3f, 0x1b35}: 0x1b41, {0x1b42, 0x1b35}: 0x1b43,
+	{0x41, 0x325}: 0x1e00, {0x61, 0x325}: 0x1e01, {0x42, 0x307}: 0x1e02, {0x62, 0x307}: 0x1e03,
+	{0x42, 0x323}: 0x1e04, {0x62, 0x323}: 0x1e05, {0x42, 0x331}: 0x1e06, {0x62, 0x331}: 0x1e07,
+	{0xc7, 0x301}: 0x1e08, {0xe7, 0x301}: 0x1e09, {0x44, 0x307}: 0x1e0a, {0x64, 0x307}: 0x1e0b,
+	{0x44, 0x323}: 0x1e0c, {0x64, 0x323}: 0x1e0d, {0x44, 0x331}: 0x1e0e, {0x64, 0x331}: 0x1e0f,
+	{0x44, 0x327}: 0x1e10, {0x64, 0x327}: 0x1e11, {0x44, 0x32d}: 0x1e12, {0x64, 0x32d}: 0x1e13,
+	{0x112, 0x300}: 0x1e14, {0x113, 0x300}: 0x1e15, {0x112, 0x301}: 0x1e16, {0x113, 0x301}: 0x1e17,
+	{0x45, 0x32d}: 0x1e18, {0x65, 0x32d}: 0x1e19, {0x45, 0x330}: 0x1e1a, {0x65, 0x330}: 0x1e1b,
+	{0x228, 0x306}: 0x1e1c, {0x229, 0x306}: 0x1e1d, {0x46, 0x307}: 0x1e1e, {0x66, 0x307}: 0x1e1f,
+	{0x47, 0x304}: 0x1e20, {0x67, 0x304}: 0x1e21, {0x48, 0x307}: 0x1e22, {0x68, 0x307}: 0x1e23,
+	{0x48, 0x323}: 0x1e24, {0x68, 0x323}: 0x1e25, {0x48, 0x308}: 0x1e26, {0x68, 0x308}: 0x1e27,
+	{0x48, 0x327}: 0x1e28, {0x68, 0x327}: 0x1e29, {0x48, 0x32e}: 0x1e2a, {0x68, 0x32e}: 0x1e2b,
+	{0x49, 0x330}: 0x1e2c, {0x69, 0x330}: 0x1e2d, {0xcf, 0x301}: 0x1e2e, {0xef, 0x301}: 0x1e2f,
+	{0x4b, 0x301}: 0x1e30, {0x6b, 0x301}: 0x1e31, {0x4b, 0x323}: 0x1e32, {0x6b, 0x323}: 0x1e33,
+	{0x4b, 0x331}: 0x1e34, {0x6b, 0x331}: 0x1e35, {0x4c, 0x323}: 0x1e36, {0x6c, 0x323}: 0x1e37,
+	{0x1e36, 0x304}: 0x1e38, {0x1e37, 0x304}: 0x1e39, {0x4c, 0x331}: 0x1e3a, {0x6c, 0x331}: 0x1e3b,
+	{0x4c, 0x32d}: 0x1e3c, {0x6c, 0x32d}: 0x1e3d, {0x4d, 0x301}: 0x1e3e, {0x6d, 0x301}: 0x1e3f,
+	{0x4d, 0x307}: 0x1e40, {0x6d, 0x307}: 0x1e41, {0x4d, 0x323}: 0x1e42, {0x6d, 0x323}: 0x1e43,
+	{0x4e, 0x307}: 0x1e44, {0x6e, 0x307}: 0x1e45, {0x4e, 0x323}: 0x1e46, {0x6e, 0x323}: 0x1e47,
+	{0x4e, 0x331}: 0x1e48, {0x6e, 0x331}: 0x1e49, {0x4e, 0x32d}: 0x1e4a, {0x6e, 0x32d}: 0x1e4b,
+	{0xd5, 0x301}: 0x1e4c, {0xf5, 0x301}: 0x1e4d, {0xd5, 0x308}: 0x1e4e, {0xf5, 0x308}: 0x1e4f,
+	{0x14c, 0x300}: 0x1e50, {0x14d, 0x300}: 0x1e51, {0x14c, 0x301}: 0x1e52, {0x14d, 0x301}: 0x1e53,
+	{0x50, 0x301}: 0x1e54, {0x70, 0x301}: 0x1e55, {0x50, 0x307}: 0x1e56, {0x70, 0x307}: 0x1e57,
+	{0x52, 0x307}: 0x1e58, {0x72, 0x307}: 0x1e59, {0x52, 0x323}: 0x1e5a, {0x72, 0x323}: 0x1e5b,
+	{0x1e5a, 0x304}: 0x1e5c, {0x1e5b, 0x304}: 0x1e5d, {0x52, 0x331}: 0x1e5e, {0x72, 0x331}: 0x1e5f,
+	{0x53, 0x307}: 0x1e60, {0x73, 0x307}: 0x1e61, {0x53, 0x323}: 0x1e62, {0x73, 0x323}: 0x1e63,
+	{0x15a, 0x307}: 0x1e64, {0x15b, 0x307}: 0x1e65, {0x160, 0x307}: 0x1e66, {0x161, 0x307}: 0x1e67,
+	{0x1e62, 0x307}: 0x1e68, {0x1e63, 0x307}: 0x1e69, {0x54, 0x307}: 0x1e6a, {0x74, 0x307}: 0x1e6b,
+	{0x54, 0x323}: 0x1e6c, {0x74, 0x323}: 0x1e6d, {0x54, 0x331}: 0x1e6e, {0x74, 0x331}: 0x1e6f,
+	{0x54, 0x32d}: 0x1e70, {0x74, 0x32d}: 0x1e71, {0x55, 0x324}: 0x1e72, {0x75, 0x324}: 0x1e73,
+	{0x55, 0x330}: 0x1e74, {0x75, 0x330}: 0x1e75, {0x55, 0x32d}: 0x1e76, {0x75, 0x32d}: 0x1e77,
+	{0x168, 0x301}: 0x1e78, {0x169, 0x301}: 0x1e79, {0x16a, 0x308}: 0x1e7a, {0x16b, 0x308}: 0x1e7b,
+	{0x56, 0x303}: 0x1e7c, {0x76, 0x303}: 0x1e7d, {0x56, 0x323}: 0x1e7e, {0x76, 0x323}: 0x1e7f,
+	{0x57, 0x300}: 0x1e80, {0x77, 0x300}: 0x1e81, {0x57, 0x301}: 0x1e82, {0x77, 0x301}: 0x1e83,
+	{0x57, 0x308}: 0x1e84, {0x77, 0x308}: 0x1e85, {0x57, 0x307}: 0x1e86, {0x77, 0x307}: 0x1e87,
+	{0x57, 0x323}: 0x1e88, {0x77, 0x323}: 0x1e89, {0x58, 0x307}: 0x1e8a, {0x78, 0x307}: 0x1e8b,
+	{0x58, 0x308}: 0x1e8c, {0x78, 0x308}: 0x1e8d, {0x59, 0x307}: 0x1e8e, {0x79, 0x307}: 0x1e8f,
+	{0x5a, 0x302}: 0x1e90, {0x7a, 0x302}: 0x1e91, {0x5a, 0x323}: 0x1e92, {0x7a, 0x323}: 0x1e93,
+	{0x5a, 0x331}: 0x1e94, {0x7a, 0x331}: 0x1e95, {0x68, 0x331}: 0x1e96, {0x74, 0x308}: 0x1e97,
+	{0x77, 0x30a}: 0x1e98, {0x79, 0x30a}: 0x1e99, {0x17f, 0x307}: 0x1e9b, {0x41, 0x323}: 0x1ea0,
+	{0x61, 0x323}: 0x1ea1, {0x41, 0x309}: 0x1ea2, {0x61, 0x309}: 0x1ea3, {0xc2, 0x301}: 0x1ea4,
+	{0xe2, 0x301}: 0x1ea5, {0xc2, 0x300}: 0x1ea6, {0xe2, 0x300}: 0x1ea7, {0xc2, 0x309}: 0x1ea8,
+	{0xe2, 0x309}: 0x1ea9, {0xc2, 0x303}: 0x1eaa, {0xe2, 0x303}: 0x1eab, {0x1ea0, 0x302}: 0x1eac,
+	{0x1ea1, 0x302}: 0x1ead, {0x102, 0x301}: 0x1eae, {0x103, 0x301}: 0x1eaf, {0x102, 0x300}: 0x1eb0,
+	{0x103, 0x300}: 0x1eb1, {0x102, 0x309}: 0x1eb2, {0x103, 0x309}: 0x1eb3, {0x102, 0x303}: 0x1eb4,
+	{0x103, 0x303}: 0x1eb5, {0x1ea0, 0x306}: 0x1eb6, {0x1ea1, 0x306}: 0x1eb7, {0x45, 0x323}: 0x1eb8,
+	{0x65, 0x323}: 0x1eb9, {0x45, 0x309}: 0x1eba, {0x65, 0x309}: 0x1ebb, {0x45, 0x303}: 0x1ebc,
+	{0x65, 0x303}: 0x1ebd, {0xca, 0x301}: 0x1ebe, {0xea, 0x301}: 0x1ebf, {0xca, 0x300}: 0x1ec0,
+	{0xea, 0x300}: 0x1ec1, {0xca, 0x309}: 0x1ec2, {0xea, 0x309}: 0x1ec3, {0xca, 0x303}: 0x1ec4,
+	{0xea, 0x303}: 0x1ec5, {0x1eb8, 0x302}: 0x1ec6, {0x1eb9, 0x302}: 0x1ec7, {0x49, 0x309}: 0x1ec8,
+	{0x69, 0x309}: 0x1ec9, {0x49, 0x323}: 0x1eca, {0x69, 0x323}: 0x1ecb, {0x4f, 0x323}: 0x1ecc,
+	{0x6f, 0x323}: 0x1ecd, {0x4f, 0x309}: 0x1ece, {0x6f, 0x309}: 0x1ecf, {0xd4, 0x301}: 0x1ed0,
+	{0xf4, 0x301}: 0x1ed1, {0xd4, 0x300}: 0x1ed2, {0xf4, 0x300}: 0x1ed3, {0xd4, 0x309}: 0x1ed4,
+	{0xf4, 0x309}: 0x1ed5, {0xd4, 0x303}: 0x1ed6, {0xf4, 0x303}: 0x1ed7, {0x1ecc, 0x302}: 0x1ed8,
+	{0x1ecd, 0x302}: 0x1ed9, {0x1a0, 0x301}: 0x1eda, {0x1a1, 0x301}: 0x1edb, {0x1a0, 0x300}: 0x1edc,
+	{0x1a1, 0x300}: 0x1edd, {0x1a0, 0x309}: 0x1ede, {0x1a1, 0x309}: 0x1edf, {0x1a0, 0x303}: 0x1ee0,
+	{0x1a1, 0x303}: 0x1ee1, {0x1a0, 0x323}: 0x1ee2, {0x1a1, 0x323}: 0x1ee3, {0x55, 0x323}: 0x1ee4,
+	{0x75, 0x323}: 0x1ee5, {0x55, 0x309}: 0x1ee6, {0x75, 0x309}: 0x1ee7, {0x1af, 0x301}: 0x1ee8,
+	{0x1b0, 0x301}: 0x1ee9, {0x1af, 0x300}: 0x1eea, {0x1b0, 0x300}: 0x1eeb, {0x1af, 0x309}: 0x1eec,
+	{0x1b0, 0x309}: 0x1eed, {0x1af, 0x303}: 0x1eee, {0x1b0, 0x303}: 0x1eef, {0x1af, 0x323}: 0x1ef0,
+	{0x1b0, 0x323}: 0x1ef1, {0x59, 0x300}: 0x1ef2, {0x79, 0x300}: 0x1ef3, {0x59, 0x323}: 0x1ef4,
+	{0x79, 0x323}: 0x1ef5, {0x59, 0x309}: 0x1ef6, {0x79, 0x309}: 0x1ef7, {0x59, 0x303}: 0x1ef8,
+	{0x79, 0x303}: 0x1ef9, {0x3b1, 0x313}: 0x1f00, {0x3b1, 0x314}: 0x1f01, {0x1f00, 0x300}: 0x1f02,
+	{0x1f01, 0x300}: 0x1f03, {0x1f00, 0x301}: 0x1f04, {0x1f01, 0x301}: 0x1f05, {0x1f00, 0x342}: 0x1f06,
+	{0x1f01, 0x342}: 0x1f07, {0x391, 0x313}: 0x1f08, {0x391, 0x314}: 0x1f09, {0x1f08, 0x300}: 0x1f0a,
+	{0x1f09, 0x300}: 0x1f0b, {0x1f08, 0x301}: 0x1f0c, {0x1f09, 0x301}: 0x1f0d, {0x1f08, 0x342}: 0x1f0e,
+	{0x1f09, 0x342}: 0x1f0f, {0x3b5, 0x313}: 0x1f10, {0x3b5, 0x314}: 0x1f11, {0x1f10, 0x300}: 0x1f12,
+	{0x1f11, 0x300}: 0x1f13, {0x1f10, 0x301}: 0x1f14, {0x1f11, 0x301}: 0x1f15, {0x395, 0x313}: 0x1f18,
+	{0x395, 0x314}: 0x1f19, {0x1f18, 0x300}: 0x1f1a, {0x1f19, 0x300}: 0x1f1b, {0x1f18, 0x301}: 0x1f1c,
+	{0x1f19, 0x301}: 0x1f1d, {0x3b7, 0x313}: 0x1f20, {0x3b7, 0x314}: 0x1f21, {0x1f20, 0x300}: 0x1f22,
+	{0x1f21, 0x300}: 0x1f23, {0x1f20, 0x301}: 0x1f24, {0x1f21, 0x301}: 0x1f25, {0x1f20, 0x342}: 0x1f26,
+	{0x1f21, 0x342}: 0x1f27, {0x397, 0x313}: 0x1f28, {0x397, 0x314}: 0x1f29, {0x1f28, 0x300}: 0x1f2a,
+	{0x1f29, 0x300}: 0x1f2b, {0x1f28, 0x301}: 0x1f2c, {0x1f29, 0x301}: 0x1f2d, {0x1f28, 0x342}: 0x1f2e,
+	{0x1f29, 0x342}: 0x1f2f, {0x3b9, 0x313}: 0x1f30, {0x3b9, 0x314}: 0x1f31, {0x1f30, 0x300}: 0x1f32,
+	{0x1f31, 0x300}: 0x1f33, {0x1f30, 0x301}: 0x1f34, {0x1f31, 0x301}: 0x1f35, {0x1f30, 0x342}: 0x1f36,
+	{0x1f31, 0x342}: 0x1f37, {0x399, 0x313}: 0x1f38, {0x399, 0x314}: 0x1f39, {0x1f38, 0x300}: 0x1f3a,
+	{0x1f39, 0x300}: 0x1f3b, {0x1f38, 0x301}: 0x1f3c, {0x1f39, 0x301}: 0x1f3d, {0x1f38, 0x342}: 0x1f3e,
+	{0x1f39, 0x342}: 0x1f3f, {0x3bf, 0x313}: 0x1f40, {0x3bf, 0x314}: 0x1f41, {0x1f40, 0x300}: 0x1f42,
+	{0x1f41, 0x300}: 0x1f43, {0x1f40, 0x301}: 0x1f44, {0x1f41, 0x301}: 0x1f45, {0x39f, 0x313}: 0x1f48,
+	{0x39f, 0x314}: 0x1f49, {0x1f48, 0x300}: 0x1f4a, {0x1f49, 0x300}: 0x1f4b, {0x1f48, 0x301}: 0x1f4c,
+	{0x1f49, 0x301}: 0x1f4d, {0x3c5, 0x313}: 0x1f50, {0x3c5, 0x314}: 0x1f51, {0x1f50, 0x300}: 0x1f52,
+	{0x1f51, 0x300}: 0x1f53, {0x1f50, 0x301}: 0x1f54, {0x1f51, 0x301}: 0x1f55, {0x1f50, 0x342}: 0x1f56,
+	{0x1f51, 0x342}: 0x1f57, {0x3a5, 0x314}: 0x1f59, {0x1f59, 0x300}: 0x1f5b, {0x1f59, 0x301}: 0x1f5d,
+	{0x1f59, 0x342}: 0x1f5f, {0x3c9, 0x313}: 0x1f60, {0x3c9, 0x314}: 0x1f61, {0x1f60, 0x300}: 0x1f62,
+	{0x1f61, 0x300}: 0x1f63, {0x1f60, 0x301}: 0x1f64, {0x1f61, 0x301}: 0x1f65, {0x1f60, 0x342}: 0x1f66,
+	{0x1f61, 0x342}: 0x1f67, {0x3a9, 0x313}: 0x1f68, {0x3a9, 0x314}: 0x1f69, {0x1f68, 0x300}: 0x1f6a,
+	{0x1f69, 0x300}: 0x1f6b, {0x1f68, 0x301}: 0x1f6c, {0x1f69, 0x301}: 0x1f6d, {0x1f68, 0x342}: 0x1f6e,
+	{0x1f69, 0x342}: 0x1f6f, {0x3b1, 0x300}: 0x1f70, {0x3b5, 0x300}: 0x1f72, {0x3b7, 0x300}: 0x1f74,
+	{0x3b9, 0x300}: 0x1f76, {0x3bf, 0x300}: 0x1f78, {0x3c5, 0x300}: 0x1f7a, {0x3c9, 0x300}: 0x1f7c,
+	{0x1f00, 0x345}: 0x1f80, {0x1f01, 0x345}: 0x1f81, {0x1f02, 0x345}: 0x1f82, {0x1f03, 0x345}: 0x1f83,
+	{0x1f04, 0x345}: 0x1f84, {0x1f05, 0x345}: 0x1f85, {0x1f06, 0x345}: 0x1f86, {0x1f07, 0x345}: 0x1f87,
+	{0x1f08, 0x345}: 0x1f88, {0x1f09, 0x345}: 0x1f89, {0x1f0a, 0x345}: 0x1f8a, {0x1f0b, 0x345}: 0x1f8b,
+	{0x1f0c, 0x345}: 0x1f8c, {0x1f0d, 0x345}: 0x1f8d, {0x1f0e, 0x345}: 0x1f8e, {0x1f0f, 0x345}: 0x1f8f,
+	{0x1f20, 0x345}: 0x1f90, {0x1f21, 0x345}: 0x1f91, {0x1f22, 0x345}: 0x1f92, {0x1f23, 0x345}: 0x1f93,
+	{0x1f24, 0x345}: 0x1f94, {0x1f25, 0x345}: 0x1f95, {0x1f26, 0x345}: 0x1f96, {0x1f27, 0x345}: 0x1f97,
+	{0x1f28, 0x345}: 0x1f98, {0x1f29, 0x345}: 0x1f99, {0x1f2a, 0x345}: 0x1f9a, {0x1f2b, 0x345}: 0x1f9b,
+	{0x1f2c, 0x345}: 0x1f9c, {0x1f2d, 0x345}: 0x1f9d, {0x1f2e, 0x345}: 0x1f9e, {0x1f2f, 0x345}: 0x1f9f,
+	{0x1f60, 0x345}: 0x1fa0, {0x1f61, 0x345}: 0x1fa1, {0x1f62, 0x345}: 0x1fa2, {0x1f63, 0x345}: 0x1fa3,
+	{0x1f64, 0x345}: 0x1fa4, {0x1f65, 0x345}: 0x1fa5, {0x1f66, 0x345}: 0x1fa6, {0x1f67, 0x345}: 0x1fa7,
+	{0x1f68, 0x345}: 0x1fa8, {0x1f69, 0x345}: 0x1fa9, {0x1f6a, 0x345}: 0x1faa, {0x1f6b, 0x345}: 0x1fab,
+	{0x1f6c, 0x345}: 0x1fac, {0x1f6d, 0x345}: 0x1fad, {0x1f6e, 0x345}: 0x1fae, {0x1f6f, 0x345}: 0x1faf,
+	{0x3b1, 0x306}: 0x1fb0, {0x3b1, 0x304}: 0x1fb1, {0x1f70, 0x345}: 0x1fb2, {0x3b1, 0x345}: 0x1fb3,
+	{0x3ac, 0x345}: 0x1fb4, {0x3b1, 0x342}: 0x1fb6, {0x1fb6, 0x345}: 0x1fb7, {0x391, 0x306}: 0x1fb8,
+	{0x391, 0x304}: 0x1fb9, {0x391, 0x300}: 0x1fba, {0x391, 0x345}: 0x1fbc, {0xa8, 0x342}: 0x1fc1,
+	{0x1f74, 0x345}: 0x1fc2, {0x3b7, 0x345}: 0x1fc3, {0x3ae, 0x345}: 0x1fc4, {0x3b7, 0x342}: 0x1fc6,
+	{0x1fc6, 0x345}: 0x1fc7, {0x395, 0x300}: 0x1fc8, {0x397, 0x300}: 0x1fca, {0x397, 0x345}: 0x1fcc,
+	{0x1fbf, 0x300}: 0x1fcd, {0x1fbf, 0x301}: 0x1fce, {0x1fbf, 0x342}: 0x1fcf, {0x3b9, 0x306}: 0x1fd0,
+	{0x3b9, 0x304}: 0x1fd1, {0x3ca, 0x300}: 0x1fd2, {0x3b9, 0x342}: 0x1fd6, {0x3ca, 0x342}: 0x1fd7,
+	{0x399, 0x306}: 0x1fd8, {0x399, 0x304}: 0x1fd9, {0x399, 0x300}: 0x1fda, {0x1ffe, 0x300}: 0x1fdd,
+	{0x1ffe, 0x301}: 0x1fde, {0x1ffe, 0x342}: 0x1fdf, {0x3c5, 0x306}: 0x1fe0, {0x3c5, 0x304}: 0x1fe1,
+	{0x3cb, 0x300}: 0x1fe2, {0x3c1, 0x313}: 0x1fe4, {0x3c1, 0x314}: 0x1fe5, {0x3c5, 0x342}: 0x1fe6,
+	{0x3cb, 0x342}: 0x1fe7, {0x3a5, 0x306}: 0x1fe8, {0x3a5, 0x304}: 0x1fe9, {0x3a5, 0x300}: 0x1fea,
+	{0x3a1, 0x314}: 0x1fec, {0xa8, 0x300}: 0x1fed, {0x1f7c, 0x345}: 0x1ff2, {0x3c9, 0x345}: 0x1ff3,
+	{0x3ce, 0x345}: 0x1ff4, {0x3c9, 0x342}: 0x1ff6, {0x1ff6, 0x345}: 0x1ff7, {0x39f, 0x300}: 0x1ff8,
+	{0x3a9, 0x300}: 0x1ffa, {0x3a9, 0x345}: 0x1ffc, {0x2190, 0x338}: 0x219a, {0x2192, 0x338}: 0x219b,
+	{0x2194, 0x338}: 0x21ae, {0x21d0, 0x338}: 0x21cd, {0x21d4, 0x338}: 0x21ce, {0x21d2, 0x338}: 0x21cf,
+	{0x2203, 0x338}: 0x2204, {0x2208, 0x338}: 0x2209, {0x220b, 0x338}: 0x220c, {0x2223, 0x338}: 0x2224,
+	{0x2225, 0x338}: 0x2226, {0x223c, 0x338}: 0x2241, {0x2243, 0x338}: 0x2244, {0x2245, 0x338}: 0x2247,
+	{0x2248, 0x338}: 0x2249, {0x3d, 0x338}: 0x2260, {0x2261, 0x338}: 0x2262, {0x224d, 0x338}: 0x226d,
+	{0x3c, 0x338}: 0x226e, {0x3e, 0x338}: 0x226f, {0x2264, 0x338}: 0x2270, {0x2265, 0x338}: 0x2271,
+	{0x2272, 0x338}: 0x2274, {0x2273, 0x338}: 0x2275, {0x2276, 0x338}: 0x2278, {0x2277, 0x338}: 0x2279,
+	{0x227a, 0x338}: 0x2280, {0x227b, 0x338}: 0x2281, {0x2282, 0x338}: 0x2284, {0x2283, 0x338}: 0x2285,
+	{0x2286, 0x338}: 0x2288, {0x2287, 0x338}: 0x2289, {0x22a2, 0x338}: 0x22ac, {0x22a8, 0x338}: 0x22ad,
+	{0x22a9, 0x338}: 0x22ae, {0x22ab, 0x338}: 0x22af, {0x227c, 0x338}: 0x22e0, {0x227d, 0x338}: 0x22e1,
+	{0x2291, 0x338}: 0x22e2, {0x2292, 0x338}: 0x22e3, {0x22b2, 0x338}: 0x22ea, {0x22b3, 0x338}: 0x22eb,
+	{0x22b4, 0x338}: 0x22ec, {0x22b5, 0x338}: 0x22ed, {0x304b, 0x3099}: 0x304c, {0x304d, 0x3099}: 0x304e,
+	{0x304f, 0x3099}: 0x3050, {0x3051, 0x3099}: 0x3052, {0x3053, 0x3099}: 0x3054, {0x3055, 0x3099}: 0x3056,
+	{0x3057, 0x3099}: 0x3058, {0x3059, 0x3099}: 0x305a, {0x305b, 0x3099}: 0x305c, {0x305d, 0x3099}: 0x305e,
+	{0x305f, 0x3099}: 0x3060, {0x3061, 0x3099}: 0x3062, {0x3064, 0x3099}: 0x3065, {0x3066, 0x3099}: 0x3067,
+	{0x3068, 0x3099}: 0x3069, {0x306f, 0x3099}: 0x3070, {0x306f, 0x309a}: 0x3071, {0x3072, 0x3099}: 0x3073,
+	{0x3072, 0x309a}: 0x3074, {0x3075, 0x3099}: 0x3076, {0x3075, 0x309a}: 0x3077, {0x3078, 0x3099}: 0x3079,
+	{0x3078, 0x309a}: 0x307a, {0x307b, 0x3099}: 0x307c, {0x307b, 0x309a}: 0x307d, {0x3046, 0x3099}: 0x3094,
+	{0x309d, 0x3099}: 0x309e, {0x30ab, 0x3099}: 0x30ac, {0x30ad, 0x3099}: 0x30ae, {0x30af, 0x3099}: 0x30b0,
+	{0x30b1, 0x3099}: 0x30b2, {0x30b3, 0x3099}: 0x30b4, {0x30b5, 0x3099}: 0x30b6, {0x30b7, 0x3099}: 0x30b8,
+	{0x30b9, 0x3099}: 0x30ba, {0x30bb, 0x3099}: 0x30bc, {0x30bd, 0x3099}: 0x30be, {0x30bf, 0x3099}: 0x30c0,
+	{0x30c1, 0x3099}: 0x30c2, {0x30c4, 0x3099}: 0x30c5, {0x30c6, 0x3099}: 0x30c7, {0x30c8, 0x3099}: 0x30c9,
+	{0x30cf, 0x3099}: 0x30d0, {0x30cf, 0x309a}: 0x30d1, {0x30d2, 0x3099}: 0x30d3, {0x30d2, 0x309a}: 0x30d4,
+	{0x30d5, 0x3099}: 0x30d6, {0x30d5, 0x309a}: 0x30d7, {0x30d8, 0x3099}: 0x30d9, {0x30d8, 0x309a}: 0x30da,
+	{0x30db, 0x3099}: 0x30dc, {0x30db, 0x309a}: 0x30dd, {0x30a6, 0x3099}: 0x30f4, {0x30ef, 0x3099}: 0x30f7,
+	{0x30f0, 0x3099}: 0x30f8, {0x30f1, 0x3099}: 0x30f9, {0x30f2, 0x3099}: 0x30fa, {0x30fd, 0x3099}: 0x30fe,
+	{0x11099, 0x110ba}: 0x1109a, {0x1109b, 0x110ba}: 0x1109c, {0x110a5, 0x110ba}: 0x110ab, {0x11131, 0x11127}: 0x1112e,
+	{0x11132, 0x11127}: 0x1112f, {0x11347, 0x1133e}: 0x1134b, {0x11347, 0x11357}: 0x1134c, {0x114b9, 0x114ba}: 0x114bb,
+	{0x114b9, 0x114b0}: 0x114bc, {0x114b9, 0x114bd}: 0x114be, {0x115b8, 0x115af}: 0x115ba, {0x115b9, 0x115af}: 0x115bb,
+	{0x11935, 0x11930}: 0x11938,
+}
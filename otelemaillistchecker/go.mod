@@ -0,0 +1,17 @@
+module github.com/Emaillistchecker-io/emaillistchecker-go/otelemaillistchecker
+
+go 1.21
+
+require (
+	github.com/Emaillistchecker-io/emaillistchecker-go v0.0.0
+	go.opentelemetry.io/otel v1.24.0
+	go.opentelemetry.io/otel/trace v1.24.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.1 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	go.opentelemetry.io/otel/metric v1.24.0 // indirect
+)
+
+replace github.com/Emaillistchecker-io/emaillistchecker-go => ../
@@ -0,0 +1,101 @@
+// Package otelemaillistchecker adapts OpenTelemetry to the emaillistchecker
+// Tracer interface. It is a separate module so the client itself has no
+// dependencies.
+//
+//	client.SetTracer(otelemaillistchecker.NewTracer(nil, nil))
+//	result, err := client.WithContext(ctx).Verify(email, nil, false)
+//
+// Each client operation becomes a span such as "emaillistchecker.Verify" with a
+// client span per HTTP attempt, and the trace context is injected into the
+// outgoing request headers.
+package otelemaillistchecker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName identifies the spans created by this package
+const InstrumentationName = "github.com/Emaillistchecker-io/emaillistchecker-go"
+
+// Tracer implements emaillistchecker.Tracer with OpenTelemetry
+type Tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// Compile-time check that *Tracer implements emaillistchecker.Tracer
+var _ emaillistchecker.Tracer = (*Tracer)(nil)
+
+// NewTracer creates a tracer from provider and propagator. Nil values use the
+// global tracer provider and text map propagator.
+func NewTracer(provider trace.TracerProvider, propagator propagation.TextMapPropagator) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	return &Tracer{
+		tracer:     provider.Tracer(InstrumentationName),
+		propagator: propagator,
+	}
+}
+
+// Start implements emaillistchecker.Tracer. HTTP attempt spans are client spans.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, emaillistchecker.Span) {
+	kind := trace.SpanKindInternal
+	if strings.HasPrefix(name, "HTTP ") {
+		kind = trace.SpanKindClient
+	}
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(kind))
+	return ctx, &Span{span: span}
+}
+
+// Inject implements emaillistchecker.Tracer
+func (t *Tracer) Inject(ctx context.Context, header http.Header) {
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// Span wraps an OpenTelemetry span
+type Span struct {
+	span trace.Span
+}
+
+// SetAttribute implements emaillistchecker.Span
+func (s *Span) SetAttribute(key string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		s.span.SetAttributes(attribute.String(key, v))
+	case int:
+		s.span.SetAttributes(attribute.Int(key, v))
+	case int64:
+		s.span.SetAttributes(attribute.Int64(key, v))
+	case bool:
+		s.span.SetAttributes(attribute.Bool(key, v))
+	case float64:
+		s.span.SetAttributes(attribute.Float64(key, v))
+	default:
+		s.span.SetAttributes(attribute.String(key, fmt.Sprint(v)))
+	}
+}
+
+// RecordError implements emaillistchecker.Span
+func (s *Span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End implements emaillistchecker.Span
+func (s *Span) End() {
+	s.span.End()
+}
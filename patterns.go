@@ -0,0 +1,324 @@
+package emaillistchecker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPatternTTL is how long learned domain patterns are reused before FindByDomain is called again
+const DefaultPatternTTL = 7 * 24 * time.Hour
+
+// EmailPattern is a parsed address template such as "{first}.{last}" or "{f}{last}".
+// Supported placeholders are {first}, {last}, {f} / {first_initial} and {l} / {last_initial};
+// anything outside braces is copied literally.
+type EmailPattern struct {
+	Template string
+	parts    []patternPart
+}
+
+// patternPart is either a literal or a placeholder in an EmailPattern
+type patternPart struct {
+	literal     string
+	placeholder string
+}
+
+// ParsePattern parses an address template as reported by FindByDomain
+func ParsePattern(template string) (*EmailPattern, error) {
+	pattern := &EmailPattern{Template: template}
+
+	rest := strings.TrimSpace(template)
+	// Some responses include the domain, e.g. "{first}.{last}@example.com"
+	if at := strings.LastIndex(rest, "@"); at >= 0 {
+		rest = rest[:at]
+	}
+
+	for rest != "" {
+		open := strings.Index(rest, "{")
+		if open < 0 {
+			pattern.parts = append(pattern.parts, patternPart{literal: rest})
+			break
+		}
+		if open > 0 {
+			pattern.parts = append(pattern.parts, patternPart{literal: rest[:open]})
+		}
+
+		end := strings.Index(rest[open:], "}")
+		if end < 0 {
+			return nil, fmt.Errorf("pattern %q: unterminated placeholder", template)
+		}
+
+		name := strings.ToLower(rest[open+1 : open+end])
+		switch name {
+		case "first", "last":
+		case "f", "first_initial":
+			name = "f"
+		case "l", "last_initial":
+			name = "l"
+		default:
+			return nil, fmt.Errorf("pattern %q: unknown placeholder {%s}", template, name)
+		}
+		pattern.parts = append(pattern.parts, patternPart{placeholder: name})
+		rest = rest[open+end+1:]
+	}
+
+	if len(pattern.parts) == 0 {
+		return nil, fmt.Errorf("pattern %q is empty", template)
+	}
+	return pattern, nil
+}
+
+// LocalPart renders the pattern for a contact. Names are normalized with
+// NormalizeFirstName and NormalizeLastName, and compound first names are joined;
+// ok is false if a required name is empty or has no ASCII spelling.
+func (p *EmailPattern) LocalPart(firstName, lastName string) (string, bool) {
+	first := strings.ReplaceAll(NormalizeFirstName(firstName), " ", "")
+	last := NormalizeLastName(lastName)
+	if !isASCII(first) || !isASCII(last) {
+		return "", false
+	}
+
+	var b strings.Builder
+	for _, part := range p.parts {
+		switch part.placeholder {
+		case "":
+			b.WriteString(part.literal)
+		case "first":
+			if first == "" {
+				return "", false
+			}
+			b.WriteString(first)
+		case "last":
+			if last == "" {
+				return "", false
+			}
+			b.WriteString(last)
+		case "f":
+			if first == "" {
+				return "", false
+			}
+			b.WriteString(first[:1])
+		case "l":
+			if last == "" {
+				return "", false
+			}
+			b.WriteString(last[:1])
+		}
+	}
+	return b.String(), true
+}
+
+// WeightedPattern is a pattern together with the share of addresses that follow it
+type WeightedPattern struct {
+	Pattern   *EmailPattern
+	Frequency float64
+}
+
+// DomainPatterns holds the patterns learned for a domain
+type DomainPatterns struct {
+	Domain    string
+	Patterns  []WeightedPattern
+	FetchedAt time.Time
+}
+
+// AddressCandidate is a generated address ranked by the frequency of its pattern
+type AddressCandidate struct {
+	Email        string
+	Pattern      string
+	Frequency    float64
+	Verification *VerifyResponse
+}
+
+// PatternCache learns address patterns per domain from FindByDomain and
+// generates candidate addresses locally for new contacts
+type PatternCache struct {
+	client *Client
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu       sync.Mutex
+	domains  map[string]*DomainPatterns
+	fetching map[string]*patternFetch
+}
+
+// patternFetch is a FindByDomain call shared by concurrent lookups of a domain
+type patternFetch struct {
+	done     chan struct{}
+	patterns *DomainPatterns
+	err      error
+}
+
+// NewPatternCache creates a pattern cache backed by client. A ttl of zero uses DefaultPatternTTL.
+func NewPatternCache(client *Client, ttl time.Duration) *PatternCache {
+	if ttl <= 0 {
+		ttl = DefaultPatternTTL
+	}
+	return &PatternCache{
+		client:   client,
+		ttl:      ttl,
+		now:      time.Now,
+		domains:  make(map[string]*DomainPatterns),
+		fetching: make(map[string]*patternFetch),
+	}
+}
+
+// Patterns returns the patterns for domain, calling FindByDomain only when
+// the domain has not been seen or its entry has expired. Concurrent lookups
+// of the same domain share one call.
+func (pc *PatternCache) Patterns(domain string) (*DomainPatterns, error) {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+
+	pc.mu.Lock()
+	if cached, ok := pc.domains[domain]; ok {
+		if !pc.expiredLocked(cached) {
+			pc.mu.Unlock()
+			return cached, nil
+		}
+		delete(pc.domains, domain)
+	}
+	if fetch, ok := pc.fetching[domain]; ok {
+		pc.mu.Unlock()
+		<-fetch.done
+		return fetch.patterns, fetch.err
+	}
+	fetch := &patternFetch{done: make(chan struct{})}
+	pc.fetching[domain] = fetch
+	pc.mu.Unlock()
+
+	defer func() {
+		pc.mu.Lock()
+		delete(pc.fetching, domain)
+		pc.mu.Unlock()
+		close(fetch.done)
+	}()
+
+	data, err := pc.client.FindByDomain(domain, 10, 0)
+	if err != nil {
+		fetch.err = err
+		return nil, err
+	}
+
+	fetch.patterns = &DomainPatterns{
+		Domain:    domain,
+		Patterns:  parseDomainPatterns(data["patterns"]),
+		FetchedAt: pc.now(),
+	}
+	pc.Set(fetch.patterns)
+
+	return fetch.patterns, nil
+}
+
+// Set stores patterns for a domain, for example ones loaded from a previous run.
+// Expired entries of other domains are evicted at the same time, so the cache
+// only grows with the domains looked up within the TTL.
+func (pc *PatternCache) Set(patterns *DomainPatterns) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	for domain, cached := range pc.domains {
+		if pc.expiredLocked(cached) {
+			delete(pc.domains, domain)
+		}
+	}
+	pc.domains[strings.ToLower(patterns.Domain)] = patterns
+}
+
+// expiredLocked reports whether an entry is older than the TTL
+func (pc *PatternCache) expiredLocked(patterns *DomainPatterns) bool {
+	return pc.now().Sub(patterns.FetchedAt) >= pc.ttl
+}
+
+// Candidates generates addresses for a contact, most frequent pattern first
+func (pc *PatternCache) Candidates(firstName, lastName, domain string) ([]AddressCandidate, error) {
+	learned, err := pc.Patterns(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var candidates []AddressCandidate
+	for _, wp := range learned.Patterns {
+		local, ok := wp.Pattern.LocalPart(firstName, lastName)
+		if !ok || local == "" {
+			continue
+		}
+
+		email := local + "@" + learned.Domain
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+
+		candidates = append(candidates, AddressCandidate{
+			Email:     email,
+			Pattern:   wp.Pattern.Template,
+			Frequency: wp.Frequency,
+		})
+	}
+
+	return candidates, nil
+}
+
+// Guess returns the best candidate for a contact and, if verify is set, checks it with Verify.
+// It returns nil if no pattern is known for the domain.
+func (pc *PatternCache) Guess(firstName, lastName, domain string, verify, smtpCheck bool) (*AddressCandidate, error) {
+	candidates, err := pc.Candidates(firstName, lastName, domain)
+	if err != nil || len(candidates) == 0 {
+		return nil, err
+	}
+
+	best := candidates[0]
+	if verify {
+		verification, err := pc.client.Verify(best.Email, nil, smtpCheck)
+		if err != nil {
+			return &best, err
+		}
+		best.Verification = verification
+	}
+
+	return &best, nil
+}
+
+// parseDomainPatterns converts the untyped "patterns" value from FindByDomain into
+// weighted patterns sorted by frequency. Entries may be plain templates or objects
+// with a "pattern" key and a frequency under "frequency", "percentage" or "count".
+func parseDomainPatterns(raw interface{}) []WeightedPattern {
+	entries, ok := raw.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var patterns []WeightedPattern
+	for i, entry := range entries {
+		var template string
+		var frequency float64
+
+		switch v := entry.(type) {
+		case string:
+			template = v
+			// Without reported frequencies keep the API's ordering
+			frequency = float64(len(entries) - i)
+		case map[string]interface{}:
+			template, _ = v["pattern"].(string)
+			for _, key := range []string{"frequency", "percentage", "count", "confidence"} {
+				if f, ok := v[key].(float64); ok {
+					frequency = f
+					break
+				}
+			}
+		}
+
+		pattern, err := ParsePattern(template)
+		if err != nil {
+			continue
+		}
+		patterns = append(patterns, WeightedPattern{Pattern: pattern, Frequency: frequency})
+	}
+
+	sort.SliceStable(patterns, func(i, j int) bool {
+		return patterns[i].Frequency > patterns[j].Frequency
+	})
+
+	return patterns
+}
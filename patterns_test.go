@@ -0,0 +1,30 @@
+package emaillistchecker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPatternCacheEvictsExpired(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pc := NewPatternCache(nil, time.Hour)
+	pc.now = func() time.Time { return now }
+
+	pc.Set(&DomainPatterns{Domain: "old.example", FetchedAt: now})
+	now = now.Add(30 * time.Minute)
+	pc.Set(&DomainPatterns{Domain: "new.example", FetchedAt: now})
+
+	now = now.Add(45 * time.Minute)
+	pc.Set(&DomainPatterns{Domain: "Other.Example", FetchedAt: now})
+	if _, ok := pc.domains["old.example"]; ok {
+		t.Error("expired old.example was kept")
+	}
+	if len(pc.domains) != 2 {
+		t.Errorf("cache holds %d domains, want 2", len(pc.domains))
+	}
+
+	cached, err := pc.Patterns("new.example")
+	if err != nil || cached.Domain != "new.example" {
+		t.Errorf("Patterns(new.example) = %v, %v, want the cached entry", cached, err)
+	}
+}
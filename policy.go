@@ -0,0 +1,124 @@
+package emaillistchecker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Action is what a Policy decides to do with a verified address
+type Action string
+
+// Policy actions, from least to most severe
+const (
+	ActionKeep   Action = "keep"
+	ActionReview Action = "review"
+	ActionRemove Action = "remove"
+)
+
+// actionSeverity orders actions so the strictest rule wins
+var actionSeverity = map[Action]int{
+	ActionKeep:   0,
+	ActionReview: 1,
+	ActionRemove: 2,
+}
+
+// Policy maps verification results to list hygiene actions.
+// The action for the result is taken first; flag and score rules can only make it stricter.
+type Policy struct {
+	// Results maps a result (deliverable, risky, unknown, undeliverable) to an action.
+	// Results missing from the map are reviewed.
+	Results map[string]Action `json:"results"`
+
+	// Actions applied when the corresponding flag is set
+	Disposable Action `json:"disposable,omitempty"`
+	Role       Action `json:"role,omitempty"`
+	Free       Action `json:"free,omitempty"`
+	SpamTrap   Action `json:"spam_trap,omitempty"`
+
+	// Addresses scoring below these thresholds are reviewed or removed
+	ReviewBelowScore float64 `json:"review_below_score,omitempty"`
+	RemoveBelowScore float64 `json:"remove_below_score,omitempty"`
+}
+
+// DefaultPolicy keeps deliverable addresses, reviews risky and unknown ones and
+// removes undeliverable, disposable and spam-trap addresses
+func DefaultPolicy() *Policy {
+	return &Policy{
+		Results: map[string]Action{
+			"deliverable":   ActionKeep,
+			"risky":         ActionReview,
+			"unknown":       ActionReview,
+			"undeliverable": ActionRemove,
+		},
+		Disposable: ActionRemove,
+		SpamTrap:   ActionRemove,
+	}
+}
+
+// LoadPolicy reads a JSON policy file. Settings in the file override DefaultPolicy.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+
+	policy := DefaultPolicy()
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %w", path, err)
+	}
+	if err := policy.validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", path, err)
+	}
+	return policy, nil
+}
+
+// Decide returns the action for a verification result
+func (p *Policy) Decide(r *VerifyResponse) Action {
+	action, ok := p.Results[r.Result]
+	if !ok {
+		action = ActionReview
+	}
+
+	stricter := func(candidate Action) {
+		if actionSeverity[candidate] > actionSeverity[action] {
+			action = candidate
+		}
+	}
+
+	if r.Disposable {
+		stricter(p.Disposable)
+	}
+	if r.Role {
+		stricter(p.Role)
+	}
+	if r.Free {
+		stricter(p.Free)
+	}
+	if r.SpamTrap {
+		stricter(p.SpamTrap)
+	}
+	if r.Score < p.ReviewBelowScore {
+		stricter(ActionReview)
+	}
+	if r.Score < p.RemoveBelowScore {
+		stricter(ActionRemove)
+	}
+
+	return action
+}
+
+// validate checks that every action in the policy is known
+func (p *Policy) validate() error {
+	actions := []Action{p.Disposable, p.Role, p.Free, p.SpamTrap}
+	for _, a := range p.Results {
+		actions = append(actions, a)
+	}
+
+	for _, a := range actions {
+		if _, ok := actionSeverity[a]; !ok && a != "" {
+			return fmt.Errorf("unknown action %q", a)
+		}
+	}
+	return nil
+}
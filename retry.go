@@ -0,0 +1,152 @@
+package emaillistchecker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first (default 3)
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry, doubled on each further retry (default 500ms)
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between attempts, including Retry-After (default 30s)
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the policy used by SetRetry when fields are zero
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+	}
+}
+
+// SetRetry makes the client retry requests the API cannot have processed: 429
+// responses and 503 responses with Retry-After. GET requests are also retried
+// after other gateway errors and network failures. Other requests, such as
+// verifications and uploads, are never repeated after those, since the first
+// attempt may have been charged.
+func (c *Client) SetRetry(policy RetryPolicy) {
+	c.retry = &policy
+}
+
+// RetryMiddleware retries requests according to policy. Each attempt's context
+// carries its attempt number, which LoggingMiddleware reports.
+func RetryMiddleware(policy RetryPolicy) Middleware {
+	defaults := DefaultRetryPolicy()
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaults.MaxAttempts
+	}
+	if policy.MinBackoff <= 0 {
+		policy.MinBackoff = defaults.MinBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = defaults.MaxBackoff
+	}
+
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead
+			backoff := policy.MinBackoff
+
+			for attempt := 1; ; attempt++ {
+				attemptReq := req.WithContext(withAttempt(ctx, attempt))
+				if attempt > 1 && req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, err
+					}
+					attemptReq.Body = body
+				}
+
+				resp, err := next.Do(attemptReq)
+
+				last := attempt >= policy.MaxAttempts || (req.Body != nil && req.GetBody == nil)
+				if last || !shouldRetry(ctx, resp, err, idempotent) {
+					return resp, err
+				}
+
+				delay := backoff
+				if resp != nil {
+					if retryAfter, ok := retryAfterDelay(resp); ok {
+						delay = retryAfter
+					}
+					io.Copy(io.Discard, resp.Body)
+					resp.Body.Close()
+				}
+				if delay > policy.MaxBackoff {
+					delay = policy.MaxBackoff
+				}
+				delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+
+				timer := time.NewTimer(delay)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, ctx.Err()
+				}
+
+				backoff *= 2
+			}
+		})
+	}
+}
+
+// shouldRetry reports whether a failed attempt is worth repeating. Only
+// idempotent requests are retried when the API may have processed them.
+func shouldRetry(ctx context.Context, resp *http.Response, err error, idempotent bool) bool {
+	if err != nil {
+		if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+			return false
+		}
+		return idempotent
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusServiceUnavailable:
+		if _, ok := retryAfterDelay(resp); ok {
+			return true
+		}
+		return idempotent
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// retryAfterDelay parses the Retry-After header in seconds
+func retryAfterDelay(resp *http.Response) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// attemptKey is the context key holding the current attempt number
+type attemptKey struct{}
+
+// withAttempt records the attempt number of a request in its context
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// attemptFromContext returns the attempt number of a request, 1 if unset
+func attemptFromContext(ctx context.Context) int {
+	if attempt, ok := ctx.Value(attemptKey{}).(int); ok {
+		return attempt
+	}
+	return 1
+}
@@ -0,0 +1,65 @@
+package emaillistchecker_test
+
+import (
+	"testing"
+	"time"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		fault    emaillistcheckertest.Fault
+		call     func(c *emaillistchecker.Client) error
+		requests int
+		ok       bool
+	}{
+		{"POST after 429", "/verify", emaillistcheckertest.Fault{Status: 429, Times: 1},
+			verifyJane, 2, true},
+		{"POST after 503 with Retry-After", "/verify", emaillistcheckertest.Fault{Status: 503, RetryAfter: 1, Times: 1},
+			verifyJane, 2, true},
+		// The verification may have been charged, so it is not repeated
+		{"POST after 503", "/verify", emaillistcheckertest.Fault{Status: 503, Times: 1},
+			verifyJane, 1, false},
+		{"POST after 502", "/verify", emaillistcheckertest.Fault{Status: 502, Times: 1},
+			verifyJane, 1, false},
+		{"batch POST after 503", "/verify/batch", emaillistcheckertest.Fault{Status: 503, Times: 1},
+			func(c *emaillistchecker.Client) error {
+				_, err := c.VerifyBatch([]string{"jane@example.com"}, "test", "", true)
+				return err
+			}, 1, false},
+		{"GET after 503", "/credits", emaillistcheckertest.Fault{Status: 503, Times: 1},
+			func(c *emaillistchecker.Client) error {
+				_, err := c.GetCredits()
+				return err
+			}, 2, true},
+		{"GET after 400", "/credits", emaillistcheckertest.Fault{Status: 400, Times: 1},
+			func(c *emaillistchecker.Client) error {
+				_, err := c.GetCredits()
+				return err
+			}, 1, false},
+	}
+	for _, tt := range tests {
+		srv := emaillistcheckertest.NewServer()
+		client := srv.Client()
+		client.SetRetry(emaillistchecker.RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+		srv.Fail(tt.path, tt.fault)
+
+		err := tt.call(client)
+		if ok := err == nil; ok != tt.ok {
+			t.Errorf("%s: err = %v, want success %v", tt.name, err, tt.ok)
+		}
+		if n := len(srv.Requests()); n != tt.requests {
+			t.Errorf("%s: sent %d requests, want %d", tt.name, n, tt.requests)
+		}
+		srv.Close()
+	}
+}
+
+func verifyJane(c *emaillistchecker.Client) error {
+	_, err := c.Verify("jane@example.com", nil, true)
+	return err
+}
@@ -0,0 +1,175 @@
+package emaillistchecker
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// Suggestion is a likely correction of a mistyped address
+type Suggestion struct {
+	// Email is the corrected address
+	Email string `json:"email"`
+	// Domain is the corrected domain
+	Domain string `json:"domain"`
+	// Confidence is how likely the correction is right, 0.0 - 1.0
+	Confidence float64 `json:"confidence"`
+}
+
+// popularDomains are corrected towards, most popular first so they win ties
+var popularDomains = []string{
+	"gmail.com", "yahoo.com", "hotmail.com", "outlook.com", "icloud.com", "aol.com",
+	"live.com", "googlemail.com", "protonmail.com", "comcast.net", "ymail.com",
+	"hotmail.co.uk", "yahoo.co.uk", "yahoo.fr", "hotmail.fr", "orange.fr", "gmx.com",
+	"gmx.de", "web.de", "t-online.de", "yandex.ru", "mail.ru", "libero.it",
+	"sbcglobal.net", "verizon.net", "bellsouth.net", "rocketmail.com", "fastmail.com",
+}
+
+// popularTLDs are corrected towards when the TLD of an unknown domain does not exist
+var popularTLDs = []string{
+	"com", "net", "org", "co.uk", "de", "fr", "it", "es", "nl", "ca", "com.au", "io", "edu", "info",
+}
+
+// bundledTLDs lists the top-level domains of the IANA root zone
+//
+//go:embed tlds.txt
+var bundledTLDs string
+
+// knownTLDs are real top-level domains, which are never corrected
+var knownTLDs = func() map[string]bool {
+	tlds := make(map[string]bool)
+	for _, line := range strings.Split(bundledTLDs, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			tlds[line] = true
+		}
+	}
+	return tlds
+}()
+
+// SuggestCorrection suggests a fix for a likely typo in the domain of email,
+// such as gmial.com or example.cmo, by edit distance to popular mail providers
+// and top-level domains. ok is false when the address looks right or no fix is
+// close enough. Known providers, including those in DefaultDomainLists, are
+// never corrected. A real top-level domain is only corrected towards a popular
+// provider one keystroke away whose name is also close, so gmail.co becomes
+// gmail.com but hotmail.es is left alone rather than turned into hotmail.fr.
+func SuggestCorrection(email string) (suggestion *Suggestion, ok bool) {
+	return suggestCorrection(email, defaultDomainLists)
+}
+
+// suggestCorrection is SuggestCorrection with lists deciding which providers are known
+func suggestCorrection(email string, lists *DomainLists) (*Suggestion, bool) {
+	email = strings.TrimSpace(email)
+	at := strings.LastIndex(email, "@")
+	if at < 1 || at == len(email)-1 {
+		return nil, false
+	}
+	local, domain := email[:at], normalizeDomain(email[at+1:])
+
+	if isKnownDomain(domain, lists) {
+		return nil, false
+	}
+
+	labels := strings.Split(domain, ".")
+	tld := labels[len(labels)-1]
+	realTLD := knownTLDs[tld]
+
+	best, bestDistance := "", 0
+	for _, candidate := range popularDomains {
+		if realTLD && !strings.HasSuffix(candidate, "."+tld) && !nearProvider(domain, candidate) {
+			// Another country's TLD is not a typo unless it is one keystroke off
+			continue
+		}
+		d := editDistance(domain, candidate)
+		if d > 2 || d > len(candidate)/4 {
+			continue
+		}
+		if best == "" || d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	if best != "" {
+		return newSuggestion(local, best, bestDistance), true
+	}
+
+	// Otherwise fix a nonexistent TLD, e.g. example.cmo
+	if len(labels) < 2 || labels[0] == "" || realTLD {
+		return nil, false
+	}
+	for split := 1; split < len(labels); split++ {
+		name, tld := strings.Join(labels[:split], "."), strings.Join(labels[split:], ".")
+		for _, candidate := range popularTLDs {
+			if d := editDistance(tld, candidate); d == 1 && (best == "" || d < bestDistance) {
+				best, bestDistance = name+"."+candidate, d
+			}
+		}
+		if best != "" {
+			return newSuggestion(local, best, bestDistance), true
+		}
+	}
+	return nil, false
+}
+
+// nearProvider reports whether domain differs from a popular provider in
+// another TLD by at most one edit in the name and one in the TLD, as in
+// gmial.co for gmail.com
+func nearProvider(domain, candidate string) bool {
+	name, tld, _ := strings.Cut(domain, ".")
+	candidateName, candidateTLD, _ := strings.Cut(candidate, ".")
+	return editDistance(name, candidateName) <= 1 && editDistance(tld, candidateTLD) <= 1
+}
+
+func newSuggestion(local, domain string, distance int) *Suggestion {
+	confidence := 1 - float64(distance)/float64(len(domain))
+	return &Suggestion{
+		Email:      local + "@" + domain,
+		Domain:     domain,
+		Confidence: float64(int(confidence*100)) / 100,
+	}
+}
+
+// isKnownDomain reports whether domain is a provider that should not be corrected
+func isKnownDomain(domain string, lists *DomainLists) bool {
+	for _, popular := range popularDomains {
+		if domain == popular {
+			return true
+		}
+	}
+	return lists.IsFree(domain)
+}
+
+// editDistance is the optimal string alignment distance between a and b:
+// insertions, deletions, substitutions and transpositions of adjacent bytes
+func editDistance(a, b string) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && prev2[j-2]+1 < cur[j] {
+				cur[j] = prev2[j-2] + 1
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
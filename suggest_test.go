@@ -0,0 +1,81 @@
+package emaillistchecker
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"gmail.com", "gmail.com", 0},
+		{"", "abc", 3},
+		{"gmial.com", "gmail.com", 1},
+		{"gmai.com", "gmail.com", 1},
+		{"gmaill.com", "gmail.com", 1},
+		{"gnail.com", "gmail.com", 1},
+		{"hotmial.con", "hotmail.com", 2},
+		{"kitten", "sitting", 3},
+		// Optimal string alignment does not edit a substring twice
+		{"ca", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := editDistance(tt.b, tt.a); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestCorrection(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"jane@gmial.com", "jane@gmail.com"},
+		{"jane@gmai.com", "jane@gmail.com"},
+		{"jane@hotmial.com", "jane@hotmail.com"},
+		{"Jane@GMIAL.com", "Jane@gmail.com"},
+		{"jane@example.cmo", "jane@example.com"},
+		// Real TLDs one keystroke from a popular provider's
+		{"jane@yaho.co", "jane@yahoo.com"},
+		{"jane@gmail.co", "jane@gmail.com"},
+		{"jane@gmail.cm", "jane@gmail.com"},
+		{"jane@gmial.co", "jane@gmail.com"},
+		// Known domains and real TLDs are left alone
+		{"jane@gmail.com", ""},
+		{"jane@hotmail.es", ""},
+		{"jane@live.ca", ""},
+		{"jane@live.co.uk", ""},
+		{"jane@yahoo.ng", ""},
+		{"jane@yahoo.com.br", ""},
+		{"jane@shop.ng", ""},
+		{"jane@example.com", ""},
+		{"jane", ""},
+	}
+	for _, tt := range tests {
+		s, ok := SuggestCorrection(tt.email)
+		switch {
+		case tt.want == "" && ok:
+			t.Errorf("SuggestCorrection(%q) = %q, want no suggestion", tt.email, s.Email)
+		case tt.want != "" && !ok:
+			t.Errorf("SuggestCorrection(%q): no suggestion, want %q", tt.email, tt.want)
+		case ok && s.Email != tt.want:
+			t.Errorf("SuggestCorrection(%q) = %q, want %q", tt.email, s.Email, tt.want)
+		}
+	}
+}
+
+func TestSuggestCorrectionDomainLists(t *testing.T) {
+	lists := NewDomainLists()
+	lists.Learn(&VerifyResponse{Email: "jane@gmial.com", Free: true})
+
+	if s, ok := suggestCorrection("jane@gmial.com", lists); ok {
+		t.Errorf("suggestCorrection with gmial.com listed as free = %q, want no suggestion", s.Email)
+	}
+	if _, ok := suggestCorrection("jane@gmial.com", defaultDomainLists); !ok {
+		t.Error("suggestCorrection with the default lists: want a suggestion")
+	}
+}
@@ -0,0 +1,254 @@
+package emaillistchecker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+// batchOperations create batches; their response bodies are captured for the batch ID
+var batchOperations = map[string]bool{
+	"VerifyBatch":     true,
+	"VerifyBatchFile": true,
+}
+
+// Span attribute keys set by the client
+const (
+	AttrOperation  = "emaillistchecker.operation"
+	AttrEndpoint   = "emaillistchecker.endpoint"
+	AttrMethod     = "http.request.method"
+	AttrStatusCode = "http.response.status_code"
+	AttrAttempt    = "emaillistchecker.attempt"
+	AttrRetryCount = "emaillistchecker.retry_count"
+	AttrBatchID    = "emaillistchecker.batch_id"
+)
+
+// Tracer starts spans and propagates trace context. The otelemaillistchecker
+// module adapts an OpenTelemetry tracer to it.
+type Tracer interface {
+	// Start opens a span as a child of any span in ctx
+	Start(ctx context.Context, name string) (context.Context, Span)
+	// Inject writes the trace context of ctx into outgoing request headers
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is an open tracing span
+type Span interface {
+	// SetAttribute records a string, int or bool attribute
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// SetTracer opens a span per client operation, for example "emaillistchecker.Verify",
+// with a child span per HTTP attempt. The parent span is taken from the context
+// given to WithContext. Pass nil to disable tracing.
+func (c *Client) SetTracer(tracer Tracer) {
+	c.tracer = tracer
+}
+
+// batchPath extracts the batch ID from batch status and results endpoints
+var batchPath = regexp.MustCompile(`^/verify/batch/(\d+)`)
+
+// traceState is shared between an operation span and its attempt spans
+type traceState struct {
+	mu       sync.Mutex
+	attempts int
+	retries  int
+
+	// span is set when the operation span was opened by traceOperation
+	span Span
+}
+
+type traceStateKey struct{}
+
+// traceOperation opens the span for an operation that may send several
+// requests, such as Verify when the response lacks the data wrapper. Requests
+// made through the returned client become attempts of that span instead of
+// operations of their own; end closes the span.
+func (c *Client) traceOperation(op string) (traced *Client, end func(error)) {
+	if c.tracer == nil {
+		return c, func(error) {}
+	}
+
+	ctx, span := c.tracer.Start(c.context(), "emaillistchecker."+op)
+	span.SetAttribute(AttrOperation, op)
+	state := &traceState{span: span}
+
+	return c.WithContext(context.WithValue(ctx, traceStateKey{}, state)), func(err error) {
+		state.mu.Lock()
+		if state.retries > 0 {
+			span.SetAttribute(AttrRetryCount, state.retries)
+		}
+		state.mu.Unlock()
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}
+}
+
+// operationTracing opens the span for a whole operation, around retries and
+// user middlewares, unless traceOperation already opened it
+func operationTracing(tracer Tracer) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			op := OperationName(req.Context())
+			endpoint := endpointFromContext(req.Context())
+
+			if state, ok := req.Context().Value(traceStateKey{}).(*traceState); ok && state.span != nil {
+				return state.traceRequest(next, req, endpoint)
+			}
+
+			ctx, span := tracer.Start(req.Context(), "emaillistchecker."+op)
+			span.SetAttribute(AttrOperation, op)
+			span.SetAttribute(AttrMethod, req.Method)
+			span.SetAttribute(AttrEndpoint, LogOptions{}.mask(endpoint))
+			if m := batchPath.FindStringSubmatch(endpoint); m != nil {
+				if id, err := strconv.Atoi(m[1]); err == nil {
+					span.SetAttribute(AttrBatchID, id)
+				}
+			}
+
+			state := &traceState{}
+			ctx = context.WithValue(ctx, traceStateKey{}, state)
+
+			resp, err := next.Do(req.WithContext(ctx))
+
+			state.mu.Lock()
+			if state.attempts > 1 {
+				span.SetAttribute(AttrRetryCount, state.attempts-1)
+			}
+			state.mu.Unlock()
+
+			if err != nil {
+				span.RecordError(err)
+				span.End()
+				return nil, err
+			}
+
+			span.SetAttribute(AttrStatusCode, resp.StatusCode)
+			resp.Body = &tracedBody{
+				ReadCloser: resp.Body,
+				span:       span,
+				status:     resp.StatusCode,
+				capture:    batchOperations[op],
+			}
+			return resp, nil
+		})
+	}
+}
+
+// traceRequest sends one request of an operation opened by traceOperation,
+// recording it on the operation span
+func (s *traceState) traceRequest(next Doer, req *http.Request, endpoint string) (*http.Response, error) {
+	s.span.SetAttribute(AttrMethod, req.Method)
+	s.span.SetAttribute(AttrEndpoint, LogOptions{}.mask(endpoint))
+
+	s.mu.Lock()
+	before := s.attempts
+	s.mu.Unlock()
+
+	resp, err := next.Do(req)
+
+	s.mu.Lock()
+	if sent := s.attempts - before; sent > 1 {
+		s.retries += sent - 1
+	}
+	s.mu.Unlock()
+
+	if err == nil {
+		s.span.SetAttribute(AttrStatusCode, resp.StatusCode)
+	}
+	return resp, err
+}
+
+// attemptTracing opens a child span for each HTTP attempt and injects its
+// trace context into the request headers
+func attemptTracing(tracer Tracer) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			attempt := attemptFromContext(req.Context())
+			if state, ok := req.Context().Value(traceStateKey{}).(*traceState); ok {
+				state.mu.Lock()
+				state.attempts++
+				state.mu.Unlock()
+			}
+
+			ctx, span := tracer.Start(req.Context(), "HTTP "+req.Method)
+			span.SetAttribute(AttrMethod, req.Method)
+			span.SetAttribute(AttrEndpoint, LogOptions{}.mask(endpointFromContext(ctx)))
+			span.SetAttribute(AttrAttempt, attempt)
+
+			req = req.WithContext(ctx)
+			req.Header = req.Header.Clone()
+			tracer.Inject(ctx, req.Header)
+
+			resp, err := next.Do(req)
+			if err != nil {
+				span.RecordError(err)
+				span.End()
+				return nil, err
+			}
+
+			span.SetAttribute(AttrStatusCode, resp.StatusCode)
+			resp.Body = &tracedBody{ReadCloser: resp.Body, span: span, status: resp.StatusCode}
+			return resp, nil
+		})
+	}
+}
+
+// tracedBody ends a span when the response body is closed. For batch
+// submissions it reads the batch ID from the response.
+type tracedBody struct {
+	io.ReadCloser
+	span    Span
+	status  int
+	capture bool
+	body    bytes.Buffer
+	once    sync.Once
+}
+
+func (b *tracedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if b.capture && b.body.Len() < maxLoggedBody {
+		b.body.Write(p[:n])
+	}
+	return n, err
+}
+
+func (b *tracedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		if b.status >= 400 {
+			b.span.RecordError(fmt.Errorf("HTTP %d", b.status))
+		} else if b.capture {
+			if id, ok := batchIDFromBody(b.body.Bytes()); ok {
+				b.span.SetAttribute(AttrBatchID, id)
+			}
+		}
+		b.span.End()
+	})
+	return err
+}
+
+// batchIDFromBody reads the ID of a created batch, with or without the data wrapper
+func batchIDFromBody(body []byte) (int, bool) {
+	var wrapped struct {
+		Data *BatchResponse `json:"data"`
+		BatchResponse
+	}
+	if err := json.Unmarshal(body, &wrapped); err != nil {
+		return 0, false
+	}
+	if wrapped.Data != nil && wrapped.Data.ID != 0 {
+		return wrapped.Data.ID, true
+	}
+	return wrapped.ID, wrapped.ID != 0
+}
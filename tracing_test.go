@@ -0,0 +1,120 @@
+package emaillistchecker_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+// recordingTracer keeps every span it opens
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+type recordingSpan struct {
+	name   string
+	parent *recordingSpan
+	attrs  map[string]interface{}
+	ended  bool
+}
+
+type spanKey struct{}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, emaillistchecker.Span) {
+	parent, _ := ctx.Value(spanKey{}).(*recordingSpan)
+	span := &recordingSpan{name: name, parent: parent, attrs: map[string]interface{}{}}
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (t *recordingTracer) Inject(ctx context.Context, header http.Header) {}
+
+func (s *recordingSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *recordingSpan) RecordError(err error)                      {}
+func (s *recordingSpan) End()                                       { s.ended = true }
+
+// named returns the spans called name
+func (t *recordingTracer) named(name string) []*recordingSpan {
+	var spans []*recordingSpan
+	for _, span := range t.spans {
+		if span.name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func TestTraceVerifyFallback(t *testing.T) {
+	// Responses without the data wrapper make Verify send the request again
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"email":"jane@example.com","result":"deliverable","score":0.9}`))
+	}))
+	defer srv.Close()
+
+	tracer := &recordingTracer{}
+	client := emaillistchecker.NewClientWithConfig("test_api_key", srv.URL, 5*time.Second)
+	client.SetTracer(tracer)
+
+	if _, err := client.Verify("jane@example.com", nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	ops := tracer.named("emaillistchecker.Verify")
+	if len(ops) != 1 {
+		t.Fatalf("got %d operation spans, want 1", len(ops))
+	}
+	if !ops[0].ended {
+		t.Error("operation span not ended")
+	}
+	if code := ops[0].attrs[emaillistchecker.AttrStatusCode]; code != 200 {
+		t.Errorf("status code = %v, want 200", code)
+	}
+	attempts := tracer.named("HTTP POST")
+	if len(attempts) != 2 {
+		t.Fatalf("got %d attempt spans, want 2", len(attempts))
+	}
+	for _, span := range attempts {
+		if span.parent != ops[0] {
+			t.Error("attempt span is not a child of the operation span")
+		}
+	}
+	if _, ok := ops[0].attrs[emaillistchecker.AttrRetryCount]; ok {
+		t.Error("the fallback request was counted as a retry")
+	}
+}
+
+func TestTraceRetries(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	srv.Fail("/verify", emaillistcheckertest.Fault{Status: 429, Times: 1})
+
+	tracer := &recordingTracer{}
+	client := srv.Client()
+	client.SetTracer(tracer)
+	client.SetRetry(emaillistchecker.RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+	if _, err := client.Verify("deliverable@example.com", nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	ops := tracer.named("emaillistchecker.Verify")
+	if len(ops) != 1 {
+		t.Fatalf("got %d operation spans, want 1", len(ops))
+	}
+	if retries := ops[0].attrs[emaillistchecker.AttrRetryCount]; retries != 1 {
+		t.Errorf("retry count = %v, want 1", retries)
+	}
+	if attempts := tracer.named("HTTP POST"); len(attempts) != 2 {
+		t.Errorf("got %d attempt spans, want 2", len(attempts))
+	}
+}
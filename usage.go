@@ -0,0 +1,293 @@
+package emaillistchecker
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Granularity is the bucket size of a usage time series
+type Granularity string
+
+// Usage granularities
+const (
+	GranularityHour  Granularity = "hour"
+	GranularityDay   Granularity = "day"
+	GranularityWeek  Granularity = "week"
+	GranularityMonth Granularity = "month"
+)
+
+// UsageQuery selects the period and bucket size of a usage report
+type UsageQuery struct {
+	// From is the inclusive start of the period; zero means the start of the account
+	From time.Time
+	// To is the exclusive end of the period; zero means now
+	To time.Time
+	// Granularity splits the period into a time series; empty returns totals only
+	Granularity Granularity
+}
+
+// UsageReport is the API usage for a period
+type UsageReport struct {
+	From               string       `json:"from"`
+	To                 string       `json:"to"`
+	Granularity        Granularity  `json:"granularity"`
+	TotalRequests      int          `json:"total_requests"`
+	SuccessfulRequests int          `json:"successful_requests"`
+	FailedRequests     int          `json:"failed_requests"`
+	CreditsUsed        int          `json:"credits_used"`
+	Series             []UsagePoint `json:"series"`
+}
+
+// UsagePoint is the usage within one bucket of a time series
+type UsagePoint struct {
+	// Period is the start of the bucket
+	Period     string `json:"period"`
+	Requests   int    `json:"requests"`
+	Successful int    `json:"successful"`
+	Failed     int    `json:"failed"`
+	Credits    int    `json:"credits"`
+}
+
+// Start parses Period into a time.Time
+func (p *UsagePoint) Start() (time.Time, error) {
+	return parseTimestamp(p.Period)
+}
+
+// GetUsage gets API usage statistics for a period, split into a time series
+// when q.Granularity is set
+func (c *Client) GetUsage(ctx context.Context, q UsageQuery) (*UsageReport, error) {
+	switch q.Granularity {
+	case "", GranularityHour, GranularityDay, GranularityWeek, GranularityMonth:
+	default:
+		return nil, fmt.Errorf("unknown usage granularity %q (want hour, day, week or month)", q.Granularity)
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return nil, errors.New("usage query From must be before To")
+	}
+
+	params := url.Values{}
+	if !q.From.IsZero() {
+		params.Set("from", q.From.UTC().Format(time.RFC3339))
+	}
+	if !q.To.IsZero() {
+		params.Set("to", q.To.UTC().Format(time.RFC3339))
+	}
+	if q.Granularity != "" {
+		params.Set("granularity", string(q.Granularity))
+	}
+
+	endpoint := "/usage"
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	var result struct {
+		Data *UsageReport `json:"data"`
+	}
+
+	err := c.WithContext(ctx).request("GetUsage", "GET", endpoint, nil, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Data == nil {
+		return nil, errors.New("usage: empty response")
+	}
+
+	return result.Data, nil
+}
+
+// Attribution counts requests, failures and estimated credits per tag set with
+// WithTag, so usage can be charged back to the callers that caused it. Counts
+// are kept in hourly buckets for the lifetime of the Attribution.
+type Attribution struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	buckets map[time.Time]map[string]*TagUsage
+}
+
+// TagUsage is the usage attributed to one tag. Requests counts every HTTP
+// attempt, including retries, as the API does.
+type TagUsage struct {
+	Tag      string `json:"tag"`
+	Requests int    `json:"requests"`
+	Failed   int    `json:"failed"`
+	Credits  int    `json:"credits"`
+}
+
+// NewAttribution creates an empty attribution
+func NewAttribution() *Attribution {
+	return &Attribution{
+		now:     time.Now,
+		buckets: make(map[time.Time]map[string]*TagUsage),
+	}
+}
+
+// SetAttribution counts the client's requests and estimated credits per tag
+// in a. Pass nil to stop counting.
+func (c *Client) SetAttribution(a *Attribution) {
+	c.attribution = a
+}
+
+// Usage returns the usage per tag between from (inclusive) and to (exclusive),
+// sorted by tag; zero times leave the period open. Untagged calls are reported
+// under the empty tag. Counts are hourly, so from is rounded down and to up to
+// the hour: the hours containing either bound are included.
+func (a *Attribution) Usage(from, to time.Time) []TagUsage {
+	from = from.Truncate(time.Hour)
+	if hour := to.Truncate(time.Hour); hour.Before(to) {
+		to = hour.Add(time.Hour)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	totals := make(map[string]*TagUsage)
+	for hour, tags := range a.buckets {
+		if !from.IsZero() && hour.Before(from) || !to.IsZero() && !hour.Before(to) {
+			continue
+		}
+		for tag, usage := range tags {
+			total := totals[tag]
+			if total == nil {
+				total = &TagUsage{Tag: tag}
+				totals[tag] = total
+			}
+			total.Requests += usage.Requests
+			total.Failed += usage.Failed
+			total.Credits += usage.Credits
+		}
+	}
+
+	usage := make([]TagUsage, 0, len(totals))
+	for _, total := range totals {
+		usage = append(usage, *total)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Tag < usage[j].Tag })
+	return usage
+}
+
+// Reset discards all counts
+func (a *Attribution) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.buckets = make(map[time.Time]map[string]*TagUsage)
+}
+
+// add records usage against a tag in the current hour
+func (a *Attribution) add(tag string, requests, failed, credits int) {
+	hour := a.now().UTC().Truncate(time.Hour)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	tags := a.buckets[hour]
+	if tags == nil {
+		tags = make(map[string]*TagUsage)
+		a.buckets[hour] = tags
+	}
+	usage := tags[tag]
+	if usage == nil {
+		usage = &TagUsage{Tag: tag}
+		tags[tag] = usage
+	}
+	usage.Requests += requests
+	usage.Failed += failed
+	usage.Credits += credits
+}
+
+// attributionMiddleware counts each HTTP attempt against the tag of its context
+func attributionMiddleware(a *Attribution) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			tag := TagFromContext(req.Context())
+			resp, err := next.Do(req)
+			if err != nil || resp.StatusCode >= 400 {
+				a.add(tag, 1, 1, 0)
+			} else {
+				a.add(tag, 1, 0, 0)
+			}
+			return resp, err
+		})
+	}
+}
+
+// WriteUsageReport fetches the server's usage between from and to and writes a CSV report
+// splitting it across the tags counted by the client's Attribution, for
+// chargeback. Usage the server saw but the client did not, such as calls from
+// other processes sharing the API key, is reported as "(other)".
+func (c *Client) WriteUsageReport(ctx context.Context, w io.Writer, from, to time.Time) error {
+	if c.attribution == nil {
+		return errors.New("usage report needs an Attribution; call SetAttribution first")
+	}
+
+	// Snapshot first so the report's own request is left out
+	tags := c.attribution.Usage(from, to)
+	report, err := c.GetUsage(ctx, UsageQuery{From: from, To: to})
+	if err != nil {
+		return err
+	}
+	return WriteUsageCSV(w, report, tags)
+}
+
+// WriteUsageCSV writes server usage and local per-tag attribution as CSV with
+// the columns tag, requests, failed_requests, credits and credit_share. Each
+// tag gets a row, untagged calls are labelled "(untagged)", any usage not
+// attributed locally is labelled "(other)", and a final "total" row holds the
+// server totals.
+func WriteUsageCSV(w io.Writer, report *UsageReport, tags []TagUsage) error {
+	var requests, failed, credits int
+	for _, tag := range tags {
+		requests += tag.Requests
+		failed += tag.Failed
+		credits += tag.Credits
+	}
+
+	totalCredits := report.CreditsUsed
+	if credits > totalCredits {
+		totalCredits = credits
+	}
+	share := func(n int) string {
+		if totalCredits == 0 {
+			return "0.0000"
+		}
+		return strconv.FormatFloat(float64(n)/float64(totalCredits), 'f', 4, 64)
+	}
+	row := func(label string, requests, failed, credits int) []string {
+		return []string{label, strconv.Itoa(requests), strconv.Itoa(failed), strconv.Itoa(credits), share(credits)}
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"tag", "requests", "failed_requests", "credits", "credit_share"})
+	for _, tag := range tags {
+		label := tag.Tag
+		if label == "" {
+			label = "(untagged)"
+		}
+		cw.Write(row(label, tag.Requests, tag.Failed, tag.Credits))
+	}
+
+	other := [3]int{report.TotalRequests - requests, report.FailedRequests - failed, report.CreditsUsed - credits}
+	if other[0] > 0 || other[1] > 0 || other[2] > 0 {
+		for i := range other {
+			if other[i] < 0 {
+				other[i] = 0
+			}
+		}
+		cw.Write(row("(other)", other[0], other[1], other[2]))
+	}
+
+	cw.Write(row("total", report.TotalRequests, report.FailedRequests, report.CreditsUsed))
+	cw.Flush()
+	return cw.Error()
+}
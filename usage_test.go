@@ -0,0 +1,42 @@
+package emaillistchecker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAttributionUsageBounds(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	a := NewAttribution()
+	a.now = func() time.Time { return now }
+
+	now = start.Add(15 * time.Minute)
+	a.add("billing", 1, 0, 1)
+	now = start.Add(75 * time.Minute)
+	a.add("billing", 1, 1, 0)
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		requests int
+	}{
+		{"open", time.Time{}, time.Time{}, 2},
+		{"to within the first hour", start, start.Add(30 * time.Minute), 1},
+		{"to on the hour", start, start.Add(time.Hour), 1},
+		{"to within the second hour", start, start.Add(70 * time.Minute), 2},
+		{"from within the second hour", start.Add(90 * time.Minute), time.Time{}, 1},
+		{"before any usage", time.Time{}, start, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			for _, usage := range a.Usage(tt.from, tt.to) {
+				requests += usage.Requests
+			}
+			if requests != tt.requests {
+				t.Errorf("requests = %d, want %d", requests, tt.requests)
+			}
+		})
+	}
+}
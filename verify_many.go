@@ -0,0 +1,166 @@
+package emaillistchecker
+
+import (
+	"context"
+	"sync"
+)
+
+// VerifyManyOptions configures VerifyMany
+type VerifyManyOptions struct {
+	// Concurrency is the number of Verify calls in flight (default 5)
+	Concurrency int
+	// Timeout and SMTPCheck are passed to every Verify call
+	Timeout   *int
+	SMTPCheck bool
+	// OnResult, if set, is called in input order as soon as each result is available
+	OnResult func(VerifyManyResult)
+}
+
+// VerifyManyResult is the outcome for one input address of VerifyMany
+type VerifyManyResult struct {
+	Index  int
+	Email  string
+	Result *VerifyResponse
+	Err    error
+}
+
+// verifyTask is one unique address being verified by VerifyMany
+type verifyTask struct {
+	email  string
+	done   chan struct{}
+	result *VerifyResponse
+	err    error
+
+	// waiting counts the inputs not yet reported; guarded by the stream's mutex
+	waiting int
+}
+
+// VerifyMany verifies addresses concurrently with Verify. Duplicates (compared
+// case-insensitively, or by canonical form with SetCanonicalizer) are verified
+// once and reported under every input.
+// Results are returned in input order; addresses not started before ctx is
+// cancelled carry ctx.Err().
+func (c *Client) VerifyMany(ctx context.Context, emails []string, opts VerifyManyOptions) []VerifyManyResult {
+	input := make(chan string)
+	go func() {
+		defer close(input)
+		for _, email := range emails {
+			input <- email
+		}
+	}()
+
+	results := make([]VerifyManyResult, 0, len(emails))
+	onResult := opts.OnResult
+	opts.OnResult = func(r VerifyManyResult) {
+		results = append(results, r)
+		if onResult != nil {
+			onResult(r)
+		}
+	}
+	c.verifyStream(ctx, input, opts, false)
+
+	return results
+}
+
+// streamInput is one input address of VerifyStream and the task verifying it
+type streamInput struct {
+	index int
+	email string
+	key   string
+	task  *verifyTask
+}
+
+// VerifyStream is VerifyMany for addresses that arrive over time, such as
+// lines read from a pipe. Verification starts as soon as an address is
+// received and OnResult is called in input order as results become available.
+// It returns when emails is closed and every result has been reported; the
+// caller must keep receiving from or close emails after ctx is cancelled.
+//
+// A duplicate shares the verification of an earlier copy only while that copy
+// is still waiting to be reported, so memory stays bounded on endless input.
+// Set a cache with SetCache to reuse results for later duplicates.
+func (c *Client) VerifyStream(ctx context.Context, emails <-chan string, opts VerifyManyOptions) {
+	c.verifyStream(ctx, emails, opts, true)
+}
+
+// verifyStream implements VerifyStream. With forget set, a task is dropped
+// once every input waiting on it has been reported; otherwise tasks are kept
+// until the stream ends, so every duplicate is verified once.
+func (c *Client) verifyStream(ctx context.Context, emails <-chan string, opts VerifyManyOptions, forget bool) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 5
+	}
+
+	client := c.WithContext(ctx)
+	jobs := make(chan *verifyTask)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range jobs {
+				if err := ctx.Err(); err != nil {
+					task.err = err
+				} else {
+					task.result, task.err = client.Verify(task.email, opts.Timeout, opts.SMTPCheck)
+				}
+				close(task.done)
+			}
+		}()
+	}
+
+	// Dispatch unique addresses to the workers and queue every input in
+	// order; the queue bounds how far reading runs ahead of the results.
+	inputs := make(chan streamInput, opts.Concurrency)
+	var mu sync.Mutex
+	tasks := make(map[string]*verifyTask)
+	go func() {
+		defer close(inputs)
+		defer close(jobs)
+
+		index := 0
+		for email := range emails {
+			key := c.dedupeKey(email)
+			mu.Lock()
+			task, ok := tasks[key]
+			if !ok {
+				task = &verifyTask{email: email, done: make(chan struct{})}
+				tasks[key] = task
+			}
+			task.waiting++
+			mu.Unlock()
+
+			if !ok {
+				select {
+				case jobs <- task:
+				case <-ctx.Done():
+					task.err = ctx.Err()
+					close(task.done)
+				}
+			}
+			inputs <- streamInput{index: index, email: email, key: key, task: task}
+			index++
+		}
+	}()
+
+	for in := range inputs {
+		<-in.task.done
+		if opts.OnResult != nil {
+			opts.OnResult(VerifyManyResult{
+				Index:  in.index,
+				Email:  in.email,
+				Result: in.task.result,
+				Err:    in.task.err,
+			})
+		}
+
+		if forget {
+			mu.Lock()
+			if in.task.waiting--; in.task.waiting == 0 {
+				delete(tasks, in.key)
+			}
+			mu.Unlock()
+		}
+	}
+	wg.Wait()
+}
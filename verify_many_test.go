@@ -0,0 +1,67 @@
+package emaillistchecker_test
+
+import (
+	"context"
+	"testing"
+
+	emaillistchecker "github.com/Emaillistchecker-io/emaillistchecker-go"
+	"github.com/Emaillistchecker-io/emaillistchecker-go/emaillistcheckertest"
+)
+
+func TestVerifyManyDuplicates(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	emails := []string{"jane@example.com", "risky@example.com", "Jane@example.com", "jane@example.com"}
+	results := client.VerifyMany(context.Background(), emails, emaillistchecker.VerifyManyOptions{Concurrency: 1})
+	if len(results) != len(emails) {
+		t.Fatalf("got %d results, want %d", len(results), len(emails))
+	}
+	for i, r := range results {
+		if r.Index != i || r.Email != emails[i] || r.Err != nil {
+			t.Errorf("results[%d] = %+v", i, r)
+		}
+	}
+	if n := len(srv.Requests()); n != 2 {
+		t.Errorf("sent %d requests, want 2", n)
+	}
+}
+
+// streamTwice sends an address, waits for its result and sends it again
+func streamTwice(client *emaillistchecker.Client, email string) {
+	emails := make(chan string)
+	reported := make(chan struct{}, 2)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client.VerifyStream(context.Background(), emails, emaillistchecker.VerifyManyOptions{
+			OnResult: func(emaillistchecker.VerifyManyResult) { reported <- struct{}{} },
+		})
+	}()
+	emails <- email
+	<-reported
+	emails <- email
+	<-reported
+	close(emails)
+	<-done
+}
+
+func TestVerifyStreamForgetsReportedAddresses(t *testing.T) {
+	srv := emaillistcheckertest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	// A repeat after the first result was reported is verified again...
+	streamTwice(client, "jane@example.com")
+	if n := len(srv.Requests()); n != 2 {
+		t.Errorf("sent %d requests without a cache, want 2", n)
+	}
+
+	// ...unless a cache holds the result
+	client.SetCache(emaillistchecker.NewMemoryCache(0))
+	streamTwice(client, "john@example.com")
+	if n := len(srv.Requests()); n != 3 {
+		t.Errorf("sent %d requests in total, want 3", n)
+	}
+}